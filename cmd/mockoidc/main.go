@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"jcourse_go/internal/infrastructure/oidc/oidctest"
+)
+
+// mockoidc runs an identity provider that signs everyone in as the given user, for trying
+// external login locally. Point a provider in config.yaml at it:
+//
+//	auth_url: http://localhost:9000/authorize
+//	token_url: http://localhost:9000/token
+//	jwks_url: http://localhost:9000/jwks
+//	issuer: http://localhost:9000
+func main() {
+	var (
+		addr         string
+		issuer       string
+		clientID     string
+		clientSecret string
+		identity     oidctest.Identity
+	)
+	flag.StringVar(&addr, "addr", ":9000", "Address to listen on")
+	flag.StringVar(&issuer, "issuer", "http://localhost:9000", "Public base URL of the server")
+	flag.StringVar(&clientID, "client-id", "jcourse", "Accepted client ID")
+	flag.StringVar(&clientSecret, "client-secret", "secret", "Accepted client secret")
+	flag.StringVar(&identity.Subject, "subject", "student", "Subject of the signed in user")
+	flag.StringVar(&identity.Email, "email", "student@sjtu.edu.cn", "Email of the signed in user")
+	flag.BoolVar(&identity.EmailVerified, "email-verified", true, "Whether the email is verified")
+	flag.StringVar(&identity.Name, "name", "Student", "Name of the signed in user")
+	flag.Parse()
+
+	server := oidctest.New(clientID, clientSecret, identity)
+	server.Issuer = issuer
+
+	log.Printf("Mock OIDC provider %s listening on %s", issuer, addr)
+	if err := http.ListenAndServe(addr, server); err != nil {
+		log.Fatalf("Mock OIDC provider stopped: %v", err)
+	}
+}
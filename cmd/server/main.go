@@ -45,32 +45,44 @@ func loadConfiguration() *config.Config {
 	return cfg
 }
 
-func setupServiceContainer(cfg *config.Config, eventPublisher event.Publisher) *app.ServiceContainer {
-	serviceContainer, err := app.NewServiceContainer(*cfg, eventPublisher)
+func setupServiceContainer(cfg *config.Config, eventBus event.EventBusPublisher) *app.ServiceContainer {
+	serviceContainer, err := app.NewServiceContainer(*cfg, eventBus)
 	if err != nil {
 		log.Fatalf("Failed to initialize service container: %v", err)
 	}
 	return serviceContainer
 }
 
-func setupEventbus(cfg *config.Config, serviceContainer *app.ServiceContainer) *app.EventBusSetup {
-	eventBusSetup, err := app.SetupEventBus(*cfg, serviceContainer)
+func setupEventbus(cfg *config.Config) *app.EventBusSetup {
+	eventBusSetup, err := app.SetupEventBus(*cfg)
 	if err != nil {
 		log.Fatalf("Failed to setup eventbus: %v", err)
 	}
 	return eventBusSetup
 }
 
+func registerEventHandlers(eventBusSetup *app.EventBusSetup, serviceContainer *app.ServiceContainer) {
+	if err := eventBusSetup.RegisterHandlers(serviceContainer); err != nil {
+		log.Fatalf("Failed to register event handlers: %v", err)
+	}
+}
+
 func startBackgroundWorkers(ctx context.Context, cfg *config.Config, eventBusSetup *app.EventBusSetup, serviceContainer *app.ServiceContainer) {
 	if cfg.Event.Enabled {
 		log.Println("Starting background event handlers...")
 
-		// Start event bus worker (async event processing)
-		go func() {
-			if err := eventBusSetup.StartEventBus(); err != nil {
-				log.Printf("Failed to start event bus: %v", err)
-			}
-		}()
+		// Start event bus worker (async event processing) before the outbox relay publishes to it
+		if err := eventBusSetup.StartEventBus(); err != nil {
+			log.Printf("Failed to start event bus: %v", err)
+		}
+
+		// Start outbox relay worker (delivers committed events to the event bus)
+		outboxRelayWorker := task.NewOutboxRelayWorker(serviceContainer)
+		go outboxRelayWorker.Start(ctx)
+
+		// Start webhook delivery worker (posts queued events to subscribers)
+		webhookWorker := task.NewWebhookDeliveryWorker(serviceContainer)
+		go webhookWorker.Start(ctx)
 
 		// Start email worker
 		emailWorker := task.NewEmailWorker(serviceContainer)
@@ -80,25 +92,30 @@ func startBackgroundWorkers(ctx context.Context, cfg *config.Config, eventBusSet
 		statsWorker := task.NewStatisticsWorker(serviceContainer)
 		go statsWorker.Start(ctx)
 
-		// Start cleanup worker
-		cleanupWorker := task.NewCleanupWorker(serviceContainer)
-		go cleanupWorker.Start(ctx)
-
 		log.Println("Background workers started successfully")
 	}
+
+	// Start cleanup worker, which also anonymises deleted accounts and lifts expired suspensions
+	// whether or not events are enabled
+	cleanupWorker := task.NewCleanupWorker(serviceContainer)
+	go cleanupWorker.Start(ctx)
 }
 
-func setupHTTPServer(serviceContainer *app.ServiceContainer) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceContainer *app.ServiceContainer) *http.Server {
 	// Initialize Gin router
 	router := gin.New()
 
 	// Add middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
-	router.Use(web.CORSMiddleware())
+	allowedOrigins := cfg.AllowedOrigins
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = []string{cfg.SiteURL}
+	}
+	router.Use(web.CORSMiddleware(allowedOrigins))
 
 	// Register routes
-	web.RegisterRouter(router, serviceContainer)
+	web.RegisterRouter(router, serviceContainer, web.NewSessionCookie(cfg.Auth.Cookie))
 
 	// Add health check endpoint
 	router.GET(HealthCheckEndpoint, func(c *gin.Context) {
@@ -155,14 +172,19 @@ func main() {
 	// Load configuration
 	cfg := loadConfiguration()
 
-	// Initialize service container first
-	serviceContainer := setupServiceContainer(cfg, nil)
+	// Setup eventbus first so services can publish to it
+	eventBusSetup := setupEventbus(cfg)
+
+	// Initialize service container with the eventbus
+	serviceContainer := setupServiceContainer(cfg, eventBusSetup.EventBus)
 	defer serviceContainer.Close()
 
-	// Setup eventbus using service container
-	eventBusSetup := setupEventbus(cfg, serviceContainer)
+	// Shutdown runs before the container is closed so in-flight events can still reach the database
 	defer eventBusSetup.ShutdownEventBus()
 
+	// Subscribe handlers now that the services they depend on exist
+	registerEventHandlers(eventBusSetup, serviceContainer)
+
 	// Create context with cancellation for background tasks
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -175,7 +197,7 @@ func main() {
 	startBackgroundWorkers(ctx, cfg, eventBusSetup, serviceContainer)
 
 	// Setup HTTP server
-	server := setupHTTPServer(serviceContainer)
+	server := setupHTTPServer(cfg, serviceContainer)
 
 	// Start server in goroutine
 	go func() {
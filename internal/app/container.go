@@ -1,49 +1,92 @@
 package app
 
 import (
+	"fmt"
+
 	announcementquery "jcourse_go/internal/application/announcement/query"
 	"jcourse_go/internal/application/auth"
 	authcommand "jcourse_go/internal/application/auth/command"
 	authquery "jcourse_go/internal/application/auth/query"
+	eventcommand "jcourse_go/internal/application/event/command"
+	eventquery "jcourse_go/internal/application/event/query"
+	eventservice "jcourse_go/internal/application/event/service"
 	pointcommand "jcourse_go/internal/application/point/command"
 	pointquery "jcourse_go/internal/application/point/query"
 	reviewcommand "jcourse_go/internal/application/review/command"
 	reviewquery "jcourse_go/internal/application/review/query"
+	reviewstream "jcourse_go/internal/application/review/stream"
 	statisticsquery "jcourse_go/internal/application/statistics/query"
 	"jcourse_go/internal/application/statistics/service"
+	webhookcommand "jcourse_go/internal/application/webhook/command"
+	webhookquery "jcourse_go/internal/application/webhook/query"
+	webhookservice "jcourse_go/internal/application/webhook/service"
 	"jcourse_go/internal/config"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
 	"jcourse_go/internal/domain/email"
 	"jcourse_go/internal/domain/event"
 	"jcourse_go/internal/domain/permission"
 	"jcourse_go/internal/domain/point"
 	"jcourse_go/internal/infrastructure/database"
 	emailimpl "jcourse_go/internal/infrastructure/email"
+	"jcourse_go/internal/infrastructure/eventbus"
+	"jcourse_go/internal/infrastructure/jwt"
+	"jcourse_go/internal/infrastructure/oidc"
 	"jcourse_go/internal/infrastructure/repository"
+	webhookimpl "jcourse_go/internal/infrastructure/webhook"
 	"jcourse_go/pkg/password"
 
 	"gorm.io/gorm"
 )
 
 type ServiceContainer struct {
-	DB *gorm.DB
-
-	AuthCommandService       authcommand.AuthCommandService
-	AuthQueryService         authquery.AuthQueryService
-	CodeService              auth.VerificationCodeService
-	CourseCommandService     reviewcommand.CourseCommandService
-	CourseQueryService       reviewquery.CourseQueryService
-	ReviewCommandService     reviewcommand.ReviewCommandService
-	ReviewQueryService       reviewquery.ReviewQueryService
-	PointCommandService      pointcommand.PointCommandService
-	PointQueryService        pointquery.UserPointQueryService
-	UserCommandService       authcommand.UserCommandService
-	UserQueryService         authquery.UserQueryService
-	AnnouncementQueryService announcementquery.AnnouncementQueryService
-	StatisticsQueryService   statisticsquery.StatisticsQueryService
-	DailyStatisticsService   service.DailyStatisticsService
+	DB         *gorm.DB
+	Transactor common.Transactor
+
+	// ProcessedEventRepository is the ledger that makes the event handlers idempotent
+	ProcessedEventRepository event.ProcessedEventRepository
+
+	AuthCommandService        authcommand.AuthCommandService
+	AuthQueryService          authquery.AuthQueryService
+	CodeService               auth.VerificationCodeService
+	CourseCommandService      reviewcommand.CourseCommandService
+	CourseQueryService        reviewquery.CourseQueryService
+	ReviewCommandService      reviewcommand.ReviewCommandService
+	ReviewQueryService        reviewquery.ReviewQueryService
+	ReviewStreamService       reviewstream.ReviewStreamService
+	PointCommandService       pointcommand.PointCommandService
+	PointQueryService         pointquery.UserPointQueryService
+	UserCommandService        authcommand.UserCommandService
+	UserQueryService          authquery.UserQueryService
+	AccountCommandService     authcommand.AccountCommandService
+	AccountExportQueryService authquery.AccountExportQueryService
+	SessionCommandService     authcommand.SessionCommandService
+	SessionQueryService       authquery.SessionQueryService
+	OAuthCommandService       authcommand.OAuthCommandService
+	TwoFactorCommandService   authcommand.TwoFactorCommandService
+	APITokenCommandService    authcommand.APITokenCommandService
+	APITokenQueryService      authquery.APITokenQueryService
+	InviteCommandService      authcommand.InviteCommandService
+	InviteQueryService        authquery.InviteQueryService
+	AdminUserCommandService   authcommand.AdminUserCommandService
+	AdminUserQueryService     authquery.AdminUserQueryService
+	AnnouncementQueryService  announcementquery.AnnouncementQueryService
+	StatisticsQueryService    statisticsquery.StatisticsQueryService
+	DailyStatisticsService    service.DailyStatisticsService
+	OutboxRelayService        eventservice.OutboxRelayService
+	DeadLetterCommandService  eventcommand.DeadLetterCommandService
+	DeadLetterQueryService    eventquery.DeadLetterQueryService
+	EventStoreService         eventservice.EventStoreService
+	EventStoreQueryService    eventquery.EventStoreQueryService
+	WebhookCommandService     webhookcommand.WebhookCommandService
+	WebhookQueryService       webhookquery.WebhookQueryService
+	WebhookDeliveryService    webhookservice.WebhookDeliveryService
+
+	// TokenCommandService is only set in token mode
+	TokenCommandService authcommand.TokenCommandService
 }
 
-func NewServiceContainer(conf config.Config, eventPublisher event.Publisher) (*ServiceContainer, error) {
+func NewServiceContainer(conf config.Config, eventBus event.EventBusPublisher) (*ServiceContainer, error) {
 	db, err := database.NewDatabase(conf.DB)
 	if err != nil {
 		return nil, err
@@ -56,13 +99,30 @@ func NewServiceContainer(conf config.Config, eventPublisher event.Publisher) (*S
 
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	challengeRepo := repository.NewLoginChallengeRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	failureRepo := repository.NewFailureCounterRepository(db)
+	inviteRepo := repository.NewInviteCodeRepository(db)
+	emailChangeRepo := repository.NewEmailChangeRepository(db)
 	reviewRepo := repository.NewReviewRepository(db)
 	courseRepo := repository.NewCourseRepository(db)
 	pointRepo := repository.NewUserPointRepository(db)
 	announcementRepo := repository.NewAnnouncementRepository(db)
 	statisticsRepo := repository.NewStatisticsRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	deadLetterRepo := repository.NewDeadLetterRepository(db)
+	eventStoreRepo := repository.NewEventStoreRepository(db)
+	webhookSubscriptionRepo := repository.NewWebhookSubscriptionRepository(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db)
+
+	transactor := database.NewTransactor(db)
 
-	hasher := password.NewHasher()
+	hasher := password.NewHasher(password.Params{
+		Memory:      conf.Password.Memory,
+		Iterations:  conf.Password.Iterations,
+		Parallelism: conf.Password.Parallelism,
+	})
 	permissionService := permission.NewPermissionService(userRepo)
 
 	codeRepo := repository.NewCodeRepository(db)
@@ -72,30 +132,122 @@ func NewServiceContainer(conf config.Config, eventPublisher event.Publisher) (*S
 	if conf.SMTP.Host != "" && conf.SMTP.Username != "" && conf.SMTP.Password != "" {
 		smtpSender := emailimpl.NewSMTPSender(conf.SMTP)
 		emailTemplate := emailimpl.NewVerificationCodeTemplate()
-		emailService = email.NewEmailServiceImpl(smtpSender, emailTemplate)
+		emailChangedTemplate := emailimpl.NewEmailChangedTemplate()
+		emailService = email.NewEmailServiceImpl(smtpSender, emailTemplate, emailChangedTemplate)
 	} else {
 		emailService = email.NewEmailService()
 	}
 
 	codeService := auth.NewVerificationCodeService(emailService, codeRepo)
 
+	registrationPolicy := domainauth.RegistrationPolicy{
+		AllowedDomains: conf.Registration.AllowedDomains,
+		BlockedDomains: conf.Registration.BlockedDomains,
+		InviteOnly:     conf.Registration.InviteOnly,
+	}
+
+	// Providers without client credentials are left disabled
+	var identityProviders []auth.IdentityProvider
+	for _, providerConf := range conf.OAuth.Providers {
+		if providerConf.ClientID == "" {
+			continue
+		}
+		identityProviders = append(identityProviders, oidc.NewProvider(providerConf, nil))
+	}
+
+	// Services write their events to the outbox; the relay delivers them to the eventbus
+	var outboxPublisher event.Publisher = event.NopPublisher{}
+	var outboxRelayService eventservice.OutboxRelayService
+	if eventBus != nil {
+		outboxPublisher = eventbus.NewOutboxPublisher(outboxRepo)
+		// The in-memory bus only queues published events, so the relay waits for their handlers
+		// before marking the messages sent
+		var relayPublisher event.Publisher = eventBus
+		if memoryBus, ok := eventBus.(*eventbus.InMemoryEventBus); ok {
+			relayPublisher = eventbus.NewDispatchPublisher(memoryBus)
+		}
+		outboxRelayService = eventservice.NewOutboxRelayService(outboxRepo, transactor, relayPublisher)
+	}
+
+	oauthCommandService := authcommand.NewOAuthCommandService(
+		auth.NewIdentityProviders(identityProviders...),
+		userRepo,
+		repository.NewExternalIdentityRepository(db),
+		repository.NewOAuthStateRepository(db),
+		sessionRepo,
+		twoFactorRepo,
+		challengeRepo,
+		registrationPolicy,
+		transactor,
+		outboxPublisher,
+	)
+
+	// Sessions stay the default; token mode hands out signed access tokens instead
+	var tokenCommandService authcommand.TokenCommandService
+	switch conf.Auth.Mode {
+	case "", config.AuthModeSession:
+	case config.AuthModeToken:
+		keys := make([]jwt.Key, 0, len(conf.Auth.SigningKeys))
+		for _, key := range conf.Auth.SigningKeys {
+			keys = append(keys, jwt.Key{ID: key.ID, Algorithm: key.Algorithm, Secret: key.Secret})
+		}
+		signer, err := jwt.NewSigner(keys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth config: %w", err)
+		}
+		tokenCommandService = authcommand.NewTokenCommandService(
+			userRepo, sessionRepo, repository.NewRefreshTokenRepository(db), twoFactorRepo, signer, transactor,
+		)
+	default:
+		return nil, fmt.Errorf("invalid auth config: unknown mode %q", conf.Auth.Mode)
+	}
+
 	container := &ServiceContainer{
-		DB: db,
-
-		AuthCommandService:       authcommand.NewAuthCommandService(userRepo, hasher, sessionRepo, codeService),
-		AuthQueryService:         authquery.NewAuthQueryService(userRepo, sessionRepo),
-		CodeService:              codeService,
-		CourseCommandService:     reviewcommand.NewCourseCommandService(courseRepo),
-		CourseQueryService:       reviewquery.NewCourseQueryService(courseRepo, reviewRepo),
-		ReviewCommandService:     reviewcommand.NewReviewCommandService(reviewRepo, courseRepo, permissionService, eventPublisher),
-		ReviewQueryService:       reviewquery.NewReviewQueryService(reviewRepo, courseRepo),
-		PointCommandService:      pointcommand.NewPointCommandService(pointRepo),
-		PointQueryService:        pointquery.NewUserPointQueryService(pointRepo),
-		UserCommandService:       authcommand.NewUserCommandService(userRepo),
-		UserQueryService:         authquery.NewUserQueryService(userRepo),
-		AnnouncementQueryService: announcementquery.NewAnnouncementQueryService(announcementRepo),
-		StatisticsQueryService:   statisticsquery.NewStatisticsQueryService(statisticsRepo),
-		DailyStatisticsService:   service.NewDailyStatisticsService(statisticsRepo),
+		DB:         db,
+		Transactor: transactor,
+
+		ProcessedEventRepository: repository.NewProcessedEventRepository(db),
+
+		AuthCommandService:        authcommand.NewAuthCommandService(userRepo, hasher, sessionRepo, apiTokenRepo, twoFactorRepo, challengeRepo, failureRepo, inviteRepo, registrationPolicy, codeService, transactor, outboxPublisher),
+		AuthQueryService:          authquery.NewAuthQueryService(userRepo, sessionRepo),
+		CodeService:               codeService,
+		CourseCommandService:      reviewcommand.NewCourseCommandService(courseRepo, transactor, outboxPublisher),
+		CourseQueryService:        reviewquery.NewCourseQueryService(courseRepo, reviewRepo),
+		ReviewCommandService:      reviewcommand.NewReviewCommandService(reviewRepo, courseRepo, permissionService, transactor, outboxPublisher),
+		ReviewQueryService:        reviewquery.NewReviewQueryService(reviewRepo, courseRepo),
+		ReviewStreamService:       reviewstream.NewReviewStreamService(reviewRepo),
+		PointCommandService:       pointcommand.NewPointCommandService(pointRepo),
+		PointQueryService:         pointquery.NewUserPointQueryService(pointRepo),
+		UserCommandService:        authcommand.NewUserCommandService(userRepo, sessionRepo, emailChangeRepo, codeService, emailService, registrationPolicy, transactor, conf.SiteURL),
+		UserQueryService:          authquery.NewUserQueryService(userRepo),
+		AccountCommandService:     authcommand.NewAccountCommandService(userRepo),
+		AccountExportQueryService: authquery.NewAccountExportQueryService(userRepo, reviewRepo, courseRepo, pointRepo),
+		SessionCommandService:     authcommand.NewSessionCommandService(userRepo, sessionRepo, twoFactorRepo),
+		TokenCommandService:       tokenCommandService,
+		SessionQueryService:       authquery.NewSessionQueryService(sessionRepo),
+		OAuthCommandService:       oauthCommandService,
+		TwoFactorCommandService:   authcommand.NewTwoFactorCommandService(userRepo, twoFactorRepo, challengeRepo, sessionRepo, failureRepo, transactor),
+		APITokenCommandService:    authcommand.NewAPITokenCommandService(userRepo, apiTokenRepo, twoFactorRepo),
+		APITokenQueryService:      authquery.NewAPITokenQueryService(apiTokenRepo),
+		InviteCommandService:      authcommand.NewInviteCommandService(inviteRepo),
+		InviteQueryService:        authquery.NewInviteQueryService(inviteRepo),
+		AdminUserCommandService:   authcommand.NewAdminUserCommandService(userRepo, sessionRepo, transactor),
+		AdminUserQueryService:     authquery.NewAdminUserQueryService(userRepo, reviewRepo, pointRepo),
+		AnnouncementQueryService:  announcementquery.NewAnnouncementQueryService(announcementRepo),
+		StatisticsQueryService:    statisticsquery.NewStatisticsQueryService(statisticsRepo),
+		DailyStatisticsService:    service.NewDailyStatisticsService(statisticsRepo),
+		OutboxRelayService:        outboxRelayService,
+		DeadLetterCommandService:  eventcommand.NewDeadLetterCommandService(deadLetterRepo, eventBus),
+		DeadLetterQueryService:    eventquery.NewDeadLetterQueryService(deadLetterRepo),
+		EventStoreService:         eventservice.NewEventStoreService(eventStoreRepo),
+		EventStoreQueryService:    eventquery.NewEventStoreQueryService(eventStoreRepo),
+		WebhookCommandService:     webhookcommand.NewWebhookCommandService(webhookSubscriptionRepo),
+		WebhookQueryService:       webhookquery.NewWebhookQueryService(webhookSubscriptionRepo, webhookDeliveryRepo),
+		WebhookDeliveryService: webhookservice.NewWebhookDeliveryService(
+			webhookSubscriptionRepo,
+			webhookDeliveryRepo,
+			webhookimpl.NewHTTPSender(webhookimpl.DefaultTimeout),
+		),
 	}
 
 	return container, nil
@@ -1,18 +1,28 @@
 package app
 
 import (
+	"fmt"
+
+	"gorm.io/gorm"
+
 	"jcourse_go/internal/config"
 	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/eventbus"
+	"jcourse_go/internal/interface/handler"
 )
 
 // EventBusSetup holds the eventbus and its configuration
 type EventBusSetup struct {
 	EventBus event.EventBusPublisher
 	Enabled  bool
+
+	// db is the connection pool owned by the PostgreSQL event bus
+	db *gorm.DB
 }
 
 // SetupEventBus creates and configures the eventbus, but doesn't start it
-func SetupEventBus(conf config.Config, serviceContainer *ServiceContainer) (*EventBusSetup, error) {
+func SetupEventBus(conf config.Config) (*EventBusSetup, error) {
 	if !conf.Event.Enabled {
 		return &EventBusSetup{
 			EventBus: nil,
@@ -20,12 +30,43 @@ func SetupEventBus(conf config.Config, serviceContainer *ServiceContainer) (*Eve
 		}, nil
 	}
 
-	// For now, return a disabled eventbus since we removed asynq
-	// The domain interfaces are preserved for future implementation
-	return &EventBusSetup{
-		EventBus: nil,
-		Enabled:  false,
-	}, nil
+	switch conf.Event.Driver {
+	case "", config.EventDriverMemory:
+		return &EventBusSetup{
+			EventBus: eventbus.NewInMemoryEventBus(conf.Event),
+			Enabled:  true,
+		}, nil
+	case config.EventDriverPostgres:
+		db, err := database.NewDatabase(conf.DB)
+		if err != nil {
+			return nil, err
+		}
+		return &EventBusSetup{
+			EventBus: eventbus.NewPostgresEventBus(db, conf.DB.DSN, conf.Event),
+			Enabled:  true,
+			db:       db,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", conf.Event.Driver)
+	}
+}
+
+// RegisterHandlers subscribes the event handlers backed by the service container
+func (e *EventBusSetup) RegisterHandlers(serviceContainer *ServiceContainer) error {
+	if e.EventBus == nil {
+		return nil
+	}
+	e.EventBus.SetDeadLetterSink(serviceContainer.DeadLetterCommandService)
+	return handler.RegisterEventHandlers(
+		e.EventBus,
+		serviceContainer.ProcessedEventRepository,
+		serviceContainer.Transactor,
+		serviceContainer.EventStoreService,
+		serviceContainer.PointCommandService,
+		serviceContainer.DailyStatisticsService,
+		serviceContainer.WebhookDeliveryService,
+		serviceContainer.ReviewStreamService,
+	)
 }
 
 // StartEventBus starts the eventbus worker
@@ -36,11 +77,17 @@ func (e *EventBusSetup) StartEventBus() error {
 	return nil
 }
 
-// ShutdownEventBus shuts down the eventbus
+// ShutdownEventBus shuts down the eventbus, draining in-flight events
 func (e *EventBusSetup) ShutdownEventBus() {
 	if e.EventBus != nil {
 		e.EventBus.Shutdown()
 	}
+	if e.db != nil {
+		sqlDB, _ := e.db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}
 }
 
 // GetPublisher returns the event publisher for services
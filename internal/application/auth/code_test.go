@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -75,7 +76,7 @@ func TestVerificationCodeService_SendCode(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			service := tt.setupMocks()
-			err := service.SendCode(context.Background(), "test@example.com")
+			err := service.SendCode(context.Background(), "test@example.com", auth.CodePurposeRegister)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
@@ -86,13 +87,14 @@ func TestVerificationCodeService_Verify(t *testing.T) {
 		name          string
 		setupMocks    func() *verificationCodeService
 		inputCode     string
+		purpose       auth.CodePurpose
 		expectedError error
 	}{
 		{
 			name: "success",
 			setupMocks: func() *verificationCodeService {
 				mockRepo := &MockCodeRepository{
-					GetCode: &auth.VerificationCode{Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
+					GetCode: &auth.VerificationCode{Purpose: auth.CodePurposeRegister, Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
 				}
 				return &verificationCodeService{
 					email:       nil,
@@ -104,13 +106,14 @@ func TestVerificationCodeService_Verify(t *testing.T) {
 				}
 			},
 			inputCode:     "123456",
+			purpose:       auth.CodePurposeRegister,
 			expectedError: nil,
 		},
 		{
 			name: "code doesn't match",
 			setupMocks: func() *verificationCodeService {
 				mockRepo := &MockCodeRepository{
-					GetCode: &auth.VerificationCode{Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
+					GetCode: &auth.VerificationCode{Purpose: auth.CodePurposeRegister, Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
 				}
 				return &verificationCodeService{
 					email:       nil,
@@ -122,13 +125,14 @@ func TestVerificationCodeService_Verify(t *testing.T) {
 				}
 			},
 			inputCode:     "654321",
+			purpose:       auth.CodePurposeRegister,
 			expectedError: apperror.ErrWrongInput,
 		},
 		{
 			name: "code expired",
 			setupMocks: func() *verificationCodeService {
 				mockRepo := &MockCodeRepository{
-					GetCode: &auth.VerificationCode{Code: "123456", ExpiresAt: time.Now().Add(-1 * time.Minute)},
+					GetCode: &auth.VerificationCode{Purpose: auth.CodePurposeRegister, Code: "123456", ExpiresAt: time.Now().Add(-1 * time.Minute)},
 				}
 				return &verificationCodeService{
 					email:       nil,
@@ -140,14 +144,89 @@ func TestVerificationCodeService_Verify(t *testing.T) {
 				}
 			},
 			inputCode:     "123456",
+			purpose:       auth.CodePurposeRegister,
 			expectedError: apperror.ErrExpired,
 		},
+		{
+			name: "code sent for another purpose",
+			setupMocks: func() *verificationCodeService {
+				mockRepo := &MockCodeRepository{
+					GetCode: &auth.VerificationCode{Purpose: auth.CodePurposeRegister, Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
+				}
+				return &verificationCodeService{
+					email:       nil,
+					codeRepo:    mockRepo,
+					codeLength:  6,
+					codeCharset: "0123456789",
+					ttl:         10 * time.Minute,
+					interval:    1 * time.Minute,
+				}
+			},
+			inputCode:     "123456",
+			purpose:       auth.CodePurposeResetPassword,
+			expectedError: apperror.ErrWrongInput,
+		},
+		{
+			name: "no code sent",
+			setupMocks: func() *verificationCodeService {
+				return &verificationCodeService{
+					email:       nil,
+					codeRepo:    &MockCodeRepository{},
+					codeLength:  6,
+					codeCharset: "0123456789",
+					ttl:         10 * time.Minute,
+					interval:    1 * time.Minute,
+				}
+			},
+			inputCode:     "123456",
+			purpose:       auth.CodePurposeResetPassword,
+			expectedError: apperror.ErrWrongInput,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			service := tt.setupMocks()
-			err := service.Verify(context.Background(), tt.inputCode, "test@example.com")
+			err := service.Verify(context.Background(), tt.inputCode, "test@example.com", tt.purpose)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
+
+func TestVerificationCodeService_VerifyInvalidatesAfterMaxAttempts(t *testing.T) {
+	mockRepo := &MockCodeRepository{
+		GetCode: &auth.VerificationCode{Purpose: auth.CodePurposeRegister, Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
+	}
+	service := &verificationCodeService{codeRepo: mockRepo}
+
+	for i := 1; i < auth.MaxCodeAttempts; i++ {
+		err := service.Verify(context.Background(), "000000", "user@example.com", auth.CodePurposeRegister)
+		assert.ErrorIs(t, err, apperror.ErrWrongInput)
+		assert.Equal(t, i, mockRepo.GetCode.Attempts)
+		assert.False(t, mockRepo.Deleted)
+	}
+
+	err := service.Verify(context.Background(), "000000", "user@example.com", auth.CodePurposeRegister)
+
+	assert.ErrorIs(t, err, apperror.ErrWrongInput)
+	assert.True(t, mockRepo.Deleted)
+}
+
+func TestVerificationCodeService_VerifyCountsConcurrentGuesses(t *testing.T) {
+	mockRepo := &MockCodeRepository{
+		GetCode: &auth.VerificationCode{Purpose: auth.CodePurposeRegister, Code: "123456", ExpiresAt: time.Now().Add(5 * time.Minute)},
+	}
+	service := &verificationCodeService{codeRepo: mockRepo}
+
+	var wg sync.WaitGroup
+	for i := 0; i < auth.MaxCodeAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = service.Verify(context.Background(), "000000", "user@example.com", auth.CodePurposeRegister)
+		}()
+	}
+	wg.Wait()
+
+	// Guesses sent at once are not lost, so they still use the code up
+	assert.True(t, mockRepo.Deleted)
+}
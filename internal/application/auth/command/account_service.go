@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type AccountCommandService interface {
+	// RequestDeletion schedules the account of the current user for anonymisation after the grace period
+	RequestDeletion(commonCtx *common.CommonContext) (*viewobject.AccountDeletionVO, error)
+	CancelDeletion(commonCtx *common.CommonContext) error
+	// AnonymizeDue anonymises the accounts whose grace period is over and returns how many were anonymised
+	AnonymizeDue(ctx context.Context) (int, error)
+}
+
+type accountCommandService struct {
+	userRepo domainauth.UserRepository
+}
+
+func NewAccountCommandService(userRepo domainauth.UserRepository) AccountCommandService {
+	return &accountCommandService{
+		userRepo: userRepo,
+	}
+}
+
+func (s *accountCommandService) getUser(ctx context.Context, userID int, operation string) (*domainauth.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", operation).WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+	return user, nil
+}
+
+func (s *accountCommandService) RequestDeletion(commonCtx *common.CommonContext) (*viewobject.AccountDeletionVO, error) {
+	userID := commonCtx.User.UserID
+	user, err := s.getUser(commonCtx.Ctx, userID, "request_account_deletion")
+	if err != nil {
+		return nil, err
+	}
+
+	// Asking again keeps the original schedule
+	if !user.IsDeletionScheduled() {
+		user.ScheduleDeletion(time.Now())
+		if err := s.userRepo.Update(commonCtx.Ctx, user); err != nil {
+			return nil, apperror.WrapDB(err).WithMetadata("operation", "request_account_deletion").WithMetadata("user_id", userID)
+		}
+	}
+
+	return &viewobject.AccountDeletionVO{ScheduledAt: *user.DeletionScheduledAt}, nil
+}
+
+func (s *accountCommandService) CancelDeletion(commonCtx *common.CommonContext) error {
+	userID := commonCtx.User.UserID
+	user, err := s.getUser(commonCtx.Ctx, userID, "cancel_account_deletion")
+	if err != nil {
+		return err
+	}
+	if !user.IsDeletionScheduled() {
+		return apperror.ErrWrongInput.WithUserMessage("Account deletion was not requested")
+	}
+
+	user.CancelDeletion(time.Now())
+	if err := s.userRepo.Update(commonCtx.Ctx, user); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "cancel_account_deletion").WithMetadata("user_id", userID)
+	}
+	return nil
+}
+
+func (s *accountCommandService) AnonymizeDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	users, err := s.userRepo.FindDeletionDue(ctx, now)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "find_accounts_due_for_deletion")
+	}
+
+	anonymized := 0
+	for _, user := range users {
+		user.Anonymize(now)
+		if err := s.userRepo.Anonymize(ctx, &user); err != nil {
+			// The account is retried on the next run
+			log.Printf("Failed to anonymise user %d: %v", user.ID, err)
+			continue
+		}
+		anonymized++
+	}
+	return anonymized, nil
+}
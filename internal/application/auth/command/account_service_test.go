@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+func accountContext(userID int) *common.CommonContext {
+	return &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: userID, Role: common.RoleUser}}
+}
+
+func TestAccountCommandService_RequestAndCancelDeletion(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	service := NewAccountCommandService(users)
+
+	deletion, err := service.RequestDeletion(accountContext(1))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(domainauth.AccountDeletionGracePeriod), deletion.ScheduledAt, time.Minute)
+
+	// Asking again does not push the deletion back
+	again, err := service.RequestDeletion(accountContext(1))
+	assert.NoError(t, err)
+	assert.Equal(t, deletion.ScheduledAt, again.ScheduledAt)
+
+	assert.NoError(t, service.CancelDeletion(accountContext(1)))
+	assert.Nil(t, users.Users[1].DeletionScheduledAt)
+
+	assertAppErrorCode(t, apperror.ErrWrongInput, service.CancelDeletion(accountContext(1)))
+}
+
+func TestAccountCommandService_AnonymizeDue(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	users := NewMockUserRepository(
+		domainauth.User{ID: 1, Username: "alice", Email: "alice@example.com", Password: "hash", DeletionScheduledAt: &past},
+		domainauth.User{ID: 2, Username: "bob", Email: "bob@example.com", Password: "hash", DeletionScheduledAt: &future},
+		domainauth.User{ID: 3, Username: "carol", Email: "carol@example.com", Password: "hash"},
+	)
+	service := NewAccountCommandService(users)
+
+	anonymized, err := service.AnonymizeDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, anonymized)
+	alice := users.Users[1]
+	assert.NotContains(t, alice.Username, "alice")
+	assert.NotContains(t, alice.Email, "alice")
+	assert.Empty(t, alice.Password)
+	assert.NotNil(t, alice.DeletedAt)
+	assert.Nil(t, alice.DeletionScheduledAt)
+	assert.Nil(t, users.Users[2].DeletedAt)
+	assert.Nil(t, users.Users[3].DeletedAt)
+
+	// The reviews of the anonymised account no longer name anyone
+	assert.Equal(t, viewobject.AnonymousUserVO, viewobject.NewUserInReviewVO(alice))
+	assert.Equal(t, viewobject.UserInReviewVO{ID: 2, Name: "bob"}, viewobject.NewUserInReviewVO(users.Users[2]))
+}
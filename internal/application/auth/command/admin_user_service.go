@@ -0,0 +1,145 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+// MaxSuspensionReasonLength matches the column the reason is stored in
+const MaxSuspensionReasonLength = 255
+
+type AdminUserCommandService interface {
+	// SuspendUser locks the user out and revokes every session of the user at once
+	SuspendUser(commonCtx *common.CommonContext, userID int, cmd domainauth.SuspendUserCommand) error
+	UnsuspendUser(commonCtx *common.CommonContext, userID int) error
+	// ChangeRole sets the role of the user and revokes every session of the user, as with SuspendUser
+	ChangeRole(commonCtx *common.CommonContext, userID int, cmd domainauth.ChangeRoleCommand) error
+	// LiftExpiredSuspensions ends the timed suspensions that are over and returns how many were lifted
+	LiftExpiredSuspensions(ctx context.Context) (int64, error)
+}
+
+type adminUserCommandService struct {
+	userRepo    domainauth.UserRepository
+	sessionRepo domainauth.SessionRepository
+	transactor  common.Transactor
+}
+
+func NewAdminUserCommandService(
+	userRepo domainauth.UserRepository,
+	sessionRepo domainauth.SessionRepository,
+	transactor common.Transactor,
+) AdminUserCommandService {
+	return &adminUserCommandService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		transactor:  transactor,
+	}
+}
+
+// getTarget returns the user an admin acts on; admins cannot act on their own account,
+// so they cannot lock themselves out by accident
+func (s *adminUserCommandService) getTarget(commonCtx *common.CommonContext, userID int, operation string) (*domainauth.User, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can manage users")
+	}
+	if commonCtx.User.UserID == userID {
+		return nil, apperror.ErrWrongInput.WithUserMessage("You cannot change your own account here")
+	}
+
+	user, err := s.userRepo.GetByID(commonCtx.Ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", operation).WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+	return user, nil
+}
+
+func (s *adminUserCommandService) SuspendUser(commonCtx *common.CommonContext, userID int, cmd domainauth.SuspendUserCommand) error {
+	if cmd.Reason == "" || len(cmd.Reason) > MaxSuspensionReasonLength {
+		return apperror.ErrValidation.WithMessage("reason is required and must be at most 255 bytes")
+	}
+	if cmd.ExpiresInDays < 0 {
+		return apperror.ErrValidation.WithMessage("expires_in_days must not be negative")
+	}
+
+	user, err := s.getTarget(commonCtx, userID, "suspend_user")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var until *time.Time
+	if cmd.ExpiresInDays > 0 {
+		t := now.AddDate(0, 0, cmd.ExpiresInDays)
+		until = &t
+	}
+	user.Suspend(cmd.Reason, until, now)
+
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "suspend_user").WithMetadata("user_id", userID)
+		}
+		if _, err := s.sessionRepo.DeleteByUser(ctx, userID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "suspend_user").WithMetadata("user_id", userID)
+		}
+		return nil
+	})
+}
+
+func (s *adminUserCommandService) UnsuspendUser(commonCtx *common.CommonContext, userID int) error {
+	user, err := s.getTarget(commonCtx, userID, "unsuspend_user")
+	if err != nil {
+		return err
+	}
+	if !user.IsSuspended() {
+		return apperror.ErrWrongInput.WithUserMessage("User is not suspended")
+	}
+
+	user.Unsuspend(time.Now())
+	if err := s.userRepo.Update(commonCtx.Ctx, user); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "unsuspend_user").WithMetadata("user_id", userID)
+	}
+	return nil
+}
+
+func (s *adminUserCommandService) ChangeRole(commonCtx *common.CommonContext, userID int, cmd domainauth.ChangeRoleCommand) error {
+	if !cmd.Role.IsValid() {
+		return apperror.ErrValidation.WithMessage("unknown role").WithMetadata("role", cmd.Role)
+	}
+
+	user, err := s.getTarget(commonCtx, userID, "change_role")
+	if err != nil {
+		return err
+	}
+
+	if user.Role == cmd.Role {
+		return nil
+	}
+	user.ChangeRole(cmd.Role, time.Now())
+
+	// Sessions read the role on every request, but access tokens carry it until they expire.
+	// Revoking the sessions also ends their refresh tokens, so no new access token has the old role.
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "change_role").WithMetadata("user_id", userID)
+		}
+		if _, err := s.sessionRepo.DeleteByUser(ctx, userID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "change_role").WithMetadata("user_id", userID)
+		}
+		return nil
+	})
+}
+
+func (s *adminUserCommandService) LiftExpiredSuspensions(ctx context.Context) (int64, error) {
+	lifted, err := s.userRepo.LiftExpiredSuspensions(ctx, time.Now())
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "lift_expired_suspensions")
+	}
+	return lifted, nil
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+func adminContext(userID int) *common.CommonContext {
+	return &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: userID, Role: common.RoleAdmin}}
+}
+
+func newTestAdminUserService() (AdminUserCommandService, *MockUserRepository, *MockSessionRepository) {
+	users := NewMockUserRepository(
+		domainauth.User{ID: 1, Email: "admin@example.com", Role: common.RoleAdmin},
+		domainauth.User{ID: 2, Email: "user@example.com", Role: common.RoleUser},
+	)
+	sessions := NewMockSessionRepository()
+	return NewAdminUserCommandService(users, sessions, &MockTransactor{}), users, sessions
+}
+
+func TestAdminUserCommandService_SuspendRevokesSessions(t *testing.T) {
+	service, users, sessions := newTestAdminUserService()
+	sessions.Store(context.Background(), 2, domainauth.SessionMetadata{})
+	sessions.Store(context.Background(), 2, domainauth.SessionMetadata{})
+	adminToken, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+
+	err := service.SuspendUser(adminContext(1), 2, domainauth.SuspendUserCommand{Reason: "spam", ExpiresInDays: 7})
+
+	assert.NoError(t, err)
+	user := users.Users[2]
+	assert.True(t, user.IsSuspended())
+	assert.Equal(t, "spam", user.SuspensionReason)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *user.SuspendedUntil, time.Minute)
+	assert.Len(t, sessions.Sessions, 1)
+	assert.Contains(t, sessions.Sessions, adminToken)
+
+	assert.NoError(t, service.UnsuspendUser(adminContext(1), 2))
+	assert.False(t, users.Users[2].IsSuspended())
+	assertAppErrorCode(t, apperror.ErrWrongInput, service.UnsuspendUser(adminContext(1), 2))
+}
+
+func TestAdminUserCommandService_SuspendValidation(t *testing.T) {
+	service, _, _ := newTestAdminUserService()
+
+	assertAppErrorCode(t, apperror.ErrPermission, service.SuspendUser(accountContext(2), 1, domainauth.SuspendUserCommand{Reason: "spam"}))
+	assertAppErrorCode(t, apperror.ErrValidation, service.SuspendUser(adminContext(1), 2, domainauth.SuspendUserCommand{}))
+	assertAppErrorCode(t, apperror.ErrValidation, service.SuspendUser(adminContext(1), 2, domainauth.SuspendUserCommand{Reason: "spam", ExpiresInDays: -1}))
+	assertAppErrorCode(t, apperror.ErrWrongInput, service.SuspendUser(adminContext(1), 1, domainauth.SuspendUserCommand{Reason: "spam"}))
+	assertAppErrorCode(t, apperror.ErrUserNotFound, service.SuspendUser(adminContext(1), 3, domainauth.SuspendUserCommand{Reason: "spam"}))
+}
+
+func TestAdminUserCommandService_LiftExpiredSuspensions(t *testing.T) {
+	service, users, _ := newTestAdminUserService()
+	users.Users[2].Suspend("spam", nil, time.Now())
+	past := time.Now().Add(-time.Hour)
+	users.Users[3] = &domainauth.User{ID: 3, Email: "other@example.com", Role: common.RoleUser}
+	users.Users[3].Suspend("spam", &past, past.Add(-time.Hour))
+
+	// A lapsed suspension no longer locks the user out, even before it is lifted
+	assert.False(t, users.Users[3].IsSuspended())
+
+	lifted, err := service.LiftExpiredSuspensions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), lifted)
+	assert.Nil(t, users.Users[3].SuspendedAt)
+	assert.True(t, users.Users[2].IsSuspended())
+}
+
+func TestAdminUserCommandService_ChangeRole(t *testing.T) {
+	service, users, sessions := newTestAdminUserService()
+	sessions.Store(context.Background(), 2, domainauth.SessionMetadata{})
+
+	assert.NoError(t, service.ChangeRole(adminContext(1), 2, domainauth.ChangeRoleCommand{Role: common.RoleAdmin}))
+	assert.Equal(t, common.RoleAdmin, users.Users[2].Role)
+	// Access tokens carry the role, so the sessions they are refreshed from are revoked
+	assert.Empty(t, sessions.Sessions)
+
+	assertAppErrorCode(t, apperror.ErrValidation, service.ChangeRole(adminContext(1), 2, domainauth.ChangeRoleCommand{Role: "owner"}))
+	assertAppErrorCode(t, apperror.ErrWrongInput, service.ChangeRole(adminContext(1), 1, domainauth.ChangeRoleCommand{Role: common.RoleUser}))
+	assertAppErrorCode(t, apperror.ErrPermission, service.ChangeRole(accountContext(2), 1, domainauth.ChangeRoleCommand{Role: common.RoleUser}))
+}
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+const maxAPITokenNameLength = 100
+
+type APITokenCommandService interface {
+	// Authenticate resolves a personal API token to its user, restricted to the token's scopes
+	Authenticate(ctx context.Context, secret string) (*common.User, error)
+	// CreateToken returns the new token together with its secret, which is only shown this once
+	CreateToken(commonCtx *common.CommonContext, cmd domainauth.CreateAPITokenCommand) (*viewobject.CreatedAPITokenVO, error)
+	RevokeToken(commonCtx *common.CommonContext, tokenID int) error
+}
+
+type apiTokenCommandService struct {
+	userRepo      domainauth.UserRepository
+	apiTokenRepo  domainauth.APITokenRepository
+	twoFactorRepo domainauth.TwoFactorRepository
+}
+
+func NewAPITokenCommandService(
+	userRepo domainauth.UserRepository,
+	apiTokenRepo domainauth.APITokenRepository,
+	twoFactorRepo domainauth.TwoFactorRepository,
+) APITokenCommandService {
+	return &apiTokenCommandService{
+		userRepo:      userRepo,
+		apiTokenRepo:  apiTokenRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+func (s *apiTokenCommandService) Authenticate(ctx context.Context, secret string) (*common.User, error) {
+	token, err := s.apiTokenRepo.GetByToken(ctx, secret)
+	if err != nil {
+		return nil, apperror.ErrSession.Wrap(err).WithMetadata("operation", "authenticate_api_token")
+	}
+	now := time.Now()
+	if token == nil || token.IsExpired(now) {
+		return nil, apperror.ErrSession.WithMessage("api token not found or expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "authenticate_api_token").WithMetadata("user_id", token.UserID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", token.UserID)
+	}
+
+	// Like sessions, the last use is written back at most once per interval and failures are only logged
+	if token.NeedsTouch(now) {
+		if err := s.apiTokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			log.Printf("Failed to record last use of api token %d: %v", token.ID, err)
+		}
+		if err := s.userRepo.TouchLastSeen(ctx, user.ID, now); err != nil {
+			log.Printf("Failed to record last seen of user %d: %v", user.ID, err)
+		}
+	}
+
+	authenticated, err := newAuthenticatedUser(ctx, s.twoFactorRepo, user)
+	if err != nil {
+		return nil, err
+	}
+	authenticated.APITokenID = token.ID
+	authenticated.Scopes = token.Scopes
+	return authenticated, nil
+}
+
+func (s *apiTokenCommandService) CreateToken(commonCtx *common.CommonContext, cmd domainauth.CreateAPITokenCommand) (*viewobject.CreatedAPITokenVO, error) {
+	ctx, user := commonCtx.Ctx, commonCtx.User
+
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPITokenNameLength {
+		return nil, apperror.ErrValidation.WithMessage("token name is required and must be at most 100 characters")
+	}
+
+	scopes, err := normalizeScopes(cmd.Scopes, user)
+	if err != nil {
+		return nil, err
+	}
+
+	lifetime := domainauth.DefaultAPITokenLifetime
+	if cmd.ExpiresInDays != 0 {
+		lifetime = time.Duration(cmd.ExpiresInDays) * 24 * time.Hour
+	}
+	if lifetime <= 0 || lifetime > domainauth.MaxAPITokenLifetime {
+		return nil, apperror.ErrValidation.WithMessage("tokens must expire within 365 days")
+	}
+
+	count, err := s.apiTokenRepo.CountByUser(ctx, user.UserID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "create_api_token").WithMetadata("user_id", user.UserID)
+	}
+	if count >= domainauth.MaxAPITokensPerUser {
+		return nil, apperror.ErrValidation.WithMessage("too many api tokens, revoke unused ones first")
+	}
+
+	token, secret := domainauth.NewAPIToken(user.UserID, name, scopes, lifetime, time.Now())
+	if err := s.apiTokenRepo.Create(ctx, &token); err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "create_api_token").WithMetadata("user_id", user.UserID)
+	}
+
+	return &viewobject.CreatedAPITokenVO{
+		APITokenVO: viewobject.NewAPITokenVO(token),
+		Token:      secret,
+	}, nil
+}
+
+// normalizeScopes validates and deduplicates the requested scopes; admin scopes are reserved to admins
+func normalizeScopes(requested []common.Scope, user *common.User) ([]common.Scope, error) {
+	if len(requested) == 0 {
+		return nil, apperror.ErrValidation.WithMessage("at least one scope is required")
+	}
+
+	scopes := make([]common.Scope, 0, len(requested))
+	seen := make(map[common.Scope]bool, len(requested))
+	for _, scope := range requested {
+		if !scope.IsValid() {
+			return nil, apperror.ErrValidation.WithMessage("unknown scope").WithMetadata("scope", scope)
+		}
+		if scope.IsAdmin() && user.Role != common.RoleAdmin {
+			return nil, apperror.ErrPermission.WithMessage("admin scopes require admin access").WithMetadata("scope", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func (s *apiTokenCommandService) RevokeToken(commonCtx *common.CommonContext, tokenID int) error {
+	userID := commonCtx.User.UserID
+	deleted, err := s.apiTokenRepo.Delete(commonCtx.Ctx, userID, tokenID)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revoke_api_token").WithMetadata("token_id", tokenID)
+	}
+	if !deleted {
+		return apperror.ErrNotFound.WithMessage("api token not found").WithMetadata("token_id", tokenID)
+	}
+	return nil
+}
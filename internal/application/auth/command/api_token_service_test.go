@@ -0,0 +1,101 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+func newTestAPITokenService(users *MockUserRepository, tokens *MockAPITokenRepository) APITokenCommandService {
+	return NewAPITokenCommandService(users, tokens, NewMockTwoFactorRepository())
+}
+
+func TestAPITokenCommandService_CreateAndAuthenticate(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	tokens := NewMockAPITokenRepository()
+	service := newTestAPITokenService(users, tokens)
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1, Role: common.RoleUser}}
+
+	created, err := service.CreateToken(commonCtx, domainauth.CreateAPITokenCommand{
+		Name:   "export bot",
+		Scopes: []common.Scope{common.ScopeReviewRead, common.ScopeReviewRead},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, domainauth.APITokenPrefix))
+	assert.Equal(t, []common.Scope{common.ScopeReviewRead}, created.Scopes)
+	assert.WithinDuration(t, time.Now().Add(domainauth.DefaultAPITokenLifetime), created.ExpiresAt, time.Minute)
+	assert.NotEqual(t, created.Token, tokens.Tokens[created.ID].TokenHash)
+
+	user, err := service.Authenticate(context.Background(), created.Token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.UserID)
+	assert.Equal(t, created.ID, user.APITokenID)
+	assert.True(t, user.HasScope(common.ScopeReviewRead))
+	assert.False(t, user.HasScope(common.ScopeReviewWrite))
+	assert.NotNil(t, tokens.Tokens[created.ID].LastUsedAt)
+}
+
+func TestAPITokenCommandService_AuthenticateExpired(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	tokens := NewMockAPITokenRepository()
+	token, secret := domainauth.NewAPIToken(1, "old", []common.Scope{common.ScopeUserRead}, time.Hour, time.Now().Add(-2*time.Hour))
+	_ = tokens.Create(context.Background(), &token)
+	service := newTestAPITokenService(users, tokens)
+
+	_, err := service.Authenticate(context.Background(), secret)
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrSession.Code, appErr.Code)
+}
+
+func TestAPITokenCommandService_CreateRejectsInvalidScopes(t *testing.T) {
+	service := newTestAPITokenService(NewMockUserRepository(), NewMockAPITokenRepository())
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1, Role: common.RoleUser}}
+
+	tests := []struct {
+		name     string
+		cmd      domainauth.CreateAPITokenCommand
+		expected *apperror.AppError
+	}{
+		{"no scopes", domainauth.CreateAPITokenCommand{Name: "bot"}, apperror.ErrValidation},
+		{"unknown scope", domainauth.CreateAPITokenCommand{Name: "bot", Scopes: []common.Scope{"review:*"}}, apperror.ErrValidation},
+		{"admin scope for user", domainauth.CreateAPITokenCommand{Name: "bot", Scopes: []common.Scope{common.ScopeAdminPoints}}, apperror.ErrPermission},
+		{"no name", domainauth.CreateAPITokenCommand{Scopes: []common.Scope{common.ScopeUserRead}}, apperror.ErrValidation},
+		{"too long", domainauth.CreateAPITokenCommand{Name: "bot", Scopes: []common.Scope{common.ScopeUserRead}, ExpiresInDays: 366}, apperror.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateToken(commonCtx, tt.cmd)
+
+			var appErr *apperror.AppError
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.expected.Code, appErr.Code)
+		})
+	}
+}
+
+func TestAPITokenCommandService_RevokeOnlyOwnTokens(t *testing.T) {
+	tokens := NewMockAPITokenRepository()
+	token, _ := domainauth.NewAPIToken(2, "other", []common.Scope{common.ScopeUserRead}, time.Hour, time.Now())
+	_ = tokens.Create(context.Background(), &token)
+	service := newTestAPITokenService(NewMockUserRepository(), tokens)
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1}}
+
+	err := service.RevokeToken(commonCtx, token.ID)
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrNotFound.Code, appErr.Code)
+	assert.Len(t, tokens.Tokens, 1)
+}
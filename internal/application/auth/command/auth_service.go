@@ -2,6 +2,8 @@ package command
 
 import (
 	"context"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,66 +11,122 @@ import (
 	"jcourse_go/internal/application/auth"
 	domainauth "jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
 	"jcourse_go/pkg/apperror"
 	"jcourse_go/pkg/password"
 )
 
 type AuthCommandService interface {
-	Login(ctx context.Context, cmd domainauth.LoginCommand) error
+	// Login checks the password and opens a session, or returns a challenge when two-factor authentication is enabled
+	Login(ctx context.Context, cmd domainauth.LoginCommand) (*LoginResult, error)
 	Register(ctx context.Context, cmd domainauth.RegisterCommand) error
 	Logout(ctx context.Context, cmd domainauth.LogoutCommand) error
 	SendVerificationCode(ctx context.Context, cmd domainauth.SendVerificationCodeCommand) error
+	// RequestPasswordReset emails a reset code if the address belongs to a user
+	RequestPasswordReset(ctx context.Context, cmd domainauth.RequestPasswordResetCommand) error
+	// ResetPassword sets a new password with a reset code, signs the user out everywhere and revokes their API tokens
+	ResetPassword(ctx context.Context, cmd domainauth.ResetPasswordCommand) error
 }
 
 func NewAuthCommandService(
 	userRepo domainauth.UserRepository,
 	hasher password.Hasher,
 	session domainauth.SessionRepository,
+	apiTokens domainauth.APITokenRepository,
+	twoFactor domainauth.TwoFactorRepository,
+	challenges domainauth.LoginChallengeRepository,
+	failures domainauth.FailureCounterRepository,
+	invites domainauth.InviteCodeRepository,
+	policy domainauth.RegistrationPolicy,
 	codeService auth.VerificationCodeService,
+	transactor common.Transactor,
+	eventPublisher event.Publisher,
 ) AuthCommandService {
 	return &authCommandService{
-		userRepo:    userRepo,
-		hasher:      hasher,
-		session:     session,
-		codeService: codeService,
+		userRepo:       userRepo,
+		hasher:         hasher,
+		dummyHash:      sync.OnceValue(func() string { return hasher.Hash("dummy password") }),
+		session:        session,
+		apiTokens:      apiTokens,
+		login:          &loginFlow{session: session, twoFactor: twoFactor, challenges: challenges},
+		throttle:       &loginThrottle{counters: failures},
+		invites:        invites,
+		policy:         policy,
+		codeService:    codeService,
+		transactor:     transactor,
+		eventPublisher: eventPublisher,
 	}
 }
 
 type authCommandService struct {
-	userRepo    domainauth.UserRepository
-	hasher      password.Hasher
-	session     domainauth.SessionRepository
-	codeService auth.VerificationCodeService
+	userRepo       domainauth.UserRepository
+	hasher         password.Hasher
+	dummyHash      func() string
+	session        domainauth.SessionRepository
+	apiTokens      domainauth.APITokenRepository
+	login          *loginFlow
+	throttle       *loginThrottle
+	invites        domainauth.InviteCodeRepository
+	policy         domainauth.RegistrationPolicy
+	codeService    auth.VerificationCodeService
+	transactor     common.Transactor
+	eventPublisher event.Publisher
 }
 
-func (s *authCommandService) Login(ctx context.Context, cmd domainauth.LoginCommand) error {
+func (s *authCommandService) Login(ctx context.Context, cmd domainauth.LoginCommand) (*LoginResult, error) {
+	if err := s.throttle.check(ctx, cmd.Email, cmd.Session.IP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.Get(ctx, cmd.Email)
 	if err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "login").WithMetadata("email", cmd.Email)
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "login").WithMetadata("email", cmd.Email)
 	}
-	if user == nil {
-		return apperror.ErrWrongAuth.WithUserMessage("Invalid email or password").WithMetadata("email", cmd.Email)
+	// Unknown emails are checked against a dummy hash so they take as long as a wrong password,
+	// and count as failures too, so neither the timing nor lockouts reveal which accounts exist
+	var passwordHash string
+	if user != nil {
+		passwordHash = user.Password
+	} else {
+		passwordHash = s.dummyHash()
 	}
-	if err := s.hasher.Validate(cmd.Password, user.Password); err != nil {
-		return apperror.ErrWrongAuth.WithUserMessage("Invalid email or password").WithMetadata("email", cmd.Email)
+	if s.hasher.Validate(cmd.Password, passwordHash) != nil || user == nil {
+		s.throttle.recordFailure(ctx, cmd.Email, cmd.Session.IP)
+		return nil, apperror.ErrWrongAuth.WithUserMessage("Invalid email or password").WithMetadata("email", cmd.Email)
 	}
 	if user.IsSuspended() {
-		return apperror.ErrSuspended.WithMetadata("user_id", user.ID)
+		return nil, apperror.ErrSuspended.WithMetadata("user_id", user.ID)
 	}
-	sessionID, err := s.session.Store(ctx, user.ID)
+	s.rehashPassword(ctx, user, cmd.Password)
+
+	result, err := s.login.begin(ctx, user.ID, cmd.Session)
 	if err != nil {
-		return apperror.ErrSession.Wrap(err).WithMetadata("operation", "login").WithMetadata("user_id", user.ID)
+		return nil, err
 	}
-	// Store session ID in context for controller to access
-	if ginCtx, ok := ctx.(*gin.Context); ok {
-		ginCtx.Set("session_id", sessionID)
+	// With two-factor authentication the login is only complete, and the failures forgotten, after VerifyLogin
+	if !result.TwoFactorRequired() {
+		s.throttle.reset(ctx, cmd.Email)
+	}
+	return result, nil
+}
+
+// rehashPassword upgrades a legacy or outdated password hash while the plain password is at hand.
+// Failing to do so must not prevent the login, it is retried on the next one.
+func (s *authCommandService) rehashPassword(ctx context.Context, user *domainauth.User, password string) {
+	if !s.hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	user.Password = s.hasher.Hash(password)
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, user.Password, user.UpdatedAt); err != nil {
+		log.Printf("Failed to rehash password of user %d: %v", user.ID, err)
 	}
-	return nil
 }
 
 func (s *authCommandService) Logout(ctx context.Context, cmd domainauth.LogoutCommand) error {
 	if err := s.session.Delete(ctx, cmd.SessionID); err != nil {
-		return apperror.ErrSession.Wrap(err).WithMetadata("operation", "logout").WithMetadata("session_id", cmd.SessionID)
+		return apperror.ErrSession.Wrap(err).WithMetadata("operation", "logout")
 	}
 	return nil
 }
@@ -88,8 +146,37 @@ func (s *authCommandService) newUserFromRegister(cmd domainauth.RegisterCommand)
 	return user
 }
 
+// checkRegistration applies the registration policy and returns the invite code the registration uses, if it needs one
+func (s *authCommandService) checkRegistration(ctx context.Context, cmd domainauth.RegisterCommand) (*domainauth.InviteCode, error) {
+	if s.policy.IsBlocked(cmd.Email) {
+		return nil, apperror.ErrValidation.WithUserMessage("Registration with this email provider is not allowed").
+			WithMetadata("email", cmd.Email)
+	}
+	if !s.policy.RequiresInvite(cmd.Email) {
+		return nil, nil
+	}
+	if cmd.InviteCode == "" {
+		return nil, apperror.ErrPermission.WithUserMessage("An invite code is required to register with this email").
+			WithMetadata("email", cmd.Email)
+	}
+
+	invite, err := s.invites.Get(ctx, cmd.InviteCode)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_invite_code").WithMetadata("email", cmd.Email)
+	}
+	if invite == nil || !invite.IsUsable(time.Now()) {
+		return nil, apperror.ErrWrongInput.WithUserMessage("Invite code is invalid or has been used up")
+	}
+	return invite, nil
+}
+
 func (s *authCommandService) Register(ctx context.Context, cmd domainauth.RegisterCommand) error {
-	if err := s.codeService.Verify(ctx, cmd.Code, cmd.Email); err != nil {
+	// The policy is checked first, so a refused registration does not use up the verification code
+	invite, err := s.checkRegistration(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	if err := s.codeService.Verify(ctx, cmd.Code, cmd.Email, domainauth.CodePurposeRegister); err != nil {
 		return apperror.ErrWrongAuth.Wrap(err).WithMetadata("operation", "register").WithMetadata("email", cmd.Email)
 	}
 	existUser, err := s.userRepo.Get(ctx, cmd.Email)
@@ -100,11 +187,33 @@ func (s *authCommandService) Register(ctx context.Context, cmd domainauth.Regist
 		return apperror.ErrWrongAuth.WithUserMessage("Email already registered").WithMetadata("email", cmd.Email)
 	}
 	user := s.newUserFromRegister(cmd)
-	userID, err := s.userRepo.Save(ctx, user)
+	var userID int
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if invite != nil {
+			redeemed, err := s.invites.Redeem(ctx, invite.ID)
+			if err != nil {
+				return apperror.WrapDB(err).WithMetadata("operation", "redeem_invite_code").WithMetadata("email", cmd.Email)
+			}
+			if !redeemed {
+				return apperror.ErrWrongInput.WithUserMessage("Invite code is invalid or has been used up")
+			}
+		}
+
+		userID, err = s.userRepo.Save(ctx, user)
+		if err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "register").WithMetadata("email", cmd.Email)
+		}
+
+		payload := &event.UserPayload{UserID: userID}
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeUserCreated, payload)); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "publish_user_created_event").WithMetadata("user_id", userID)
+		}
+		return nil
+	})
 	if err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "register").WithMetadata("email", cmd.Email)
+		return err
 	}
-	sessionID, err := s.session.Store(ctx, userID)
+	sessionID, err := s.session.Store(ctx, userID, cmd.Session)
 	if err != nil {
 		return apperror.ErrSession.Wrap(err).WithMetadata("operation", "register").WithMetadata("user_id", userID)
 	}
@@ -116,5 +225,54 @@ func (s *authCommandService) Register(ctx context.Context, cmd domainauth.Regist
 }
 
 func (s *authCommandService) SendVerificationCode(ctx context.Context, cmd domainauth.SendVerificationCodeCommand) error {
-	return s.codeService.SendCode(ctx, cmd.Email)
+	if s.policy.IsBlocked(cmd.Email) {
+		return apperror.ErrValidation.WithUserMessage("Registration with this email provider is not allowed").
+			WithMetadata("email", cmd.Email)
+	}
+	return s.codeService.SendCode(ctx, cmd.Email, domainauth.CodePurposeRegister)
+}
+
+func (s *authCommandService) RequestPasswordReset(ctx context.Context, cmd domainauth.RequestPasswordResetCommand) error {
+	user, err := s.userRepo.Get(ctx, cmd.Email)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "request_password_reset").WithMetadata("email", cmd.Email)
+	}
+	// Unknown addresses get the same response, so the endpoint does not reveal who has an account
+	if user == nil {
+		return nil
+	}
+	return s.codeService.SendCode(ctx, cmd.Email, domainauth.CodePurposeResetPassword)
+}
+
+func (s *authCommandService) ResetPassword(ctx context.Context, cmd domainauth.ResetPasswordCommand) error {
+	if cmd.Password == "" {
+		return apperror.ErrValidation.WithMessage("password is required")
+	}
+	if err := s.codeService.Verify(ctx, cmd.Code, cmd.Email, domainauth.CodePurposeResetPassword); err != nil {
+		return apperror.ErrWrongAuth.Wrap(err).WithMetadata("operation", "reset_password").WithMetadata("email", cmd.Email)
+	}
+
+	user, err := s.userRepo.Get(ctx, cmd.Email)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "reset_password").WithMetadata("email", cmd.Email)
+	}
+	if user == nil {
+		return apperror.ErrUserNotFound.WithMetadata("email", cmd.Email)
+	}
+
+	user.Password = s.hasher.Hash(cmd.Password)
+	user.UpdatedAt = time.Now()
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "reset_password").WithMetadata("user_id", user.ID)
+		}
+		// Whoever knew the old password may still hold a session, or an API token created with it
+		if _, err := s.session.DeleteByUser(ctx, user.ID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revoke_sessions").WithMetadata("user_id", user.ID)
+		}
+		if _, err := s.apiTokens.DeleteByUser(ctx, user.ID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revoke_api_tokens").WithMetadata("user_id", user.ID)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,334 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+	"jcourse_go/pkg/password"
+)
+
+var testHasher = password.NewHasher(password.Params{Memory: 1024, Iterations: 1, Parallelism: 1})
+
+func newTestAuthService(users *MockUserRepository, publisher *MockPublisher) AuthCommandService {
+	return NewAuthCommandService(users, testHasher, NewMockSessionRepository(), NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, &MockCodeService{}, &MockTransactor{}, publisher)
+}
+
+func newTestAuthServiceWithSessions(users *MockUserRepository, sessions *MockSessionRepository) AuthCommandService {
+	return NewAuthCommandService(users, testHasher, sessions, NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, &MockCodeService{}, &MockTransactor{}, &MockPublisher{})
+}
+
+func legacyHash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAuthCommandService_LoginUpgradesLegacyHash(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: legacyHash("secret"), Role: common.RoleUser})
+	service := newTestAuthService(users, &MockPublisher{})
+
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, users.Updated)
+	assert.False(t, testHasher.NeedsRehash(users.Users[1].Password))
+	assert.NoError(t, testHasher.Validate("secret", users.Users[1].Password))
+}
+
+func TestAuthCommandService_LoginKeepsCurrentHash(t *testing.T) {
+	hash := testHasher.Hash("secret")
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: hash, Role: common.RoleUser})
+	service := newTestAuthService(users, &MockPublisher{})
+
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, users.Updated)
+	assert.Equal(t, hash, users.Users[1].Password)
+}
+
+func TestAuthCommandService_LoginRehashFailureIsNotFatal(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: legacyHash("secret"), Role: common.RoleUser})
+	users.UpdateError = errors.New("connection lost")
+	service := newTestAuthService(users, &MockPublisher{})
+
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret"})
+
+	assert.NoError(t, err)
+}
+
+func TestAuthCommandService_LoginWrongPassword(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: legacyHash("secret"), Role: common.RoleUser})
+	service := newTestAuthService(users, &MockPublisher{})
+
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "guess"})
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrWrongAuth.Code, appErr.Code)
+	assert.Equal(t, 0, users.Updated)
+}
+
+func TestAuthCommandService_RegisterPublishesUserCreated(t *testing.T) {
+	users := NewMockUserRepository()
+	publisher := &MockPublisher{}
+	service := newTestAuthService(users, publisher)
+
+	err := service.Register(context.Background(), domainauth.RegisterCommand{Email: "new@example.com", Code: "123456", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.Len(t, publisher.Events, 1)
+	assert.Equal(t, event.TypeUserCreated, publisher.Events[0].Type())
+	payload := publisher.Events[0].Payload().(*event.UserPayload)
+	assert.Equal(t, "new@example.com", users.Users[payload.UserID].Email)
+	assert.NoError(t, testHasher.Validate("secret", users.Users[payload.UserID].Password))
+}
+
+func TestAuthCommandService_LoginStoresSessionMetadata(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("secret"), Role: common.RoleUser})
+	sessions := NewMockSessionRepository()
+	service := newTestAuthServiceWithSessions(users, sessions)
+
+	metadata := domainauth.SessionMetadata{IP: "203.0.113.7", UserAgent: "test-agent"}
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret", Session: metadata})
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions.Sessions, 1)
+	for _, session := range sessions.Sessions {
+		assert.Equal(t, 1, session.UserID)
+		assert.Equal(t, metadata, session.Metadata)
+	}
+}
+
+func TestAuthCommandService_RequestPasswordReset(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	codes := &MockCodeService{}
+	service := NewAuthCommandService(users, testHasher, NewMockSessionRepository(), NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, codes, &MockTransactor{}, &MockPublisher{})
+
+	err := service.RequestPasswordReset(context.Background(), domainauth.RequestPasswordResetCommand{Email: "user@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []domainauth.CodePurpose{domainauth.CodePurposeResetPassword}, codes.Sent)
+
+	// Unknown addresses succeed silently without sending anything
+	err = service.RequestPasswordReset(context.Background(), domainauth.RequestPasswordResetCommand{Email: "nobody@example.com"})
+	assert.NoError(t, err)
+	assert.Len(t, codes.Sent, 1)
+}
+
+func TestAuthCommandService_ResetPasswordRevokesSessions(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("old"), Role: common.RoleUser})
+	sessions := NewMockSessionRepository()
+	_, _ = sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	_, _ = sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	foreign, _ := sessions.Store(context.Background(), 2, domainauth.SessionMetadata{})
+	tokens := NewMockAPITokenRepository()
+	_ = tokens.Create(context.Background(), &domainauth.APIToken{UserID: 1})
+	_ = tokens.Create(context.Background(), &domainauth.APIToken{UserID: 2})
+	codes := &MockCodeService{}
+	service := NewAuthCommandService(users, testHasher, sessions, tokens, NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, codes, &MockTransactor{}, &MockPublisher{})
+
+	err := service.ResetPassword(context.Background(), domainauth.ResetPasswordCommand{Email: "user@example.com", Code: "123456", Password: "new"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domainauth.CodePurpose{domainauth.CodePurposeResetPassword}, codes.Verified)
+	assert.NoError(t, testHasher.Validate("new", users.Users[1].Password))
+	assert.Len(t, sessions.Sessions, 1)
+	assert.Contains(t, sessions.Sessions, foreign)
+	// API tokens created by whoever knew the old password are revoked too
+	assert.Len(t, tokens.Tokens, 1)
+	assert.Equal(t, 2, tokens.Tokens[2].UserID)
+}
+
+func TestAuthCommandService_ResetPasswordWrongCode(t *testing.T) {
+	hash := testHasher.Hash("old")
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: hash, Role: common.RoleUser})
+	sessions := NewMockSessionRepository()
+	_, _ = sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	codes := &MockCodeService{VerifyError: apperror.ErrWrongInput}
+	service := NewAuthCommandService(users, testHasher, sessions, NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, codes, &MockTransactor{}, &MockPublisher{})
+
+	err := service.ResetPassword(context.Background(), domainauth.ResetPasswordCommand{Email: "user@example.com", Code: "000000", Password: "new"})
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrWrongAuth.Code, appErr.Code)
+	assert.Equal(t, hash, users.Users[1].Password)
+	assert.Len(t, sessions.Sessions, 1)
+}
+
+func newTestAuthServiceWithFailures(users *MockUserRepository, failures *MockFailureCounterRepository) AuthCommandService {
+	return NewAuthCommandService(users, testHasher, NewMockSessionRepository(), NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), failures, NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, &MockCodeService{}, &MockTransactor{}, &MockPublisher{})
+}
+
+func TestAuthCommandService_LoginUnknownEmailValidatesDummyHash(t *testing.T) {
+	hasher := &MockHasher{Hasher: testHasher}
+	service := NewAuthCommandService(NewMockUserRepository(), hasher, NewMockSessionRepository(), NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, &MockCodeService{}, &MockTransactor{}, &MockPublisher{})
+
+	for _, guess := range []string{"guess", "dummy password"} {
+		_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "nobody@example.com", Password: guess})
+
+		var appErr *apperror.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperror.ErrWrongAuth.Code, appErr.Code)
+	}
+	// The password is checked against an argon2id hash, as for a user with a wrong password
+	assert.Len(t, hasher.Validated, 2)
+	assert.False(t, testHasher.NeedsRehash(hasher.Validated[0]))
+}
+
+func TestAuthCommandService_LoginLocksOutEmail(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("secret"), Role: common.RoleUser})
+	service := newTestAuthServiceWithFailures(users, NewMockFailureCounterRepository())
+
+	for i := 0; i < domainauth.EmailThrottlePolicy.Threshold; i++ {
+		_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "guess"})
+		var appErr *apperror.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperror.ErrWrongAuth.Code, appErr.Code)
+	}
+
+	// Even the right password is refused while locked out, and emails are matched regardless of case
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "User@Example.com", Password: "secret"})
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrRateLimit.Code, appErr.Code)
+	assert.InDelta(t, domainauth.EmailThrottlePolicy.BaseLockout.Seconds(), appErr.RetryAfter.Seconds(), 5)
+}
+
+func TestAuthCommandService_LoginLocksOutIP(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("secret"), Role: common.RoleUser})
+	service := newTestAuthServiceWithFailures(users, NewMockFailureCounterRepository())
+	session := domainauth.SessionMetadata{IP: "203.0.113.7"}
+
+	// Spraying a password over many accounts trips the per-IP counter
+	for i := 0; i < domainauth.IPThrottlePolicy.Threshold; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		_, _ = service.Login(context.Background(), domainauth.LoginCommand{Email: email, Password: "guess", Session: session})
+	}
+
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret", Session: session})
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrRateLimit.Code, appErr.Code)
+
+	// Other addresses are not affected
+	_, err = service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret", Session: domainauth.SessionMetadata{IP: "198.51.100.1"}})
+	assert.NoError(t, err)
+}
+
+func TestLoginThrottle_CountsConcurrentFailures(t *testing.T) {
+	failures := NewMockFailureCounterRepository()
+	throttle := &loginThrottle{counters: failures}
+	attempts := 2 * domainauth.EmailThrottlePolicy.Threshold
+
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			throttle.recordFailure(context.Background(), "user@example.com", "203.0.113.7")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, attempts, failures.Counters[domainauth.EmailThrottleKey("user@example.com")].Failures)
+	assert.Equal(t, attempts, failures.Counters[domainauth.IPThrottleKey("203.0.113.7")].Failures)
+	assert.Error(t, throttle.check(context.Background(), "user@example.com", ""))
+}
+
+func TestAuthCommandService_LoginResetsEmailFailures(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("secret"), Role: common.RoleUser})
+	failures := NewMockFailureCounterRepository()
+	service := newTestAuthServiceWithFailures(users, failures)
+	session := domainauth.SessionMetadata{IP: "203.0.113.7"}
+
+	_, _ = service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "guess", Session: session})
+	_, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret", Session: session})
+
+	assert.NoError(t, err)
+	assert.NotContains(t, failures.Counters, domainauth.EmailThrottleKey("user@example.com"))
+	assert.Equal(t, 1, failures.Counters[domainauth.IPThrottleKey("203.0.113.7")].Failures)
+}
+
+func TestAuthCommandService_LoginWithTwoFactorKeepsEmailFailures(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("secret"), Role: common.RoleUser})
+	failures := NewMockFailureCounterRepository()
+	twoFactors := NewMockTwoFactorRepository()
+	now := time.Now()
+	twoFactors.TwoFactors[1] = domainauth.TwoFactor{UserID: 1, EnabledAt: &now}
+	service := NewAuthCommandService(users, testHasher, NewMockSessionRepository(), NewMockAPITokenRepository(), twoFactors, NewMockLoginChallengeRepository(), failures, NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, &MockCodeService{}, &MockTransactor{}, &MockPublisher{})
+
+	_, _ = service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "guess"})
+	result, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret"})
+
+	// The password alone does not complete the login, so it does not clear the failures
+	assert.NoError(t, err)
+	assert.True(t, result.TwoFactorRequired())
+	assert.Contains(t, failures.Counters, domainauth.EmailThrottleKey("user@example.com"))
+}
+
+func newTestAuthServiceWithPolicy(users *MockUserRepository, invites *MockInviteCodeRepository, policy domainauth.RegistrationPolicy, codes *MockCodeService) AuthCommandService {
+	return NewAuthCommandService(users, testHasher, NewMockSessionRepository(), NewMockAPITokenRepository(), NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), invites, policy, codes, &MockTransactor{}, &MockPublisher{})
+}
+
+func TestAuthCommandService_RegisterOutsideAllowedDomainsNeedsInvite(t *testing.T) {
+	users := NewMockUserRepository()
+	invites := NewMockInviteCodeRepository(domainauth.InviteCode{ID: 1, Code: "ABCDEFGHJKLM", MaxUses: 1})
+	codes := &MockCodeService{}
+	service := newTestAuthServiceWithPolicy(users, invites, domainauth.RegistrationPolicy{AllowedDomains: []string{"sjtu.edu.cn"}}, codes)
+
+	err := service.Register(context.Background(), domainauth.RegisterCommand{Email: "alice@sjtu.edu.cn", Code: "123456", Password: "secret"})
+	assert.NoError(t, err)
+
+	err = service.Register(context.Background(), domainauth.RegisterCommand{Email: "bob@example.com", Code: "123456", Password: "secret"})
+	assertAppErrorCode(t, apperror.ErrPermission, err)
+	// The refused registration did not use up the verification code
+	assert.Len(t, codes.Verified, 1)
+
+	err = service.Register(context.Background(), domainauth.RegisterCommand{Email: "bob@example.com", Code: "123456", Password: "secret", InviteCode: "abcd-efgh-jklm"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, invites.Invites[1].Uses)
+
+	// The invite is used up
+	err = service.Register(context.Background(), domainauth.RegisterCommand{Email: "carol@example.com", Code: "123456", Password: "secret", InviteCode: "ABCDEFGHJKLM"})
+	assertAppErrorCode(t, apperror.ErrWrongInput, err)
+	assert.Len(t, users.Users, 2)
+}
+
+func TestAuthCommandService_RegisterRejectsDisposableDomain(t *testing.T) {
+	invites := NewMockInviteCodeRepository(domainauth.InviteCode{ID: 1, Code: "ABCDEFGHJKLM", MaxUses: 1})
+	service := newTestAuthServiceWithPolicy(NewMockUserRepository(), invites, domainauth.RegistrationPolicy{}, &MockCodeService{})
+
+	// Not even an invite admits a blocked domain
+	err := service.Register(context.Background(), domainauth.RegisterCommand{Email: "spam@mailinator.com", Code: "123456", Password: "secret", InviteCode: "ABCDEFGHJKLM"})
+	assertAppErrorCode(t, apperror.ErrValidation, err)
+	assert.Equal(t, 0, invites.Invites[1].Uses)
+
+	err = service.SendVerificationCode(context.Background(), domainauth.SendVerificationCodeCommand{Email: "spam@mailinator.com"})
+	assertAppErrorCode(t, apperror.ErrValidation, err)
+}
+
+func TestAuthCommandService_RegisterInviteOnly(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	invites := NewMockInviteCodeRepository(domainauth.InviteCode{ID: 1, Code: "ABCDEFGHJKLM", MaxUses: 5, ExpiresAt: &expired})
+	service := newTestAuthServiceWithPolicy(NewMockUserRepository(), invites, domainauth.RegistrationPolicy{AllowedDomains: []string{"sjtu.edu.cn"}, InviteOnly: true}, &MockCodeService{})
+
+	err := service.Register(context.Background(), domainauth.RegisterCommand{Email: "alice@sjtu.edu.cn", Code: "123456", Password: "secret"})
+	assertAppErrorCode(t, apperror.ErrPermission, err)
+
+	err = service.Register(context.Background(), domainauth.RegisterCommand{Email: "alice@sjtu.edu.cn", Code: "123456", Password: "secret", InviteCode: "ABCDEFGHJKLM"})
+	assertAppErrorCode(t, apperror.ErrWrongInput, err)
+}
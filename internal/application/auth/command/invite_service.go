@@ -0,0 +1,70 @@
+package command
+
+import (
+	"time"
+
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+// MaxInviteUses bounds a single invite code, so a leaked one cannot open registration to everyone
+const MaxInviteUses = 1000
+
+type InviteCommandService interface {
+	CreateInvite(commonCtx *common.CommonContext, cmd domainauth.CreateInviteCommand) (*viewobject.InviteCodeVO, error)
+	RevokeInvite(commonCtx *common.CommonContext, id int) error
+}
+
+type inviteCommandService struct {
+	inviteRepo domainauth.InviteCodeRepository
+}
+
+func NewInviteCommandService(inviteRepo domainauth.InviteCodeRepository) InviteCommandService {
+	return &inviteCommandService{
+		inviteRepo: inviteRepo,
+	}
+}
+
+func (s *inviteCommandService) CreateInvite(commonCtx *common.CommonContext, cmd domainauth.CreateInviteCommand) (*viewobject.InviteCodeVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can manage invite codes")
+	}
+	if cmd.MaxUses < 1 || cmd.MaxUses > MaxInviteUses {
+		return nil, apperror.ErrValidation.WithMessage("max_uses must be between 1 and 1000")
+	}
+	if cmd.ExpiresInDays < 0 {
+		return nil, apperror.ErrValidation.WithMessage("expires_in_days must not be negative")
+	}
+
+	now := time.Now()
+	var expiresAt *time.Time
+	if cmd.ExpiresInDays > 0 {
+		t := now.AddDate(0, 0, cmd.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	invite := domainauth.NewInviteCode(cmd.MaxUses, expiresAt, commonCtx.User.UserID, now)
+	if err := s.inviteRepo.Create(commonCtx.Ctx, &invite); err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "create_invite_code")
+	}
+
+	vo := viewobject.NewInviteCodeVO(invite)
+	return &vo, nil
+}
+
+func (s *inviteCommandService) RevokeInvite(commonCtx *common.CommonContext, id int) error {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return apperror.ErrPermission.WithMessage("only admins can manage invite codes")
+	}
+
+	deleted, err := s.inviteRepo.Delete(commonCtx.Ctx, id)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revoke_invite_code").WithMetadata("invite_id", id)
+	}
+	if !deleted {
+		return apperror.ErrNotFound.WithMessage("invite code not found").WithMetadata("invite_id", id)
+	}
+	return nil
+}
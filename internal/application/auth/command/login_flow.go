@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/pkg/apperror"
+)
+
+// LoginResult is the outcome of a login that passed the first factor: either an open session,
+// or, for users with two-factor authentication, a challenge to complete with VerifyLogin
+type LoginResult struct {
+	SessionID          string
+	ChallengeToken     string
+	ChallengeExpiresAt time.Time
+}
+
+func (r *LoginResult) TwoFactorRequired() bool {
+	return r.ChallengeToken != ""
+}
+
+// loginFlow opens the session of a user who passed the first factor, or a challenge when
+// the user has enabled two-factor authentication
+type loginFlow struct {
+	session    domainauth.SessionRepository
+	twoFactor  domainauth.TwoFactorRepository
+	challenges domainauth.LoginChallengeRepository
+}
+
+func (f *loginFlow) begin(ctx context.Context, userID int, metadata domainauth.SessionMetadata) (*LoginResult, error) {
+	twoFactor, err := f.twoFactor.Get(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_two_factor").WithMetadata("user_id", userID)
+	}
+
+	if twoFactor.IsEnabled() {
+		challenge, token := domainauth.NewLoginChallenge(userID, metadata, time.Now())
+		if err := f.challenges.Create(ctx, &challenge); err != nil {
+			return nil, apperror.WrapDB(err).WithMetadata("operation", "create_login_challenge").WithMetadata("user_id", userID)
+		}
+		return &LoginResult{ChallengeToken: token, ChallengeExpiresAt: challenge.ExpiresAt}, nil
+	}
+
+	sessionID, err := f.session.Store(ctx, userID, metadata)
+	if err != nil {
+		return nil, apperror.ErrSession.Wrap(err).WithMetadata("operation", "login").WithMetadata("user_id", userID)
+	}
+	return &LoginResult{SessionID: sessionID}, nil
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/pkg/apperror"
+)
+
+// loginThrottle locks emails and IP addresses out after repeated failed logins, for progressively longer
+type loginThrottle struct {
+	counters domainauth.FailureCounterRepository
+}
+
+type throttledKey struct {
+	key    domainauth.ThrottleKey
+	policy domainauth.ThrottlePolicy
+}
+
+func (t *loginThrottle) keys(email string, ip string) []throttledKey {
+	keys := []throttledKey{{domainauth.EmailThrottleKey(strings.ToLower(email)), domainauth.EmailThrottlePolicy}}
+	if ip != "" {
+		keys = append(keys, throttledKey{domainauth.IPThrottleKey(ip), domainauth.IPThrottlePolicy})
+	}
+	return keys
+}
+
+// check returns ErrRateLimit with the remaining lockout while the email or the IP address is locked out
+func (t *loginThrottle) check(ctx context.Context, email string, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, k := range t.keys(email, ip) {
+		counter, err := t.counters.Get(ctx, k.key)
+		if err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "check_login_throttle")
+		}
+		retryAfter = max(retryAfter, counter.RetryAfter(now))
+	}
+	if retryAfter > 0 {
+		return apperror.ErrRateLimit.WithUserMessage("Too many failed login attempts, please try again later").
+			WithRetryAfter(retryAfter).WithMetadata("email", email)
+	}
+	return nil
+}
+
+// recordFailure counts a failed login; failing to do so is only logged, the login fails either way
+func (t *loginThrottle) recordFailure(ctx context.Context, email string, ip string) {
+	now := time.Now()
+	for _, k := range t.keys(email, ip) {
+		if _, err := t.counters.RecordFailure(ctx, k.key, k.policy, now); err != nil {
+			log.Printf("Failed to record login failure of %s: %v", k.key, err)
+		}
+	}
+}
+
+// reset forgets the failures of the email after a successful login; the IP address keeps its count,
+// so an attacker cannot clear it by logging into an account of their own
+func (t *loginThrottle) reset(ctx context.Context, email string) {
+	key := domainauth.EmailThrottleKey(strings.ToLower(email))
+	if err := t.counters.Delete(ctx, key); err != nil {
+		log.Printf("Failed to reset login failures of %s: %v", key, err)
+	}
+}
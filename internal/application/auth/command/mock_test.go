@@ -0,0 +1,704 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/email"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/password"
+)
+
+// MockTransactor runs the function directly without a real transaction
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// MockUserRepository is an in-memory implementation of auth.UserRepository for testing
+type MockUserRepository struct {
+	Users       map[int]*domainauth.User
+	UpdateError error
+	Updated     int
+}
+
+func NewMockUserRepository(users ...domainauth.User) *MockUserRepository {
+	m := &MockUserRepository{Users: make(map[int]*domainauth.User)}
+	for _, user := range users {
+		u := user
+		m.Users[u.ID] = &u
+	}
+	return m
+}
+
+func (m *MockUserRepository) Get(ctx context.Context, email string) (*domainauth.User, error) {
+	for _, user := range m.Users {
+		if user.Email == email {
+			u := *user
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID int) (*domainauth.User, error) {
+	user, ok := m.Users[userID]
+	if !ok {
+		return nil, nil
+	}
+	u := *user
+	return &u, nil
+}
+
+func (m *MockUserRepository) FindBy(ctx context.Context, filter domainauth.UserFilter) ([]domainauth.User, error) {
+	users := make([]domainauth.User, 0, len(filter.UserIDs))
+	for _, id := range filter.UserIDs {
+		if user, ok := m.Users[id]; ok {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (m *MockUserRepository) Save(ctx context.Context, user *domainauth.User) (int, error) {
+	u := *user
+	u.ID = len(m.Users) + 1
+	m.Users[u.ID] = &u
+	return u.ID, nil
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *domainauth.User) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	u := *user
+	m.Users[u.ID] = &u
+	m.Updated++
+	return nil
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string, at time.Time) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	if user, ok := m.Users[userID]; ok {
+		user.Password = passwordHash
+		user.UpdatedAt = at
+	}
+	m.Updated++
+	return nil
+}
+
+func (m *MockUserRepository) TouchLastSeen(ctx context.Context, userID int, at time.Time) error {
+	if user, ok := m.Users[userID]; ok {
+		user.LastSeenAt = at
+	}
+	return nil
+}
+
+func (m *MockUserRepository) ChangeEmail(ctx context.Context, userID int, email string) (bool, error) {
+	for _, user := range m.Users {
+		if user.Email == email {
+			return false, nil
+		}
+	}
+	user, ok := m.Users[userID]
+	if !ok {
+		return false, nil
+	}
+	user.Email = email
+	return true, nil
+}
+
+func (m *MockUserRepository) FindDeletionDue(ctx context.Context, before time.Time) ([]domainauth.User, error) {
+	var users []domainauth.User
+	for _, user := range m.Users {
+		if user.DeletionScheduledAt != nil && !user.DeletionScheduledAt.After(before) {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+// Anonymize only stores the user; purging the related data is left to the repository implementation
+func (m *MockUserRepository) Anonymize(ctx context.Context, user *domainauth.User) error {
+	u := *user
+	m.Users[u.ID] = &u
+	return nil
+}
+
+// Search matches the query against email and username without paging
+func (m *MockUserRepository) Search(ctx context.Context, filter domainauth.UserSearchFilter) ([]domainauth.User, int64, error) {
+	var users []domainauth.User
+	for _, user := range m.Users {
+		if strings.Contains(user.Email, filter.Query) || strings.Contains(user.Username, filter.Query) {
+			users = append(users, *user)
+		}
+	}
+	return users, int64(len(users)), nil
+}
+
+func (m *MockUserRepository) LiftExpiredSuspensions(ctx context.Context, now time.Time) (int64, error) {
+	var lifted int64
+	for _, user := range m.Users {
+		if user.SuspendedUntil != nil && !user.SuspendedUntil.After(now) {
+			user.Unsuspend(now)
+			lifted++
+		}
+	}
+	return lifted, nil
+}
+
+// MockSessionRepository is an in-memory implementation of auth.SessionRepository for testing
+type MockSessionRepository struct {
+	Sessions map[string]*domainauth.Session
+}
+
+func NewMockSessionRepository() *MockSessionRepository {
+	return &MockSessionRepository{Sessions: make(map[string]*domainauth.Session)}
+}
+
+func (m *MockSessionRepository) Store(ctx context.Context, userID int, metadata domainauth.SessionMetadata) (string, error) {
+	token := fmt.Sprintf("token-%d", len(m.Sessions)+1)
+	session := domainauth.NewSession(userID, domainauth.HashSessionToken(token), metadata, time.Now())
+	session.ID = len(m.Sessions) + 1
+	m.Sessions[token] = &session
+	return token, nil
+}
+
+func (m *MockSessionRepository) Get(ctx context.Context, token string) (*domainauth.Session, error) {
+	session, ok := m.Sessions[token]
+	if !ok {
+		return nil, nil
+	}
+	s := *session
+	return &s, nil
+}
+
+func (m *MockSessionRepository) GetByID(ctx context.Context, sessionID int) (*domainauth.Session, error) {
+	for _, session := range m.Sessions {
+		if session.ID == sessionID {
+			s := *session
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockSessionRepository) Delete(ctx context.Context, token string) error {
+	delete(m.Sessions, token)
+	return nil
+}
+
+func (m *MockSessionRepository) ListByUser(ctx context.Context, userID int) ([]domainauth.Session, error) {
+	var sessions []domainauth.Session
+	for _, session := range m.Sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *MockSessionRepository) Update(ctx context.Context, session *domainauth.Session) error {
+	for _, stored := range m.Sessions {
+		if stored.ID == session.ID {
+			stored.LastSeenAt = session.LastSeenAt
+			stored.ExpiresAt = session.ExpiresAt
+		}
+	}
+	return nil
+}
+
+func (m *MockSessionRepository) DeleteByID(ctx context.Context, userID int, sessionID int) (bool, error) {
+	for token, session := range m.Sessions {
+		if session.ID == sessionID && session.UserID == userID {
+			delete(m.Sessions, token)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockSessionRepository) DeleteByUser(ctx context.Context, userID int) (int64, error) {
+	return m.DeleteOthers(ctx, userID, 0)
+}
+
+func (m *MockSessionRepository) DeleteOthers(ctx context.Context, userID int, keepID int) (int64, error) {
+	var deleted int64
+	for token, session := range m.Sessions {
+		if session.UserID == userID && session.ID != keepID {
+			delete(m.Sessions, token)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// MockHasher is a password.Hasher that records the hashes it validated passwords against
+type MockHasher struct {
+	password.Hasher
+	Validated []string
+}
+
+func (m *MockHasher) Validate(plain, hash string) error {
+	m.Validated = append(m.Validated, hash)
+	return m.Hasher.Validate(plain, hash)
+}
+
+// MockCodeService accepts every code unless VerifyError is set, and records the codes it was asked to send
+type MockCodeService struct {
+	VerifyError error
+	Sent        []domainauth.CodePurpose
+	Verified    []domainauth.CodePurpose
+}
+
+func (m *MockCodeService) SendCode(ctx context.Context, email string, purpose domainauth.CodePurpose) error {
+	m.Sent = append(m.Sent, purpose)
+	return nil
+}
+
+func (m *MockCodeService) Verify(ctx context.Context, inputCode string, email string, purpose domainauth.CodePurpose) error {
+	m.Verified = append(m.Verified, purpose)
+	return m.VerifyError
+}
+
+// MockPublisher records the published events
+type MockPublisher struct {
+	Events []event.Event
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	m.Events = append(m.Events, events...)
+	return nil
+}
+
+// MockIdentityProvider returns Claims for every code, or ExchangeError when set
+type MockIdentityProvider struct {
+	Claims        domainauth.IdentityClaims
+	ExchangeError error
+	// Nonce and CodeVerifier are those of the last exchange
+	Nonce        string
+	CodeVerifier string
+}
+
+func (m *MockIdentityProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockIdentityProvider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	return "https://idp.example.com/authorize?state=" + state + "&code_challenge=" + codeChallenge
+}
+
+func (m *MockIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domainauth.IdentityClaims, error) {
+	m.Nonce = nonce
+	m.CodeVerifier = codeVerifier
+	if m.ExchangeError != nil {
+		return nil, m.ExchangeError
+	}
+	claims := m.Claims
+	return &claims, nil
+}
+
+// MockExternalIdentityRepository is an in-memory implementation of auth.ExternalIdentityRepository for testing
+type MockExternalIdentityRepository struct {
+	Identities []domainauth.ExternalIdentity
+}
+
+func (m *MockExternalIdentityRepository) Get(ctx context.Context, provider string, subject string) (*domainauth.ExternalIdentity, error) {
+	for _, identity := range m.Identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			i := identity
+			return &i, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockExternalIdentityRepository) Save(ctx context.Context, identity *domainauth.ExternalIdentity) error {
+	identity.ID = len(m.Identities) + 1
+	m.Identities = append(m.Identities, *identity)
+	return nil
+}
+
+// MockOAuthStateRepository is an in-memory implementation of auth.OAuthStateRepository for testing
+type MockOAuthStateRepository struct {
+	States map[string]domainauth.OAuthState
+}
+
+func NewMockOAuthStateRepository() *MockOAuthStateRepository {
+	return &MockOAuthStateRepository{States: make(map[string]domainauth.OAuthState)}
+}
+
+func (m *MockOAuthStateRepository) Save(ctx context.Context, state *domainauth.OAuthState) error {
+	m.States[state.State] = *state
+	return nil
+}
+
+func (m *MockOAuthStateRepository) Take(ctx context.Context, state string) (*domainauth.OAuthState, error) {
+	s, ok := m.States[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(m.States, state)
+	return &s, nil
+}
+
+// MockTwoFactorRepository is an in-memory implementation of auth.TwoFactorRepository for testing
+type MockTwoFactorRepository struct {
+	TwoFactors map[int]domainauth.TwoFactor
+}
+
+func NewMockTwoFactorRepository() *MockTwoFactorRepository {
+	return &MockTwoFactorRepository{TwoFactors: make(map[int]domainauth.TwoFactor)}
+}
+
+func (m *MockTwoFactorRepository) Get(ctx context.Context, userID int) (*domainauth.TwoFactor, error) {
+	twoFactor, ok := m.TwoFactors[userID]
+	if !ok {
+		return nil, nil
+	}
+	twoFactor.RecoveryCodes = append([]string(nil), twoFactor.RecoveryCodes...)
+	return &twoFactor, nil
+}
+
+func (m *MockTwoFactorRepository) GetForUpdate(ctx context.Context, userID int) (*domainauth.TwoFactor, error) {
+	return m.Get(ctx, userID)
+}
+
+func (m *MockTwoFactorRepository) Save(ctx context.Context, twoFactor *domainauth.TwoFactor) error {
+	m.TwoFactors[twoFactor.UserID] = *twoFactor
+	return nil
+}
+
+func (m *MockTwoFactorRepository) Delete(ctx context.Context, userID int) error {
+	delete(m.TwoFactors, userID)
+	return nil
+}
+
+// MockLoginChallengeRepository is an in-memory implementation of auth.LoginChallengeRepository for testing
+type MockLoginChallengeRepository struct {
+	Challenges map[int]*domainauth.LoginChallenge
+}
+
+func NewMockLoginChallengeRepository() *MockLoginChallengeRepository {
+	return &MockLoginChallengeRepository{Challenges: make(map[int]*domainauth.LoginChallenge)}
+}
+
+func (m *MockLoginChallengeRepository) Create(ctx context.Context, challenge *domainauth.LoginChallenge) error {
+	challenge.ID = len(m.Challenges) + 1
+	c := *challenge
+	m.Challenges[challenge.ID] = &c
+	return nil
+}
+
+func (m *MockLoginChallengeRepository) Get(ctx context.Context, token string) (*domainauth.LoginChallenge, error) {
+	tokenHash := domainauth.HashSessionToken(token)
+	for _, challenge := range m.Challenges {
+		if challenge.TokenHash == tokenHash && challenge.ExpiresAt.After(time.Now()) {
+			c := *challenge
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockLoginChallengeRepository) IncrementAttempts(ctx context.Context, id int) (int, error) {
+	stored, ok := m.Challenges[id]
+	if !ok {
+		return 0, nil
+	}
+	stored.Attempts++
+	return stored.Attempts, nil
+}
+
+func (m *MockLoginChallengeRepository) Delete(ctx context.Context, id int) error {
+	delete(m.Challenges, id)
+	return nil
+}
+
+// MockAPITokenRepository is an in-memory implementation of auth.APITokenRepository for testing
+type MockAPITokenRepository struct {
+	Tokens map[int]*domainauth.APIToken
+}
+
+func NewMockAPITokenRepository() *MockAPITokenRepository {
+	return &MockAPITokenRepository{Tokens: make(map[int]*domainauth.APIToken)}
+}
+
+func (m *MockAPITokenRepository) Create(ctx context.Context, token *domainauth.APIToken) error {
+	token.ID = len(m.Tokens) + 1
+	t := *token
+	m.Tokens[token.ID] = &t
+	return nil
+}
+
+func (m *MockAPITokenRepository) GetByToken(ctx context.Context, secret string) (*domainauth.APIToken, error) {
+	tokenHash := domainauth.HashSessionToken(secret)
+	for _, token := range m.Tokens {
+		if token.TokenHash == tokenHash {
+			t := *token
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAPITokenRepository) ListByUser(ctx context.Context, userID int) ([]domainauth.APIToken, error) {
+	var tokens []domainauth.APIToken
+	for _, token := range m.Tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *MockAPITokenRepository) CountByUser(ctx context.Context, userID int) (int64, error) {
+	tokens, _ := m.ListByUser(ctx, userID)
+	return int64(len(tokens)), nil
+}
+
+func (m *MockAPITokenRepository) Delete(ctx context.Context, userID int, id int) (bool, error) {
+	token, ok := m.Tokens[id]
+	if !ok || token.UserID != userID {
+		return false, nil
+	}
+	delete(m.Tokens, id)
+	return true, nil
+}
+
+func (m *MockAPITokenRepository) DeleteByUser(ctx context.Context, userID int) (int64, error) {
+	var deleted int64
+	for id, token := range m.Tokens {
+		if token.UserID == userID {
+			delete(m.Tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockAPITokenRepository) TouchLastUsed(ctx context.Context, id int, at time.Time) error {
+	if token, ok := m.Tokens[id]; ok {
+		token.LastUsedAt = &at
+	}
+	return nil
+}
+
+// MockFailureCounterRepository is an in-memory implementation of auth.FailureCounterRepository for testing
+type MockFailureCounterRepository struct {
+	mu       sync.Mutex
+	Counters map[domainauth.ThrottleKey]domainauth.FailureCounter
+}
+
+func NewMockFailureCounterRepository() *MockFailureCounterRepository {
+	return &MockFailureCounterRepository{Counters: make(map[domainauth.ThrottleKey]domainauth.FailureCounter)}
+}
+
+func (m *MockFailureCounterRepository) Get(ctx context.Context, key domainauth.ThrottleKey) (*domainauth.FailureCounter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter, ok := m.Counters[key]
+	if !ok {
+		return nil, nil
+	}
+	return &counter, nil
+}
+
+func (m *MockFailureCounterRepository) RecordFailure(ctx context.Context, key domainauth.ThrottleKey, policy domainauth.ThrottlePolicy, now time.Time) (*domainauth.FailureCounter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter := m.Counters[key]
+	counter.Key = key
+	counter.RecordFailure(policy, now)
+	m.Counters[key] = counter
+	return &counter, nil
+}
+
+func (m *MockFailureCounterRepository) Delete(ctx context.Context, key domainauth.ThrottleKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.Counters, key)
+	return nil
+}
+
+// MockInviteCodeRepository is an in-memory implementation of auth.InviteCodeRepository for testing
+type MockInviteCodeRepository struct {
+	Invites map[int]domainauth.InviteCode
+	nextID  int
+}
+
+func NewMockInviteCodeRepository(invites ...domainauth.InviteCode) *MockInviteCodeRepository {
+	m := &MockInviteCodeRepository{Invites: make(map[int]domainauth.InviteCode)}
+	for _, invite := range invites {
+		m.Invites[invite.ID] = invite
+		if invite.ID > m.nextID {
+			m.nextID = invite.ID
+		}
+	}
+	return m
+}
+
+func (m *MockInviteCodeRepository) Create(ctx context.Context, invite *domainauth.InviteCode) error {
+	m.nextID++
+	invite.ID = m.nextID
+	m.Invites[invite.ID] = *invite
+	return nil
+}
+
+func (m *MockInviteCodeRepository) Get(ctx context.Context, code string) (*domainauth.InviteCode, error) {
+	for _, invite := range m.Invites {
+		if invite.Code == domainauth.NormalizeInviteCode(code) {
+			return &invite, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockInviteCodeRepository) List(ctx context.Context, pagination common.Pagination) ([]domainauth.InviteCode, int64, error) {
+	invites := make([]domainauth.InviteCode, 0, len(m.Invites))
+	for _, invite := range m.Invites {
+		invites = append(invites, invite)
+	}
+	return invites, int64(len(invites)), nil
+}
+
+func (m *MockInviteCodeRepository) Redeem(ctx context.Context, id int) (bool, error) {
+	invite, ok := m.Invites[id]
+	if !ok || !invite.IsUsable(time.Now()) {
+		return false, nil
+	}
+	invite.Uses++
+	m.Invites[id] = invite
+	return true, nil
+}
+
+func (m *MockInviteCodeRepository) Delete(ctx context.Context, id int) (bool, error) {
+	if _, ok := m.Invites[id]; !ok {
+		return false, nil
+	}
+	delete(m.Invites, id)
+	return true, nil
+}
+
+// MockEmailChangeRepository is an in-memory implementation of auth.EmailChangeRepository for testing
+type MockEmailChangeRepository struct {
+	Changes map[int]domainauth.EmailChange
+}
+
+func NewMockEmailChangeRepository() *MockEmailChangeRepository {
+	return &MockEmailChangeRepository{Changes: make(map[int]domainauth.EmailChange)}
+}
+
+func (m *MockEmailChangeRepository) Create(ctx context.Context, change *domainauth.EmailChange) error {
+	change.ID = len(m.Changes) + 1
+	m.Changes[change.ID] = *change
+	return nil
+}
+
+func (m *MockEmailChangeRepository) GetByRevertToken(ctx context.Context, token string) (*domainauth.EmailChange, error) {
+	for _, change := range m.Changes {
+		if change.RevertTokenHash == domainauth.HashSessionToken(token) {
+			return &change, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockEmailChangeRepository) Update(ctx context.Context, change *domainauth.EmailChange) error {
+	m.Changes[change.ID] = *change
+	return nil
+}
+
+// MockEmailService records the email change notices it was asked to send
+type MockEmailService struct {
+	Notices map[string]email.EmailChangedNotice
+}
+
+func (m *MockEmailService) SendVerificationCode(ctx context.Context, emailAddr string, input *domainauth.VerificationCode) error {
+	return nil
+}
+
+func (m *MockEmailService) SendEmailChangedNotice(ctx context.Context, emailAddr string, input *email.EmailChangedNotice) error {
+	if m.Notices == nil {
+		m.Notices = make(map[string]email.EmailChangedNotice)
+	}
+	m.Notices[emailAddr] = *input
+	return nil
+}
+
+// MockRefreshTokenRepository is an in-memory implementation of auth.RefreshTokenRepository for testing
+type MockRefreshTokenRepository struct {
+	Tokens map[int]*domainauth.RefreshToken
+}
+
+func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
+	return &MockRefreshTokenRepository{Tokens: make(map[int]*domainauth.RefreshToken)}
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *domainauth.RefreshToken) error {
+	token.ID = len(m.Tokens) + 1
+	t := *token
+	m.Tokens[t.ID] = &t
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) GetByToken(ctx context.Context, secret string) (*domainauth.RefreshToken, error) {
+	for _, token := range m.Tokens {
+		if token.TokenHash == domainauth.HashSessionToken(secret) {
+			t := *token
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(ctx context.Context, id int, at time.Time) (bool, error) {
+	token, ok := m.Tokens[id]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &at
+	return true, nil
+}
+
+func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for id, token := range m.Tokens {
+		if token.ExpiresAt.Before(before) {
+			delete(m.Tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// MockAccessTokenSigner hands out opaque tokens and remembers the claims they were signed with
+type MockAccessTokenSigner struct {
+	Claims map[string]domainauth.AccessClaims
+}
+
+func NewMockAccessTokenSigner() *MockAccessTokenSigner {
+	return &MockAccessTokenSigner{Claims: make(map[string]domainauth.AccessClaims)}
+}
+
+func (m *MockAccessTokenSigner) Sign(claims domainauth.AccessClaims) (string, error) {
+	token := fmt.Sprintf("access-%d", len(m.Claims)+1)
+	m.Claims[token] = claims
+	return token, nil
+}
+
+func (m *MockAccessTokenSigner) Verify(token string, now time.Time) (*domainauth.AccessClaims, error) {
+	claims, ok := m.Claims[token]
+	if !ok || !now.Before(claims.ExpiresAt) {
+		return nil, fmt.Errorf("invalid access token")
+	}
+	return &claims, nil
+}
@@ -0,0 +1,185 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"jcourse_go/internal/application/auth"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+type OAuthCommandService interface {
+	// StartLogin remembers a new authorization request and returns the provider URL to redirect to
+	StartLogin(ctx context.Context, provider string) (string, error)
+	// CompleteLogin handles the provider's callback and links or creates the user. It opens a session,
+	// or returns a challenge when the user has two-factor authentication enabled.
+	CompleteLogin(ctx context.Context, cmd domainauth.OAuthCallbackCommand) (*LoginResult, error)
+}
+
+type oauthCommandService struct {
+	providers      auth.IdentityProviders
+	userRepo       domainauth.UserRepository
+	identityRepo   domainauth.ExternalIdentityRepository
+	stateRepo      domainauth.OAuthStateRepository
+	login          *loginFlow
+	policy         domainauth.RegistrationPolicy
+	transactor     common.Transactor
+	eventPublisher event.Publisher
+}
+
+func NewOAuthCommandService(
+	providers auth.IdentityProviders,
+	userRepo domainauth.UserRepository,
+	identityRepo domainauth.ExternalIdentityRepository,
+	stateRepo domainauth.OAuthStateRepository,
+	session domainauth.SessionRepository,
+	twoFactor domainauth.TwoFactorRepository,
+	challenges domainauth.LoginChallengeRepository,
+	policy domainauth.RegistrationPolicy,
+	transactor common.Transactor,
+	eventPublisher event.Publisher,
+) OAuthCommandService {
+	return &oauthCommandService{
+		providers:      providers,
+		userRepo:       userRepo,
+		identityRepo:   identityRepo,
+		stateRepo:      stateRepo,
+		login:          &loginFlow{session: session, twoFactor: twoFactor, challenges: challenges},
+		policy:         policy,
+		transactor:     transactor,
+		eventPublisher: eventPublisher,
+	}
+}
+
+func (s *oauthCommandService) StartLogin(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", apperror.ErrNotFound.WithMessage("unknown identity provider").WithMetadata("provider", providerName)
+	}
+
+	state := domainauth.NewOAuthState(providerName, time.Now())
+	if err := s.stateRepo.Save(ctx, &state); err != nil {
+		return "", apperror.WrapDB(err).WithMetadata("operation", "start_oauth_login").WithMetadata("provider", providerName)
+	}
+	return provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()), nil
+}
+
+func (s *oauthCommandService) CompleteLogin(ctx context.Context, cmd domainauth.OAuthCallbackCommand) (*LoginResult, error) {
+	provider, ok := s.providers[cmd.Provider]
+	if !ok {
+		return nil, apperror.ErrNotFound.WithMessage("unknown identity provider").WithMetadata("provider", cmd.Provider)
+	}
+
+	state, err := s.stateRepo.Take(ctx, cmd.State)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "complete_oauth_login").WithMetadata("provider", cmd.Provider)
+	}
+	if state == nil || state.Provider != cmd.Provider || state.IsExpired(time.Now()) {
+		return nil, apperror.ErrWrongAuth.WithUserMessage("Login request is invalid or has expired").WithMetadata("provider", cmd.Provider)
+	}
+
+	claims, err := provider.Exchange(ctx, cmd.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, apperror.ErrWrongAuth.Wrap(err).WithMetadata("operation", "oauth_exchange").WithMetadata("provider", cmd.Provider)
+	}
+
+	var userID int
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userID, err = s.resolveUser(ctx, cmd.Provider, claims)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "oauth_login").WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+	if user.IsSuspended() {
+		return nil, apperror.ErrSuspended.WithMetadata("user_id", userID)
+	}
+
+	return s.login.begin(ctx, userID, cmd.Session)
+}
+
+// resolveUser returns the user linked to the provider's subject. An unlinked subject is linked to the user
+// with the same email, or to a new user, but only when the provider has verified the email.
+func (s *oauthCommandService) resolveUser(ctx context.Context, provider string, claims *domainauth.IdentityClaims) (int, error) {
+	identity, err := s.identityRepo.Get(ctx, provider, claims.Subject)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "get_external_identity").WithMetadata("provider", provider)
+	}
+	if identity != nil {
+		return identity.UserID, nil
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return 0, apperror.ErrWrongAuth.WithUserMessage("The identity provider did not confirm your email address").
+			WithMetadata("provider", provider)
+	}
+
+	user, err := s.userRepo.Get(ctx, claims.Email)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "oauth_login").WithMetadata("email", claims.Email)
+	}
+
+	userID := 0
+	if user != nil {
+		userID = user.ID
+	} else if userID, err = s.createUser(ctx, claims); err != nil {
+		return 0, err
+	}
+
+	identity = &domainauth.ExternalIdentity{
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: time.Now(),
+	}
+	if err := s.identityRepo.Save(ctx, identity); err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "link_external_identity").WithMetadata("user_id", userID)
+	}
+	return userID, nil
+}
+
+// createUser registers a user without a password; one can be set later through a password reset.
+// There is no way to present an invite code here, so only emails that need none can register.
+func (s *oauthCommandService) createUser(ctx context.Context, claims *domainauth.IdentityClaims) (int, error) {
+	if s.policy.IsBlocked(claims.Email) || s.policy.RequiresInvite(claims.Email) {
+		return 0, apperror.ErrPermission.WithUserMessage("Registration is not open to this account").
+			WithMetadata("email", claims.Email)
+	}
+
+	now := time.Now()
+	username := claims.Name
+	if username == "" {
+		username = claims.Email
+	}
+	user := &domainauth.User{
+		Username:   username,
+		Email:      claims.Email,
+		Role:       common.RoleUser,
+		LastSeenAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	userID, err := s.userRepo.Save(ctx, user)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "oauth_register").WithMetadata("email", claims.Email)
+	}
+
+	payload := &event.UserPayload{UserID: userID}
+	if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeUserCreated, payload)); err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "publish_user_created_event").WithMetadata("user_id", userID)
+	}
+	return userID, nil
+}
@@ -0,0 +1,199 @@
+package command
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/application/auth"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+type oauthTestFixture struct {
+	service    OAuthCommandService
+	provider   *MockIdentityProvider
+	users      *MockUserRepository
+	identities *MockExternalIdentityRepository
+	states     *MockOAuthStateRepository
+	sessions   *MockSessionRepository
+	publisher  *MockPublisher
+}
+
+func newOAuthTestFixture(claims domainauth.IdentityClaims, users ...domainauth.User) *oauthTestFixture {
+	f := &oauthTestFixture{
+		provider:   &MockIdentityProvider{Claims: claims},
+		users:      NewMockUserRepository(users...),
+		identities: &MockExternalIdentityRepository{},
+		states:     NewMockOAuthStateRepository(),
+		sessions:   NewMockSessionRepository(),
+		publisher:  &MockPublisher{},
+	}
+	f.service = NewOAuthCommandService(
+		auth.NewIdentityProviders(f.provider),
+		f.users, f.identities, f.states, f.sessions, NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(), domainauth.RegistrationPolicy{}, &MockTransactor{}, f.publisher,
+	)
+	return f
+}
+
+// start begins a login and returns the state the provider redirects back with
+func (f *oauthTestFixture) start(t *testing.T) string {
+	authURL, err := f.service.StartLogin(context.Background(), "mock")
+	assert.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	return parsed.Query().Get("state")
+}
+
+func (f *oauthTestFixture) callback(state string) (string, error) {
+	result, err := f.service.CompleteLogin(context.Background(), domainauth.OAuthCallbackCommand{
+		Provider: "mock",
+		Code:     "code",
+		State:    state,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.SessionID, nil
+}
+
+func TestOAuthCommandService_StartLoginUsesPKCE(t *testing.T) {
+	f := newOAuthTestFixture(domainauth.IdentityClaims{})
+
+	authURL, err := f.service.StartLogin(context.Background(), "mock")
+
+	assert.NoError(t, err)
+	parsed, _ := url.Parse(authURL)
+	stored, ok := f.states.States[parsed.Query().Get("state")]
+	assert.True(t, ok)
+	assert.Equal(t, stored.CodeChallenge(), parsed.Query().Get("code_challenge"))
+	assert.NotEqual(t, stored.CodeVerifier, parsed.Query().Get("code_challenge"))
+}
+
+func TestOAuthCommandService_StartLoginUnknownProvider(t *testing.T) {
+	f := newOAuthTestFixture(domainauth.IdentityClaims{})
+
+	_, err := f.service.StartLogin(context.Background(), "unknown")
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrNotFound.Code, appErr.Code)
+}
+
+func TestOAuthCommandService_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	f := newOAuthTestFixture(
+		domainauth.IdentityClaims{Subject: "alice", Email: "alice@sjtu.edu.cn", EmailVerified: true},
+		domainauth.User{ID: 7, Email: "alice@sjtu.edu.cn", Role: common.RoleUser},
+	)
+	state := f.start(t)
+
+	token, err := f.callback(state)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, f.sessions.Sessions[token].UserID)
+	assert.Empty(t, f.states.States)
+	if assert.Len(t, f.identities.Identities, 1) {
+		assert.Equal(t, 7, f.identities.Identities[0].UserID)
+		assert.Equal(t, "mock", f.identities.Identities[0].Provider)
+	}
+	assert.Empty(t, f.publisher.Events)
+	assert.NotEmpty(t, f.provider.Nonce)
+	assert.NotEmpty(t, f.provider.CodeVerifier)
+}
+
+func TestOAuthCommandService_CreatesUserForNewIdentity(t *testing.T) {
+	f := newOAuthTestFixture(domainauth.IdentityClaims{Subject: "bob", Email: "bob@sjtu.edu.cn", EmailVerified: true, Name: "Bob"})
+	state := f.start(t)
+
+	token, err := f.callback(state)
+
+	assert.NoError(t, err)
+	userID := f.sessions.Sessions[token].UserID
+	assert.Equal(t, "bob@sjtu.edu.cn", f.users.Users[userID].Email)
+	assert.Equal(t, "Bob", f.users.Users[userID].Username)
+	if assert.Len(t, f.publisher.Events, 1) {
+		assert.Equal(t, event.TypeUserCreated, f.publisher.Events[0].Type())
+	}
+}
+
+func TestOAuthCommandService_UsesLinkedIdentity(t *testing.T) {
+	// The provider's email changed after linking, the link still decides
+	f := newOAuthTestFixture(
+		domainauth.IdentityClaims{Subject: "alice", Email: "renamed@sjtu.edu.cn", EmailVerified: true},
+		domainauth.User{ID: 7, Email: "alice@sjtu.edu.cn", Role: common.RoleUser},
+	)
+	f.identities.Identities = []domainauth.ExternalIdentity{{ID: 1, UserID: 7, Provider: "mock", Subject: "alice"}}
+	state := f.start(t)
+
+	token, err := f.callback(state)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, f.sessions.Sessions[token].UserID)
+	assert.Len(t, f.identities.Identities, 1)
+	assert.Len(t, f.users.Users, 1)
+}
+
+func TestOAuthCommandService_RejectsUnverifiedEmail(t *testing.T) {
+	f := newOAuthTestFixture(
+		domainauth.IdentityClaims{Subject: "mallory", Email: "alice@sjtu.edu.cn", EmailVerified: false},
+		domainauth.User{ID: 7, Email: "alice@sjtu.edu.cn", Role: common.RoleUser},
+	)
+	state := f.start(t)
+
+	_, err := f.callback(state)
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrWrongAuth.Code, appErr.Code)
+	assert.Empty(t, f.identities.Identities)
+	assert.Empty(t, f.sessions.Sessions)
+}
+
+func TestOAuthCommandService_RejectsInvalidState(t *testing.T) {
+	f := newOAuthTestFixture(domainauth.IdentityClaims{Subject: "bob", Email: "bob@sjtu.edu.cn", EmailVerified: true})
+	state := f.start(t)
+	_, err := f.callback(state)
+	assert.NoError(t, err)
+
+	expired := domainauth.NewOAuthState("mock", time.Now().Add(-time.Hour))
+	f.states.States[expired.State] = expired
+
+	tests := map[string]string{
+		"unknown": "forged",
+		"reused":  state,
+		"expired": expired.State,
+	}
+	for name, state := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := f.callback(state)
+
+			var appErr *apperror.AppError
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, apperror.ErrWrongAuth.Code, appErr.Code)
+		})
+	}
+	assert.Len(t, f.sessions.Sessions, 1)
+}
+
+func TestOAuthCommandService_RefusesRegistrationOutsidePolicy(t *testing.T) {
+	f := newOAuthTestFixture(domainauth.IdentityClaims{Subject: "bob", Email: "bob@example.com", EmailVerified: true})
+	f.service = NewOAuthCommandService(
+		auth.NewIdentityProviders(f.provider),
+		f.users, f.identities, f.states, f.sessions, NewMockTwoFactorRepository(), NewMockLoginChallengeRepository(),
+		domainauth.RegistrationPolicy{AllowedDomains: []string{"sjtu.edu.cn"}}, &MockTransactor{}, f.publisher,
+	)
+	state := f.start(t)
+
+	_, err := f.callback(state)
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrPermission.Code, appErr.Code)
+	assert.Empty(t, f.users.Users)
+	assert.Empty(t, f.sessions.Sessions)
+}
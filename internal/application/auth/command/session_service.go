@@ -0,0 +1,128 @@
+package command
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type SessionCommandService interface {
+	// Authenticate resolves the session token to its user and records the activity, sliding the session's expiry
+	Authenticate(ctx context.Context, token string) (*common.User, error)
+	RevokeSession(commonCtx *common.CommonContext, sessionID int) error
+	// RevokeOtherSessions signs out every device except the current one and returns how many sessions were revoked
+	RevokeOtherSessions(commonCtx *common.CommonContext) (int64, error)
+}
+
+type sessionCommandService struct {
+	userRepo      domainauth.UserRepository
+	sessionRepo   domainauth.SessionRepository
+	twoFactorRepo domainauth.TwoFactorRepository
+}
+
+func NewSessionCommandService(
+	userRepo domainauth.UserRepository,
+	sessionRepo domainauth.SessionRepository,
+	twoFactorRepo domainauth.TwoFactorRepository,
+) SessionCommandService {
+	return &sessionCommandService{
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+func (s *sessionCommandService) Authenticate(ctx context.Context, token string) (*common.User, error) {
+	session, err := s.sessionRepo.Get(ctx, token)
+	if err != nil {
+		return nil, apperror.ErrSession.Wrap(err).WithMetadata("operation", "authenticate")
+	}
+	if session == nil {
+		return nil, apperror.ErrSession.WithMessage("session not found or expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "authenticate").WithMetadata("user_id", session.UserID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", session.UserID)
+	}
+
+	if now := time.Now(); session.NeedsTouch(now) {
+		s.touch(ctx, session, now)
+	}
+
+	authenticated, err := newAuthenticatedUser(ctx, s.twoFactorRepo, user)
+	if err != nil {
+		return nil, err
+	}
+	authenticated.SessionID = session.ID
+	return authenticated, nil
+}
+
+// newAuthenticatedUser returns the request user, refusing suspended ones. Two-factor authentication
+// is mandatory for admins: until they enroll, they only get the rights of a user.
+func newAuthenticatedUser(ctx context.Context, twoFactorRepo domainauth.TwoFactorRepository, user *domainauth.User) (*common.User, error) {
+	if user.IsSuspended() {
+		return nil, apperror.ErrSuspended.WithMetadata("user_id", user.ID)
+	}
+
+	authenticated := &common.User{
+		UserID: user.ID,
+		Role:   user.Role,
+	}
+
+	if user.IsAdmin() {
+		twoFactor, err := twoFactorRepo.Get(ctx, user.ID)
+		if err != nil {
+			return nil, apperror.WrapDB(err).WithMetadata("operation", "authenticate").WithMetadata("user_id", user.ID)
+		}
+		if !twoFactor.IsEnabled() {
+			authenticated.Role = common.RoleUser
+			authenticated.TwoFactorPending = true
+		}
+	}
+	return authenticated, nil
+}
+
+// touch records the activity at most once per SessionTouchInterval; failures are only logged
+// since the request itself is authenticated either way
+func (s *sessionCommandService) touch(ctx context.Context, session *domainauth.Session, now time.Time) {
+	session.Touch(now)
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		log.Printf("Failed to extend session %d: %v", session.ID, err)
+	}
+	if err := s.userRepo.TouchLastSeen(ctx, session.UserID, now); err != nil {
+		log.Printf("Failed to record last seen of user %d: %v", session.UserID, err)
+	}
+}
+
+func (s *sessionCommandService) RevokeSession(commonCtx *common.CommonContext, sessionID int) error {
+	userID := commonCtx.User.UserID
+	deleted, err := s.sessionRepo.DeleteByID(commonCtx.Ctx, userID, sessionID)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revoke_session").WithMetadata("session_id", sessionID)
+	}
+	if !deleted {
+		return apperror.ErrNotFound.WithMessage("session not found").WithMetadata("session_id", sessionID)
+	}
+	return nil
+}
+
+func (s *sessionCommandService) RevokeOtherSessions(commonCtx *common.CommonContext) (int64, error) {
+	if commonCtx.User.SessionID == 0 {
+		return 0, apperror.ErrSession.WithMessage("current session is unknown")
+	}
+
+	revoked, err := s.sessionRepo.DeleteOthers(commonCtx.Ctx, commonCtx.User.UserID, commonCtx.User.SessionID)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "revoke_other_sessions").
+			WithMetadata("user_id", commonCtx.User.UserID)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+func TestSessionCommandService_AuthenticateSlidesExpiry(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	sessions := NewMockSessionRepository()
+	token, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	stale := time.Now().Add(-time.Hour)
+	sessions.Sessions[token].LastSeenAt = stale
+	sessions.Sessions[token].ExpiresAt = stale.Add(domainauth.SessionIdleTimeout)
+	service := NewSessionCommandService(users, sessions, NewMockTwoFactorRepository())
+
+	user, err := service.Authenticate(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.UserID)
+	assert.Equal(t, sessions.Sessions[token].ID, user.SessionID)
+	assert.True(t, sessions.Sessions[token].LastSeenAt.After(stale))
+	assert.True(t, sessions.Sessions[token].ExpiresAt.After(stale.Add(domainauth.SessionIdleTimeout)))
+	assert.False(t, users.Users[1].LastSeenAt.IsZero())
+}
+
+func TestSessionCommandService_AuthenticateThrottlesTouch(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	sessions := NewMockSessionRepository()
+	token, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	lastSeen := sessions.Sessions[token].LastSeenAt
+	service := NewSessionCommandService(users, sessions, NewMockTwoFactorRepository())
+
+	_, err := service.Authenticate(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, lastSeen, sessions.Sessions[token].LastSeenAt)
+	assert.True(t, users.Users[1].LastSeenAt.IsZero())
+}
+
+func TestSessionCommandService_AuthenticateUnknownToken(t *testing.T) {
+	service := NewSessionCommandService(NewMockUserRepository(), NewMockSessionRepository(), NewMockTwoFactorRepository())
+
+	_, err := service.Authenticate(context.Background(), "unknown")
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrSession.Code, appErr.Code)
+}
+
+func TestSessionCommandService_AuthenticateSuspendedUser(t *testing.T) {
+	now := time.Now()
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser, SuspendedAt: &now})
+	sessions := NewMockSessionRepository()
+	token, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	service := NewSessionCommandService(users, sessions, NewMockTwoFactorRepository())
+
+	_, err := service.Authenticate(context.Background(), token)
+
+	assertAppErrorCode(t, apperror.ErrSuspended, err)
+}
+
+func TestSessionCommandService_RevokeSession(t *testing.T) {
+	sessions := NewMockSessionRepository()
+	own, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	other, _ := sessions.Store(context.Background(), 2, domainauth.SessionMetadata{})
+	service := NewSessionCommandService(NewMockUserRepository(), sessions, NewMockTwoFactorRepository())
+	commonCtx := common.NewCommonContext(context.Background(), &common.User{UserID: 1, Role: common.RoleUser})
+
+	// Sessions of other users cannot be revoked
+	err := service.RevokeSession(commonCtx, sessions.Sessions[other].ID)
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrNotFound.Code, appErr.Code)
+	assert.Contains(t, sessions.Sessions, other)
+
+	err = service.RevokeSession(commonCtx, sessions.Sessions[own].ID)
+	assert.NoError(t, err)
+	assert.NotContains(t, sessions.Sessions, own)
+}
+
+func TestSessionCommandService_RevokeOtherSessions(t *testing.T) {
+	sessions := NewMockSessionRepository()
+	current, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	_, _ = sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	_, _ = sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	foreign, _ := sessions.Store(context.Background(), 2, domainauth.SessionMetadata{})
+	service := NewSessionCommandService(NewMockUserRepository(), sessions, NewMockTwoFactorRepository())
+	commonCtx := common.NewCommonContext(context.Background(), &common.User{
+		UserID:    1,
+		Role:      common.RoleUser,
+		SessionID: sessions.Sessions[current].ID,
+	})
+
+	revoked, err := service.RevokeOtherSessions(commonCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), revoked)
+	assert.Contains(t, sessions.Sessions, current)
+	assert.Contains(t, sessions.Sessions, foreign)
+	assert.Len(t, sessions.Sessions, 2)
+}
@@ -0,0 +1,206 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+// TokenPair is what a client holds in token mode: a short-lived access token and the refresh token that renews it
+type TokenPair struct {
+	AccessToken  string
+	ExpiresAt    time.Time
+	RefreshToken string
+}
+
+// TokenCommandService implements token mode. Every login still opens a session, which the refresh tokens
+// belong to; revoking the session, as logout and password resets do, ends the refresh tokens with it.
+type TokenCommandService interface {
+	// IssueTokens trades the token of a freshly opened session for an access token and its first refresh token
+	IssueTokens(ctx context.Context, sessionToken string) (*TokenPair, error)
+	// Refresh exchanges a refresh token for a new pair. A refresh token that was already used is taken
+	// as stolen, so the session and every refresh token issued for it are revoked.
+	Refresh(ctx context.Context, cmd domainauth.RefreshTokenCommand) (*TokenPair, error)
+	// Revoke ends the session of the refresh token
+	Revoke(ctx context.Context, cmd domainauth.RefreshTokenCommand) error
+	// Authenticate verifies a signed access token without a database lookup
+	Authenticate(ctx context.Context, accessToken string) (*common.User, error)
+	// PurgeExpired removes refresh tokens that expired before the given time and returns how many were removed
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type tokenCommandService struct {
+	userRepo         domainauth.UserRepository
+	sessionRepo      domainauth.SessionRepository
+	refreshTokenRepo domainauth.RefreshTokenRepository
+	twoFactorRepo    domainauth.TwoFactorRepository
+	signer           domainauth.AccessTokenSigner
+	transactor       common.Transactor
+}
+
+func NewTokenCommandService(
+	userRepo domainauth.UserRepository,
+	sessionRepo domainauth.SessionRepository,
+	refreshTokenRepo domainauth.RefreshTokenRepository,
+	twoFactorRepo domainauth.TwoFactorRepository,
+	signer domainauth.AccessTokenSigner,
+	transactor common.Transactor,
+) TokenCommandService {
+	return &tokenCommandService{
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		twoFactorRepo:    twoFactorRepo,
+		signer:           signer,
+		transactor:       transactor,
+	}
+}
+
+// errRefreshTokenReused aborts the exchange of a token that was used concurrently
+var errRefreshTokenReused = errors.New("refresh token reused")
+
+func (s *tokenCommandService) IssueTokens(ctx context.Context, sessionToken string) (*TokenPair, error) {
+	session, err := s.sessionRepo.Get(ctx, sessionToken)
+	if err != nil {
+		return nil, apperror.ErrSession.Wrap(err).WithMetadata("operation", "issue_tokens")
+	}
+	if session == nil {
+		return nil, apperror.ErrSession.WithMessage("session not found or expired")
+	}
+	return s.issue(ctx, session, time.Now())
+}
+
+func (s *tokenCommandService) Refresh(ctx context.Context, cmd domainauth.RefreshTokenCommand) (*TokenPair, error) {
+	token, err := s.refreshTokenRepo.GetByToken(ctx, cmd.RefreshToken)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "refresh_token")
+	}
+	if token == nil {
+		return nil, apperror.ErrSession.WithMessage("refresh token not found")
+	}
+
+	now := time.Now()
+	if token.IsUsed() {
+		return nil, s.revokeFamily(ctx, token)
+	}
+	if token.IsExpired(now) {
+		return nil, apperror.ErrSession.WithMessage("refresh token expired")
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, token.SessionID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "refresh_token").WithMetadata("session_id", token.SessionID)
+	}
+	if session == nil {
+		return nil, apperror.ErrSession.WithMessage("session not found or expired")
+	}
+
+	var pair *TokenPair
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		marked, err := s.refreshTokenRepo.MarkUsed(ctx, token.ID, now)
+		if err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "refresh_token").WithMetadata("session_id", session.ID)
+		}
+		if !marked {
+			return errRefreshTokenReused
+		}
+
+		// Refreshing is activity; the next refresh token lives as long as the extended session
+		session.Touch(now)
+		if err := s.sessionRepo.Update(ctx, session); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "refresh_token").WithMetadata("session_id", session.ID)
+		}
+
+		pair, err = s.issue(ctx, session, now)
+		return err
+	})
+	if errors.Is(err, errRefreshTokenReused) {
+		return nil, s.revokeFamily(ctx, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.TouchLastSeen(ctx, session.UserID, now); err != nil {
+		log.Printf("Failed to record last seen of user %d: %v", session.UserID, err)
+	}
+	return pair, nil
+}
+
+// revokeFamily ends the session of a refresh token that was presented again after its exchange
+func (s *tokenCommandService) revokeFamily(ctx context.Context, token *domainauth.RefreshToken) error {
+	if _, err := s.sessionRepo.DeleteByID(ctx, token.UserID, token.SessionID); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revoke_token_family").WithMetadata("session_id", token.SessionID)
+	}
+	return apperror.ErrSession.WithMessage("refresh token reused, session revoked").
+		WithMetadata("session_id", token.SessionID)
+}
+
+// issue signs an access token for the user of the session and stores the next refresh token
+func (s *tokenCommandService) issue(ctx context.Context, session *domainauth.Session, now time.Time) (*TokenPair, error) {
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "issue_tokens").WithMetadata("user_id", session.UserID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", session.UserID)
+	}
+	authenticated, err := newAuthenticatedUser(ctx, s.twoFactorRepo, user)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, secret := domainauth.NewRefreshToken(session, now)
+	if err := s.refreshTokenRepo.Create(ctx, &refreshToken); err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "issue_tokens").WithMetadata("session_id", session.ID)
+	}
+
+	claims := domainauth.NewAccessClaims(authenticated, session.ID, now)
+	accessToken, err := s.signer.Sign(claims)
+	if err != nil {
+		return nil, apperror.ErrSession.Wrap(err).WithMetadata("operation", "issue_tokens").WithMetadata("session_id", session.ID)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		ExpiresAt:    claims.ExpiresAt,
+		RefreshToken: secret,
+	}, nil
+}
+
+func (s *tokenCommandService) Revoke(ctx context.Context, cmd domainauth.RefreshTokenCommand) error {
+	token, err := s.refreshTokenRepo.GetByToken(ctx, cmd.RefreshToken)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revoke_refresh_token")
+	}
+	// Like logging out, revoking an unknown token succeeds
+	if token == nil {
+		return nil
+	}
+
+	if _, err := s.sessionRepo.DeleteByID(ctx, token.UserID, token.SessionID); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revoke_refresh_token").WithMetadata("session_id", token.SessionID)
+	}
+	return nil
+}
+
+func (s *tokenCommandService) Authenticate(ctx context.Context, accessToken string) (*common.User, error) {
+	claims, err := s.signer.Verify(accessToken, time.Now())
+	if err != nil {
+		return nil, apperror.ErrSession.Wrap(err).WithMessage("access token is invalid or has expired")
+	}
+	return claims.User(), nil
+}
+
+func (s *tokenCommandService) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.refreshTokenRepo.DeleteExpired(ctx, before)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "purge_refresh_tokens")
+	}
+	return deleted, nil
+}
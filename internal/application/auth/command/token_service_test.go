@@ -0,0 +1,105 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type tokenFixture struct {
+	users         *MockUserRepository
+	sessions      *MockSessionRepository
+	refreshTokens *MockRefreshTokenRepository
+	service       TokenCommandService
+	sessionToken  string
+}
+
+func newTokenFixture() *tokenFixture {
+	f := &tokenFixture{
+		users:         NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser}),
+		sessions:      NewMockSessionRepository(),
+		refreshTokens: NewMockRefreshTokenRepository(),
+	}
+	f.sessionToken, _ = f.sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	f.service = NewTokenCommandService(f.users, f.sessions, f.refreshTokens, NewMockTwoFactorRepository(), NewMockAccessTokenSigner(), &MockTransactor{})
+	return f
+}
+
+func TestTokenCommandService_IssueAndAuthenticate(t *testing.T) {
+	f := newTokenFixture()
+
+	pair, err := f.service.IssueTokens(context.Background(), f.sessionToken)
+
+	assert.NoError(t, err)
+	assert.Contains(t, pair.RefreshToken, domainauth.RefreshTokenPrefix)
+	assert.WithinDuration(t, time.Now().Add(domainauth.AccessTokenTTL), pair.ExpiresAt, time.Minute)
+
+	user, err := f.service.Authenticate(context.Background(), pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.UserID)
+	assert.Equal(t, common.RoleUser, user.Role)
+	assert.Equal(t, f.sessions.Sessions[f.sessionToken].ID, user.SessionID)
+
+	_, err = f.service.Authenticate(context.Background(), "forged")
+	assertAppErrorCode(t, apperror.ErrSession, err)
+}
+
+func TestTokenCommandService_RefreshRotates(t *testing.T) {
+	f := newTokenFixture()
+	first, _ := f.service.IssueTokens(context.Background(), f.sessionToken)
+
+	second, err := f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: first.RefreshToken})
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	assert.NotEqual(t, first.AccessToken, second.AccessToken)
+	assert.NotNil(t, f.refreshTokens.Tokens[1].UsedAt)
+	assert.Nil(t, f.refreshTokens.Tokens[2].UsedAt)
+
+	_, err = f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: second.RefreshToken})
+	assert.NoError(t, err)
+}
+
+func TestTokenCommandService_ReusedRefreshTokenRevokesFamily(t *testing.T) {
+	f := newTokenFixture()
+	first, _ := f.service.IssueTokens(context.Background(), f.sessionToken)
+	second, _ := f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: first.RefreshToken})
+
+	_, err := f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: first.RefreshToken})
+
+	assertAppErrorCode(t, apperror.ErrSession, err)
+	assert.Empty(t, f.sessions.Sessions)
+
+	// The token the legitimate client holds ends with the session
+	_, err = f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: second.RefreshToken})
+	assertAppErrorCode(t, apperror.ErrSession, err)
+}
+
+func TestTokenCommandService_RefreshRefusesSuspendedUser(t *testing.T) {
+	f := newTokenFixture()
+	pair, _ := f.service.IssueTokens(context.Background(), f.sessionToken)
+	f.users.Users[1].Suspend("spam", nil, time.Now())
+
+	_, err := f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: pair.RefreshToken})
+
+	assertAppErrorCode(t, apperror.ErrSuspended, err)
+}
+
+func TestTokenCommandService_Revoke(t *testing.T) {
+	f := newTokenFixture()
+	pair, _ := f.service.IssueTokens(context.Background(), f.sessionToken)
+
+	assert.NoError(t, f.service.Revoke(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: pair.RefreshToken}))
+	assert.Empty(t, f.sessions.Sessions)
+
+	_, err := f.service.Refresh(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: pair.RefreshToken})
+	assertAppErrorCode(t, apperror.ErrSession, err)
+
+	assert.NoError(t, f.service.Revoke(context.Background(), domainauth.RefreshTokenCommand{RefreshToken: "unknown"}))
+}
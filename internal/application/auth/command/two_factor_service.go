@@ -0,0 +1,252 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+	"jcourse_go/pkg/totp"
+)
+
+// TOTPIssuer names the account in authenticator apps
+const TOTPIssuer = "jCourse"
+
+type TwoFactorCommandService interface {
+	// Enroll generates a new TOTP secret for the current user; it only takes effect once confirmed
+	Enroll(commonCtx *common.CommonContext) (*viewobject.TwoFactorEnrollmentVO, error)
+	// Confirm enables two-factor authentication with a code from the app and returns the recovery codes,
+	// which are only shown this once. Every other session of the user is signed out.
+	Confirm(commonCtx *common.CommonContext, code string) ([]string, error)
+	// Disable turns two-factor authentication off after checking a code or a recovery code
+	Disable(commonCtx *common.CommonContext, code string) error
+	// VerifyLogin completes a login challenge with a code or a recovery code and returns the session token
+	VerifyLogin(ctx context.Context, cmd domainauth.VerifyTwoFactorCommand) (string, error)
+}
+
+type twoFactorCommandService struct {
+	userRepo      domainauth.UserRepository
+	twoFactorRepo domainauth.TwoFactorRepository
+	challengeRepo domainauth.LoginChallengeRepository
+	session       domainauth.SessionRepository
+	throttle      *loginThrottle
+	transactor    common.Transactor
+}
+
+func NewTwoFactorCommandService(
+	userRepo domainauth.UserRepository,
+	twoFactorRepo domainauth.TwoFactorRepository,
+	challengeRepo domainauth.LoginChallengeRepository,
+	session domainauth.SessionRepository,
+	failures domainauth.FailureCounterRepository,
+	transactor common.Transactor,
+) TwoFactorCommandService {
+	return &twoFactorCommandService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		challengeRepo: challengeRepo,
+		session:       session,
+		throttle:      &loginThrottle{counters: failures},
+		transactor:    transactor,
+	}
+}
+
+func (s *twoFactorCommandService) Enroll(commonCtx *common.CommonContext) (*viewobject.TwoFactorEnrollmentVO, error) {
+	ctx, userID := commonCtx.Ctx, commonCtx.User.UserID
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "enroll_two_factor").WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "enroll_two_factor").WithMetadata("user_id", userID)
+	}
+	if twoFactor.IsEnabled() {
+		return nil, apperror.ErrWrongInput.WithMessage("two-factor authentication is already enabled")
+	}
+
+	now := time.Now()
+	twoFactor = &domainauth.TwoFactor{
+		UserID:    userID,
+		Secret:    totp.GenerateSecret(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "enroll_two_factor").WithMetadata("user_id", userID)
+	}
+
+	return &viewobject.TwoFactorEnrollmentVO{
+		Secret:          twoFactor.Secret,
+		ProvisioningURI: totp.ProvisioningURI(TOTPIssuer, user.Email, twoFactor.Secret),
+	}, nil
+}
+
+func (s *twoFactorCommandService) Confirm(commonCtx *common.CommonContext, code string) ([]string, error) {
+	ctx, userID := commonCtx.Ctx, commonCtx.User.UserID
+
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "confirm_two_factor").WithMetadata("user_id", userID)
+	}
+	if twoFactor == nil {
+		return nil, apperror.ErrNotFound.WithMessage("no two-factor enrollment to confirm")
+	}
+	if twoFactor.IsEnabled() {
+		return nil, apperror.ErrWrongInput.WithMessage("two-factor authentication is already enabled")
+	}
+
+	// Only a code from the app proves the secret was saved; there are no recovery codes yet
+	now := time.Now()
+	step, ok := totp.Validate(twoFactor.Secret, code, now)
+	if !ok || !twoFactor.UseStep(step) {
+		return nil, apperror.ErrWrongAuth.WithUserMessage("Invalid two-factor code")
+	}
+
+	codes, hashes := domainauth.NewRecoveryCodes()
+	twoFactor.RecoveryCodes = hashes
+	twoFactor.EnabledAt = &now
+	twoFactor.UpdatedAt = now
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "confirm_two_factor").WithMetadata("user_id", userID)
+		}
+		// Sessions opened with the password alone are not trusted any more
+		if _, err := s.session.DeleteOthers(ctx, userID, commonCtx.User.SessionID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revoke_other_sessions").WithMetadata("user_id", userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *twoFactorCommandService) Disable(commonCtx *common.CommonContext, code string) error {
+	ctx, userID := commonCtx.Ctx, commonCtx.User.UserID
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "disable_two_factor").WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+	if user.IsAdmin() {
+		return apperror.ErrPermission.WithMessage("two-factor authentication is mandatory for admins")
+	}
+
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "disable_two_factor").WithMetadata("user_id", userID)
+	}
+	if !twoFactor.IsEnabled() {
+		return apperror.ErrWrongInput.WithMessage("two-factor authentication is not enabled")
+	}
+	if !verifyTwoFactorCode(twoFactor, code, time.Now()) {
+		return apperror.ErrWrongAuth.WithUserMessage("Invalid two-factor code")
+	}
+
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "disable_two_factor").WithMetadata("user_id", userID)
+	}
+	return nil
+}
+
+// These roll back the transaction of VerifyLogin, which then handles them outside of it
+var (
+	errWrongTwoFactorCode = errors.New("wrong two-factor code")
+	errTwoFactorDisabled  = errors.New("two-factor authentication was disabled")
+)
+
+func (s *twoFactorCommandService) VerifyLogin(ctx context.Context, cmd domainauth.VerifyTwoFactorCommand) (string, error) {
+	challenge, err := s.challengeRepo.Get(ctx, cmd.ChallengeToken)
+	if err != nil {
+		return "", apperror.WrapDB(err).WithMetadata("operation", "verify_two_factor_login")
+	}
+	if challenge == nil {
+		return "", apperror.ErrWrongAuth.WithUserMessage("Login challenge is invalid or has expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return "", apperror.WrapDB(err).WithMetadata("operation", "verify_two_factor_login").WithMetadata("user_id", challenge.UserID)
+	}
+	if user == nil {
+		return "", apperror.ErrWrongAuth.WithUserMessage("Login challenge is invalid or has expired")
+	}
+	// Wrong codes count against the same lockout as wrong passwords, so new challenges do not give new guesses
+	if err := s.throttle.check(ctx, user.Email, challenge.Session.IP); err != nil {
+		return "", err
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The row stays locked until the used step or recovery code is saved, so a code is accepted only once
+		twoFactor, err := s.twoFactorRepo.GetForUpdate(ctx, challenge.UserID)
+		if err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "verify_two_factor_login").WithMetadata("user_id", challenge.UserID)
+		}
+		if !twoFactor.IsEnabled() {
+			return errTwoFactorDisabled
+		}
+		if !verifyTwoFactorCode(twoFactor, cmd.Code, time.Now()) {
+			return errWrongTwoFactorCode
+		}
+
+		if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "verify_two_factor_login").WithMetadata("user_id", challenge.UserID)
+		}
+		if err := s.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "delete_login_challenge").WithMetadata("user_id", challenge.UserID)
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errTwoFactorDisabled):
+		// Disabled meanwhile; the user has to log in again
+		_ = s.challengeRepo.Delete(ctx, challenge.ID)
+		return "", apperror.ErrWrongAuth.WithUserMessage("Login challenge is invalid or has expired")
+	case errors.Is(err, errWrongTwoFactorCode):
+		s.throttle.recordFailure(ctx, user.Email, challenge.Session.IP)
+		return "", s.recordFailedAttempt(ctx, challenge)
+	case err != nil:
+		return "", err
+	}
+
+	s.throttle.reset(ctx, user.Email)
+	sessionID, err := s.session.Store(ctx, challenge.UserID, challenge.Session)
+	if err != nil {
+		return "", apperror.ErrSession.Wrap(err).WithMetadata("operation", "verify_two_factor_login").WithMetadata("user_id", challenge.UserID)
+	}
+	return sessionID, nil
+}
+
+// recordFailedAttempt counts a wrong code and ends the challenge after MaxLoginChallengeAttempts
+func (s *twoFactorCommandService) recordFailedAttempt(ctx context.Context, challenge *domainauth.LoginChallenge) error {
+	attempts, err := s.challengeRepo.IncrementAttempts(ctx, challenge.ID)
+	if err == nil && attempts >= domainauth.MaxLoginChallengeAttempts {
+		err = s.challengeRepo.Delete(ctx, challenge.ID)
+	}
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "record_login_challenge_attempt").WithMetadata("user_id", challenge.UserID)
+	}
+	return apperror.ErrWrongAuth.WithUserMessage("Invalid two-factor code").WithMetadata("user_id", challenge.UserID)
+}
+
+// verifyTwoFactorCode accepts an unused TOTP code or consumes a recovery code
+func verifyTwoFactorCode(twoFactor *domainauth.TwoFactor, code string, now time.Time) bool {
+	if step, ok := totp.Validate(twoFactor.Secret, code, now); ok {
+		return twoFactor.UseStep(step)
+	}
+	return twoFactor.UseRecoveryCode(code)
+}
@@ -0,0 +1,238 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+	"jcourse_go/pkg/totp"
+)
+
+type twoFactorTestFixture struct {
+	service    TwoFactorCommandService
+	users      *MockUserRepository
+	twoFactors *MockTwoFactorRepository
+	challenges *MockLoginChallengeRepository
+	sessions   *MockSessionRepository
+	failures   *MockFailureCounterRepository
+}
+
+func newTwoFactorTestFixture(users ...domainauth.User) *twoFactorTestFixture {
+	f := &twoFactorTestFixture{
+		users:      NewMockUserRepository(users...),
+		twoFactors: NewMockTwoFactorRepository(),
+		challenges: NewMockLoginChallengeRepository(),
+		sessions:   NewMockSessionRepository(),
+		failures:   NewMockFailureCounterRepository(),
+	}
+	f.service = NewTwoFactorCommandService(f.users, f.twoFactors, f.challenges, f.sessions, f.failures, &MockTransactor{})
+	return f
+}
+
+// enable enrolls the user and returns the secret and the recovery codes
+func (f *twoFactorTestFixture) enable(t *testing.T, userID int) (string, []string) {
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: userID}}
+	enrollment, err := f.service.Enroll(commonCtx)
+	assert.NoError(t, err)
+
+	codes, err := f.service.Confirm(commonCtx, totpCode(t, enrollment.Secret, totp.Step(time.Now())))
+	assert.NoError(t, err)
+	return enrollment.Secret, codes
+}
+
+// challenge starts a login of the user and returns the challenge token
+func (f *twoFactorTestFixture) challenge(t *testing.T, userID int) string {
+	flow := &loginFlow{session: f.sessions, twoFactor: f.twoFactors, challenges: f.challenges}
+	result, err := flow.begin(context.Background(), userID, domainauth.SessionMetadata{IP: "203.0.113.7"})
+	assert.NoError(t, err)
+	assert.True(t, result.TwoFactorRequired())
+	return result.ChallengeToken
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	code, err := totp.Code(secret, step)
+	assert.NoError(t, err)
+	return code
+}
+
+func assertAppErrorCode(t *testing.T, expected *apperror.AppError, err error) {
+	var appErr *apperror.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, expected.Code, appErr.Code)
+	}
+}
+
+func TestTwoFactorCommandService_ConfirmEnablesAndRevokesOtherSessions(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	current, _ := f.sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	f.sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1, SessionID: f.sessions.Sessions[current].ID}}
+
+	enrollment, err := f.service.Enroll(commonCtx)
+	assert.NoError(t, err)
+	assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/")
+	assert.Nil(t, f.twoFactors.TwoFactors[1].EnabledAt)
+
+	codes, err := f.service.Confirm(commonCtx, totpCode(t, enrollment.Secret, totp.Step(time.Now())))
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, domainauth.RecoveryCodeCount)
+	assert.NotNil(t, f.twoFactors.TwoFactors[1].EnabledAt)
+	assert.Len(t, f.sessions.Sessions, 1)
+	assert.Contains(t, f.sessions.Sessions, current)
+}
+
+func TestTwoFactorCommandService_ConfirmWrongCode(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1}}
+	_, err := f.service.Enroll(commonCtx)
+	assert.NoError(t, err)
+
+	_, err = f.service.Confirm(commonCtx, "000000")
+
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	assert.Nil(t, f.twoFactors.TwoFactors[1].EnabledAt)
+}
+
+func TestTwoFactorCommandService_LoginRequiresChallenge(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "user@example.com", Password: testHasher.Hash("secret"), Role: common.RoleUser})
+	sessions := NewMockSessionRepository()
+	twoFactors := NewMockTwoFactorRepository()
+	now := time.Now()
+	twoFactors.TwoFactors[1] = domainauth.TwoFactor{UserID: 1, Secret: totp.GenerateSecret(), EnabledAt: &now}
+	service := NewAuthCommandService(users, testHasher, sessions, NewMockAPITokenRepository(), twoFactors, NewMockLoginChallengeRepository(), NewMockFailureCounterRepository(), NewMockInviteCodeRepository(), domainauth.RegistrationPolicy{}, &MockCodeService{}, &MockTransactor{}, &MockPublisher{})
+
+	result, err := service.Login(context.Background(), domainauth.LoginCommand{Email: "user@example.com", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.TwoFactorRequired())
+	assert.Empty(t, result.SessionID)
+	assert.Empty(t, sessions.Sessions)
+}
+
+func TestTwoFactorCommandService_VerifyLoginWithTOTP(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	secret, _ := f.enable(t, 1)
+	token := f.challenge(t, 1)
+	// The step used to confirm the enrollment cannot be replayed
+	code := totpCode(t, secret, totp.Step(time.Now())+1)
+
+	sessionID, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: token, Code: code})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.sessions.Sessions[sessionID].UserID)
+	assert.Equal(t, "203.0.113.7", f.sessions.Sessions[sessionID].Metadata.IP)
+	assert.Empty(t, f.challenges.Challenges)
+
+	// Neither the challenge nor the code can be used twice
+	_, err = f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: token, Code: code})
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	_, err = f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: code})
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+}
+
+func TestTwoFactorCommandService_RecoveryCodeIsSingleUse(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	_, codes := f.enable(t, 1)
+
+	_, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: codes[0]})
+	assert.NoError(t, err)
+	assert.Len(t, f.twoFactors.TwoFactors[1].RecoveryCodes, domainauth.RecoveryCodeCount-1)
+
+	_, err = f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: codes[0]})
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+}
+
+func TestTwoFactorCommandService_VerifyLoginLimitsAttempts(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	_, codes := f.enable(t, 1)
+	token := f.challenge(t, 1)
+
+	for i := 0; i < domainauth.MaxLoginChallengeAttempts; i++ {
+		_, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: token, Code: "wrong"})
+		assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	}
+
+	_, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: token, Code: codes[0]})
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	assert.Empty(t, f.sessions.Sessions)
+}
+
+func TestTwoFactorCommandService_WrongCodesLockOutAcrossChallenges(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	secret, _ := f.enable(t, 1)
+
+	// A fresh challenge per guess does not escape the lockout of the email
+	for i := 0; i < domainauth.EmailThrottlePolicy.Threshold; i++ {
+		_, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: "wrong"})
+		assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	}
+
+	code := totpCode(t, secret, totp.Step(time.Now())+1)
+	_, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: code})
+	assertAppErrorCode(t, apperror.ErrRateLimit, err)
+	assert.Empty(t, f.sessions.Sessions)
+}
+
+func TestTwoFactorCommandService_VerifyLoginResetsEmailFailures(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	secret, _ := f.enable(t, 1)
+	_, err := f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: "wrong"})
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	assert.Contains(t, f.failures.Counters, domainauth.EmailThrottleKey("user@example.com"))
+
+	code := totpCode(t, secret, totp.Step(time.Now())+1)
+	_, err = f.service.VerifyLogin(context.Background(), domainauth.VerifyTwoFactorCommand{ChallengeToken: f.challenge(t, 1), Code: code})
+
+	assert.NoError(t, err)
+	assert.NotContains(t, f.failures.Counters, domainauth.EmailThrottleKey("user@example.com"))
+}
+
+func TestTwoFactorCommandService_AdminCannotDisable(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "admin@example.com", Role: common.RoleAdmin})
+	_, codes := f.enable(t, 1)
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1, Role: common.RoleAdmin}}
+
+	err := f.service.Disable(commonCtx, codes[0])
+
+	assertAppErrorCode(t, apperror.ErrPermission, err)
+	assert.NotNil(t, f.twoFactors.TwoFactors[1].EnabledAt)
+}
+
+func TestTwoFactorCommandService_Disable(t *testing.T) {
+	f := newTwoFactorTestFixture(domainauth.User{ID: 1, Email: "user@example.com", Role: common.RoleUser})
+	_, codes := f.enable(t, 1)
+	commonCtx := &common.CommonContext{Ctx: context.Background(), User: &common.User{UserID: 1}}
+
+	err := f.service.Disable(commonCtx, codes[0])
+
+	assert.NoError(t, err)
+	assert.Empty(t, f.twoFactors.TwoFactors)
+}
+
+func TestSessionCommandService_AuthenticateAdminWithoutTwoFactor(t *testing.T) {
+	users := NewMockUserRepository(domainauth.User{ID: 1, Email: "admin@example.com", Role: common.RoleAdmin})
+	sessions := NewMockSessionRepository()
+	twoFactors := NewMockTwoFactorRepository()
+	token, _ := sessions.Store(context.Background(), 1, domainauth.SessionMetadata{})
+	service := NewSessionCommandService(users, sessions, twoFactors)
+
+	user, err := service.Authenticate(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, common.RoleUser, user.Role)
+	assert.True(t, user.TwoFactorPending)
+
+	now := time.Now()
+	twoFactors.TwoFactors[1] = domainauth.TwoFactor{UserID: 1, EnabledAt: &now}
+	user, err = service.Authenticate(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, common.RoleAdmin, user.Role)
+	assert.False(t, user.TwoFactorPending)
+}
@@ -2,25 +2,61 @@ package command
 
 import (
 	"context"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
-	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/application/auth"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/email"
 	"jcourse_go/pkg/apperror"
 )
 
+// EmailRevertPath is the page of the frontend that reverts an email change with the token in its query
+const EmailRevertPath = "/account/email/revert"
+
 type UserCommandService interface {
 	UpdateUserInfo(ctx context.Context, userID int, nickname string) error
+	// RequestEmailChange sends a code to the new address, which must not belong to another account
+	RequestEmailChange(commonCtx *common.CommonContext, cmd domainauth.RequestEmailChangeCommand) error
+	// ConfirmEmailChange switches to the new address, revokes the other sessions and notifies the previous address
+	ConfirmEmailChange(commonCtx *common.CommonContext, cmd domainauth.ConfirmEmailChangeCommand) error
+	// RevertEmailChange restores the previous address and revokes every session of the account
+	RevertEmailChange(ctx context.Context, cmd domainauth.RevertEmailChangeCommand) error
 }
 
 type userCommandService struct {
-	userRepo auth.UserRepository
+	userRepo        domainauth.UserRepository
+	sessionRepo     domainauth.SessionRepository
+	emailChangeRepo domainauth.EmailChangeRepository
+	codeService     auth.VerificationCodeService
+	emailService    email.EmailService
+	policy          domainauth.RegistrationPolicy
+	transactor      common.Transactor
+	siteURL         string
 }
 
 func NewUserCommandService(
-	userRepo auth.UserRepository,
+	userRepo domainauth.UserRepository,
+	sessionRepo domainauth.SessionRepository,
+	emailChangeRepo domainauth.EmailChangeRepository,
+	codeService auth.VerificationCodeService,
+	emailService email.EmailService,
+	policy domainauth.RegistrationPolicy,
+	transactor common.Transactor,
+	siteURL string,
 ) UserCommandService {
 	return &userCommandService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		emailChangeRepo: emailChangeRepo,
+		codeService:     codeService,
+		emailService:    emailService,
+		policy:          policy,
+		transactor:      transactor,
+		siteURL:         strings.TrimRight(siteURL, "/"),
 	}
 }
 
@@ -38,3 +74,119 @@ func (s *userCommandService) UpdateUserInfo(ctx context.Context, userID int, nic
 
 	return s.userRepo.Update(ctx, user)
 }
+
+// checkNewEmail validates the address the user wants to switch to and returns the user
+func (s *userCommandService) checkNewEmail(ctx context.Context, userID int, newEmail string) (*domainauth.User, error) {
+	if !strings.Contains(newEmail, "@") {
+		return nil, apperror.ErrValidation.WithMessage("new_email must be an email address")
+	}
+	// Changing the address must not get around the domain rules of registration
+	if s.policy.IsBlocked(newEmail) || !s.policy.IsAllowedDomain(newEmail) {
+		return nil, apperror.ErrValidation.WithUserMessage("Email addresses of this domain are not accepted").
+			WithMetadata("email", newEmail)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "change_email").WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return nil, apperror.ErrWrongInput.WithUserMessage("This is already your email address")
+	}
+
+	existing, err := s.userRepo.Get(ctx, newEmail)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "change_email").WithMetadata("user_id", userID)
+	}
+	if existing != nil {
+		return nil, apperror.ErrWrongInput.WithUserMessage("Email already registered")
+	}
+	return user, nil
+}
+
+func (s *userCommandService) RequestEmailChange(commonCtx *common.CommonContext, cmd domainauth.RequestEmailChangeCommand) error {
+	newEmail := strings.TrimSpace(cmd.NewEmail)
+	if _, err := s.checkNewEmail(commonCtx.Ctx, commonCtx.User.UserID, newEmail); err != nil {
+		return err
+	}
+	return s.codeService.SendCode(commonCtx.Ctx, newEmail, domainauth.CodePurposeChangeEmail)
+}
+
+func (s *userCommandService) ConfirmEmailChange(commonCtx *common.CommonContext, cmd domainauth.ConfirmEmailChangeCommand) error {
+	ctx, userID := commonCtx.Ctx, commonCtx.User.UserID
+	newEmail := strings.TrimSpace(cmd.NewEmail)
+
+	user, err := s.checkNewEmail(ctx, userID, newEmail)
+	if err != nil {
+		return err
+	}
+	if err := s.codeService.Verify(ctx, cmd.Code, newEmail, domainauth.CodePurposeChangeEmail); err != nil {
+		return apperror.ErrWrongAuth.Wrap(err).WithMetadata("operation", "change_email").WithMetadata("user_id", userID)
+	}
+
+	now := time.Now()
+	change, revertToken := domainauth.NewEmailChange(userID, user.Email, newEmail, now)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		changed, err := s.userRepo.ChangeEmail(ctx, userID, newEmail)
+		if err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "change_email").WithMetadata("user_id", userID)
+		}
+		if !changed {
+			return apperror.ErrWrongInput.WithUserMessage("Email already registered")
+		}
+		if err := s.emailChangeRepo.Create(ctx, &change); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "record_email_change").WithMetadata("user_id", userID)
+		}
+		if _, err := s.sessionRepo.DeleteOthers(ctx, userID, commonCtx.User.SessionID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revoke_other_sessions").WithMetadata("user_id", userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The change is committed at this point; a lost notice must not undo it
+	notice := &email.EmailChangedNotice{
+		NewEmail:  newEmail,
+		RevertURL: s.siteURL + EmailRevertPath + "?token=" + url.QueryEscape(revertToken),
+		ExpiresAt: change.ExpiresAt,
+	}
+	if err := s.emailService.SendEmailChangedNotice(ctx, change.OldEmail, notice); err != nil {
+		log.Printf("Failed to notify %s of the email change of user %d: %v", change.OldEmail, userID, err)
+	}
+	return nil
+}
+
+func (s *userCommandService) RevertEmailChange(ctx context.Context, cmd domainauth.RevertEmailChangeCommand) error {
+	change, err := s.emailChangeRepo.GetByRevertToken(ctx, cmd.Token)
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "revert_email_change")
+	}
+	now := time.Now()
+	if change == nil || !change.CanRevert(now) {
+		return apperror.ErrWrongInput.WithUserMessage("The link is invalid or has expired")
+	}
+
+	change.Revert(now)
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		changed, err := s.userRepo.ChangeEmail(ctx, change.UserID, change.OldEmail)
+		if err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revert_email_change").WithMetadata("user_id", change.UserID)
+		}
+		if !changed {
+			return apperror.ErrWrongInput.WithUserMessage("The previous email address is in use by another account")
+		}
+		if err := s.emailChangeRepo.Update(ctx, change); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revert_email_change").WithMetadata("user_id", change.UserID)
+		}
+		// Whoever changed the address may still be signed in
+		if _, err := s.sessionRepo.DeleteByUser(ctx, change.UserID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "revert_email_change").WithMetadata("user_id", change.UserID)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type emailChangeTestFixture struct {
+	users    *MockUserRepository
+	sessions *MockSessionRepository
+	changes  *MockEmailChangeRepository
+	codes    *MockCodeService
+	emails   *MockEmailService
+	service  UserCommandService
+}
+
+func newEmailChangeTestFixture(users ...domainauth.User) *emailChangeTestFixture {
+	f := &emailChangeTestFixture{
+		users:    NewMockUserRepository(users...),
+		sessions: NewMockSessionRepository(),
+		changes:  NewMockEmailChangeRepository(),
+		codes:    &MockCodeService{},
+		emails:   &MockEmailService{},
+	}
+	policy := domainauth.RegistrationPolicy{AllowedDomains: []string{"sjtu.edu.cn"}}
+	f.service = NewUserCommandService(f.users, f.sessions, f.changes, f.codes, f.emails, policy, &MockTransactor{}, "https://course.example/")
+	return f
+}
+
+// login opens a session of the user and returns the context of a request made with it
+func (f *emailChangeTestFixture) login(userID int) *common.CommonContext {
+	token, _ := f.sessions.Store(context.Background(), userID, domainauth.SessionMetadata{})
+	return &common.CommonContext{
+		Ctx:  context.Background(),
+		User: &common.User{UserID: userID, Role: common.RoleUser, SessionID: f.sessions.Sessions[token].ID},
+	}
+}
+
+func TestUserCommandService_RequestEmailChange(t *testing.T) {
+	f := newEmailChangeTestFixture(
+		domainauth.User{ID: 1, Email: "alice@sjtu.edu.cn"},
+		domainauth.User{ID: 2, Email: "bob@sjtu.edu.cn"},
+	)
+	commonCtx := f.login(1)
+
+	err := f.service.RequestEmailChange(commonCtx, domainauth.RequestEmailChangeCommand{NewEmail: "bob@sjtu.edu.cn"})
+	assertAppErrorCode(t, apperror.ErrWrongInput, err)
+
+	err = f.service.RequestEmailChange(commonCtx, domainauth.RequestEmailChangeCommand{NewEmail: "alice@example.com"})
+	assertAppErrorCode(t, apperror.ErrValidation, err)
+	assert.Empty(t, f.codes.Sent)
+
+	err = f.service.RequestEmailChange(commonCtx, domainauth.RequestEmailChangeCommand{NewEmail: "alice@alumni.sjtu.edu.cn"})
+	assert.NoError(t, err)
+	assert.Equal(t, []domainauth.CodePurpose{domainauth.CodePurposeChangeEmail}, f.codes.Sent)
+}
+
+func TestUserCommandService_ConfirmEmailChange(t *testing.T) {
+	f := newEmailChangeTestFixture(domainauth.User{ID: 1, Email: "alice@sjtu.edu.cn"})
+	commonCtx := f.login(1)
+	other := f.login(1)
+
+	err := f.service.ConfirmEmailChange(commonCtx, domainauth.ConfirmEmailChangeCommand{NewEmail: "alice@alumni.sjtu.edu.cn", Code: "123456"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@alumni.sjtu.edu.cn", f.users.Users[1].Email)
+	assert.Equal(t, []domainauth.CodePurpose{domainauth.CodePurposeChangeEmail}, f.codes.Verified)
+	// Only the session the change was made with survives
+	assert.Len(t, f.sessions.Sessions, 1)
+	for _, session := range f.sessions.Sessions {
+		assert.Equal(t, commonCtx.User.SessionID, session.ID)
+		assert.NotEqual(t, other.User.SessionID, session.ID)
+	}
+
+	notice, ok := f.emails.Notices["alice@sjtu.edu.cn"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "alice@alumni.sjtu.edu.cn", notice.NewEmail)
+		assert.True(t, strings.HasPrefix(notice.RevertURL, "https://course.example"+EmailRevertPath+"?token="))
+	}
+}
+
+func TestUserCommandService_ConfirmEmailChangeWrongCode(t *testing.T) {
+	f := newEmailChangeTestFixture(domainauth.User{ID: 1, Email: "alice@sjtu.edu.cn"})
+	f.codes.VerifyError = apperror.ErrWrongInput
+
+	err := f.service.ConfirmEmailChange(f.login(1), domainauth.ConfirmEmailChangeCommand{NewEmail: "alice@alumni.sjtu.edu.cn", Code: "000000"})
+
+	assertAppErrorCode(t, apperror.ErrWrongAuth, err)
+	assert.Equal(t, "alice@sjtu.edu.cn", f.users.Users[1].Email)
+	assert.Empty(t, f.changes.Changes)
+	assert.Empty(t, f.emails.Notices)
+}
+
+func TestUserCommandService_RevertEmailChange(t *testing.T) {
+	f := newEmailChangeTestFixture(domainauth.User{ID: 1, Email: "alice@sjtu.edu.cn"})
+	err := f.service.ConfirmEmailChange(f.login(1), domainauth.ConfirmEmailChangeCommand{NewEmail: "mallory@sjtu.edu.cn", Code: "123456"})
+	assert.NoError(t, err)
+
+	revertURL, err := url.Parse(f.emails.Notices["alice@sjtu.edu.cn"].RevertURL)
+	assert.NoError(t, err)
+	token := revertURL.Query().Get("token")
+
+	err = f.service.RevertEmailChange(context.Background(), domainauth.RevertEmailChangeCommand{Token: token})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@sjtu.edu.cn", f.users.Users[1].Email)
+	assert.Empty(t, f.sessions.Sessions)
+
+	// The link only works once
+	err = f.service.RevertEmailChange(context.Background(), domainauth.RevertEmailChangeCommand{Token: token})
+	assertAppErrorCode(t, apperror.ErrWrongInput, err)
+
+	err = f.service.RevertEmailChange(context.Background(), domainauth.RevertEmailChangeCommand{Token: "unknown"})
+	assertAppErrorCode(t, apperror.ErrWrongInput, err)
+}
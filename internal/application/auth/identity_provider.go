@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/auth"
+)
+
+// IdentityProvider signs users in through an external OAuth2/OIDC authorization server
+// with the authorization code flow and PKCE
+type IdentityProvider interface {
+	// Name identifies the provider in routes and linked identities, e.g. "jaccount"
+	Name() string
+	// AuthCodeURL returns where to send the browser to sign in
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange redeems the authorization code and returns the claims of the verified ID token,
+	// which must carry the nonce of the authorization request
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*auth.IdentityClaims, error)
+}
+
+// IdentityProviders looks providers up by name
+type IdentityProviders map[string]IdentityProvider
+
+func NewIdentityProviders(providers ...IdentityProvider) IdentityProviders {
+	registry := make(IdentityProviders, len(providers))
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+	return registry
+}
@@ -2,8 +2,10 @@ package auth
 
 import (
 	"context"
+	"sync"
 
 	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/email"
 )
 
 // MockEmailService is a mock implementation of email.EmailService for testing
@@ -15,15 +17,21 @@ func (m *MockEmailService) SendVerificationCode(ctx context.Context, emailAddr s
 	return m.SendError
 }
 
+func (m *MockEmailService) SendEmailChangedNotice(ctx context.Context, emailAddr string, input *email.EmailChangedNotice) error {
+	return m.SendError
+}
+
 // MockCodeRepository is a mock implementation of auth.CodeRepository for testing
 type MockCodeRepository struct {
+	mu          sync.Mutex
 	GetCode     *auth.VerificationCode
 	GetError    error
 	SaveError   error
 	DeleteError error
+	Deleted     bool
 }
 
-func (m *MockCodeRepository) Get(ctx context.Context, email string) (*auth.VerificationCode, error) {
+func (m *MockCodeRepository) Get(ctx context.Context, email string, purpose auth.CodePurpose) (*auth.VerificationCode, error) {
 	return m.GetCode, m.GetError
 }
 
@@ -31,6 +39,19 @@ func (m *MockCodeRepository) Save(ctx context.Context, code *auth.VerificationCo
 	return m.SaveError
 }
 
+func (m *MockCodeRepository) IncrementAttempts(ctx context.Context, code *auth.VerificationCode) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetCode == nil || m.Deleted {
+		return 0, nil
+	}
+	m.GetCode.Attempts++
+	return m.GetCode.Attempts, nil
+}
+
 func (m *MockCodeRepository) Delete(ctx context.Context, code *auth.VerificationCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Deleted = m.DeleteError == nil
 	return m.DeleteError
 }
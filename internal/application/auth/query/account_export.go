@@ -0,0 +1,94 @@
+package query
+
+import (
+	"time"
+
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/point"
+	"jcourse_go/internal/domain/review"
+	"jcourse_go/pkg/apperror"
+)
+
+type AccountExportQueryService interface {
+	// Export collects the personal data of the current user into one archive
+	Export(commonCtx *common.CommonContext) (*viewobject.AccountExportVO, error)
+}
+
+type accountExportQueryService struct {
+	userRepo   domainauth.UserRepository
+	reviewRepo review.ReviewRepository
+	courseRepo review.CourseRepository
+	pointRepo  point.UserPointRepository
+}
+
+func NewAccountExportQueryService(
+	userRepo domainauth.UserRepository,
+	reviewRepo review.ReviewRepository,
+	courseRepo review.CourseRepository,
+	pointRepo point.UserPointRepository,
+) AccountExportQueryService {
+	return &accountExportQueryService{
+		userRepo:   userRepo,
+		reviewRepo: reviewRepo,
+		courseRepo: courseRepo,
+		pointRepo:  pointRepo,
+	}
+}
+
+func (s *accountExportQueryService) Export(commonCtx *common.CommonContext) (*viewobject.AccountExportVO, error) {
+	ctx, userID := commonCtx.Ctx, commonCtx.User.UserID
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "export_user").WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+
+	reviews, err := s.reviewRepo.FindBy(ctx, review.ReviewFilter{UserID: &userID})
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "export_reviews").WithMetadata("user_id", userID)
+	}
+	exportedReviews := make([]viewobject.ExportedReviewVO, len(reviews))
+	for i, r := range reviews {
+		revisions, err := s.reviewRepo.GetReviewRevisions(ctx, r.ID)
+		if err != nil {
+			return nil, apperror.WrapDB(err).WithMetadata("operation", "export_review_revisions").WithMetadata("review_id", r.ID)
+		}
+		exportedReviews[i] = viewobject.NewExportedReviewVO(&r, revisions)
+	}
+
+	actions, err := s.reviewRepo.GetUserReviewActions(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "export_review_actions").WithMetadata("user_id", userID)
+	}
+	exportedActions := make([]viewobject.ExportedReviewActionVO, len(actions))
+	for i, a := range actions {
+		exportedActions[i] = viewobject.NewExportedReviewActionVO(&a)
+	}
+
+	points, err := s.pointRepo.GetUserAllPoints(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "export_points").WithMetadata("user_id", userID)
+	}
+
+	enrolled, err := s.courseRepo.GetUserEnrolledCourses(ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "export_enrollments").WithMetadata("user_id", userID)
+	}
+	if enrolled == nil {
+		enrolled = []int{}
+	}
+
+	return &viewobject.AccountExportVO{
+		ExportedAt:        time.Now(),
+		Profile:           viewobject.NewUserInfoVO(user),
+		Reviews:           exportedReviews,
+		ReviewActions:     exportedActions,
+		Points:            viewobject.NewUserPointVO(points),
+		EnrolledCourseIDs: enrolled,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/point"
+	"jcourse_go/internal/domain/review"
+	"jcourse_go/pkg/apperror"
+)
+
+type AdminUserQueryService interface {
+	SearchUsers(commonCtx *common.CommonContext, filter domainauth.UserSearchFilter) (*viewobject.AdminUserListVO, error)
+	GetUser(commonCtx *common.CommonContext, userID int) (*viewobject.AdminUserVO, error)
+	GetUserReviews(commonCtx *common.CommonContext, userID int, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error)
+	GetUserPoints(commonCtx *common.CommonContext, userID int) (*viewobject.UserPointVO, error)
+}
+
+type adminUserQueryService struct {
+	userRepo   domainauth.UserRepository
+	reviewRepo review.ReviewRepository
+	pointRepo  point.UserPointRepository
+}
+
+func NewAdminUserQueryService(
+	userRepo domainauth.UserRepository,
+	reviewRepo review.ReviewRepository,
+	pointRepo point.UserPointRepository,
+) AdminUserQueryService {
+	return &adminUserQueryService{
+		userRepo:   userRepo,
+		reviewRepo: reviewRepo,
+		pointRepo:  pointRepo,
+	}
+}
+
+func requireAdmin(commonCtx *common.CommonContext) error {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return apperror.ErrPermission.WithMessage("only admins can view users")
+	}
+	return nil
+}
+
+func (s *adminUserQueryService) SearchUsers(commonCtx *common.CommonContext, filter domainauth.UserSearchFilter) (*viewobject.AdminUserListVO, error) {
+	if err := requireAdmin(commonCtx); err != nil {
+		return nil, err
+	}
+
+	users, total, err := s.userRepo.Search(commonCtx.Ctx, filter)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "search_users")
+	}
+
+	vo := viewobject.NewAdminUserListVO(users, total)
+	return &vo, nil
+}
+
+func (s *adminUserQueryService) GetUser(commonCtx *common.CommonContext, userID int) (*viewobject.AdminUserVO, error) {
+	if err := requireAdmin(commonCtx); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(commonCtx.Ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_user").WithMetadata("user_id", userID)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
+	}
+
+	vo := viewobject.NewAdminUserVO(user)
+	return &vo, nil
+}
+
+func (s *adminUserQueryService) GetUserReviews(commonCtx *common.CommonContext, userID int, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error) {
+	if err := requireAdmin(commonCtx); err != nil {
+		return nil, err
+	}
+
+	filter := review.ReviewFilter{UserID: &userID}
+	if err := opts.Apply(&filter); err != nil {
+		return nil, apperror.ErrValidation.WithMessage(err.Error())
+	}
+
+	reviews, err := s.reviewRepo.FindBy(commonCtx.Ctx, filter)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_user_reviews").WithMetadata("user_id", userID)
+	}
+	total, err := s.reviewRepo.CountBy(commonCtx.Ctx, filter)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "count_user_reviews").WithMetadata("user_id", userID)
+	}
+
+	list := viewobject.NewAdminReviewListVO(reviews, total, review.NextReviewCursor(filter, reviews), true)
+	return &list, nil
+}
+
+func (s *adminUserQueryService) GetUserPoints(commonCtx *common.CommonContext, userID int) (*viewobject.UserPointVO, error) {
+	if err := requireAdmin(commonCtx); err != nil {
+		return nil, err
+	}
+
+	points, err := s.pointRepo.GetUserAllPoints(commonCtx.Ctx, userID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_user_points").WithMetadata("user_id", userID)
+	}
+
+	vo := viewobject.NewUserPointVO(points)
+	return &vo, nil
+}
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type APITokenQueryService interface {
+	// ListTokens returns the personal API tokens of the current user, including expired ones
+	ListTokens(commonCtx *common.CommonContext) ([]viewobject.APITokenVO, error)
+}
+
+type apiTokenQueryService struct {
+	apiTokenRepo domainauth.APITokenRepository
+}
+
+func NewAPITokenQueryService(apiTokenRepo domainauth.APITokenRepository) APITokenQueryService {
+	return &apiTokenQueryService{
+		apiTokenRepo: apiTokenRepo,
+	}
+}
+
+func (s *apiTokenQueryService) ListTokens(commonCtx *common.CommonContext) ([]viewobject.APITokenVO, error) {
+	tokens, err := s.apiTokenRepo.ListByUser(commonCtx.Ctx, commonCtx.User.UserID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_api_tokens").
+			WithMetadata("user_id", commonCtx.User.UserID)
+	}
+
+	vos := make([]viewobject.APITokenVO, 0, len(tokens))
+	for _, token := range tokens {
+		vos = append(vos, viewobject.NewAPITokenVO(token))
+	}
+	return vos, nil
+}
@@ -5,12 +5,10 @@ import (
 
 	"jcourse_go/internal/application/viewobject"
 	domainauth "jcourse_go/internal/domain/auth"
-	"jcourse_go/internal/domain/common"
 	"jcourse_go/pkg/apperror"
 )
 
 type AuthQueryService interface {
-	GetUserFromSession(ctx context.Context, sessionID string) (*common.User, error)
 	GetUserInfo(ctx context.Context, userID int) (*viewobject.UserInfoVO, error)
 }
 
@@ -29,26 +27,6 @@ func NewAuthQueryService(
 	}
 }
 
-func (s *authQueryService) GetUserFromSession(ctx context.Context, sessionID string) (*common.User, error) {
-	userID, err := s.session.Get(ctx, sessionID)
-	if err != nil {
-		return nil, apperror.ErrSession.Wrap(err).WithMetadata("operation", "get_user_from_session").WithMetadata("session_id", sessionID)
-	}
-
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_user_from_session").WithMetadata("user_id", userID)
-	}
-	if user == nil {
-		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
-	}
-
-	return &common.User{
-		UserID: user.ID,
-		Role:   user.Role,
-	}, nil
-}
-
 func (s *authQueryService) GetUserInfo(ctx context.Context, userID int) (*viewobject.UserInfoVO, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -64,6 +42,7 @@ func (s *authQueryService) GetUserInfo(ctx context.Context, userID int) (*viewob
 		Email:       user.Email,
 		Role:        user.Role,
 		IsSuspended: user.IsSuspended(),
+		LastSeenAt:  user.LastSeenAt,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
 	}, nil
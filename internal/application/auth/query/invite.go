@@ -0,0 +1,36 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type InviteQueryService interface {
+	ListInvites(commonCtx *common.CommonContext, pagination common.Pagination) (*viewobject.InviteCodeListVO, error)
+}
+
+type inviteQueryService struct {
+	inviteRepo domainauth.InviteCodeRepository
+}
+
+func NewInviteQueryService(inviteRepo domainauth.InviteCodeRepository) InviteQueryService {
+	return &inviteQueryService{
+		inviteRepo: inviteRepo,
+	}
+}
+
+func (s *inviteQueryService) ListInvites(commonCtx *common.CommonContext, pagination common.Pagination) (*viewobject.InviteCodeListVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can view invite codes")
+	}
+
+	invites, total, err := s.inviteRepo.List(commonCtx.Ctx, pagination)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_invite_codes")
+	}
+
+	vo := viewobject.NewInviteCodeListVO(invites, total)
+	return &vo, nil
+}
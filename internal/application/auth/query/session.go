@@ -0,0 +1,37 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+type SessionQueryService interface {
+	// ListSessions returns the active sessions of the current user
+	ListSessions(commonCtx *common.CommonContext) ([]viewobject.SessionVO, error)
+}
+
+type sessionQueryService struct {
+	sessionRepo domainauth.SessionRepository
+}
+
+func NewSessionQueryService(sessionRepo domainauth.SessionRepository) SessionQueryService {
+	return &sessionQueryService{
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (s *sessionQueryService) ListSessions(commonCtx *common.CommonContext) ([]viewobject.SessionVO, error) {
+	sessions, err := s.sessionRepo.ListByUser(commonCtx.Ctx, commonCtx.User.UserID)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_sessions").
+			WithMetadata("user_id", commonCtx.User.UserID)
+	}
+
+	vos := make([]viewobject.SessionVO, 0, len(sessions))
+	for _, session := range sessions {
+		vos = append(vos, viewobject.NewSessionVO(session, session.ID == commonCtx.User.SessionID))
+	}
+	return vos, nil
+}
@@ -33,13 +33,6 @@ func (s *userQueryService) GetUserInfo(ctx context.Context, userID int) (*viewob
 		return nil, apperror.ErrUserNotFound.WithMetadata("user_id", userID)
 	}
 
-	return &viewobject.UserInfoVO{
-		UserID:      user.ID,
-		Username:    user.Username,
-		Email:       user.Email,
-		Role:        user.Role,
-		IsSuspended: user.IsSuspended(),
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
-	}, nil
+	vo := viewobject.NewUserInfoVO(user)
+	return &vo, nil
 }
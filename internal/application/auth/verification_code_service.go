@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"math/big"
 	"time"
 
@@ -12,8 +13,9 @@ import (
 )
 
 type VerificationCodeService interface {
-	SendCode(ctx context.Context, email string) error
-	Verify(ctx context.Context, inputCode string, email string) error
+	SendCode(ctx context.Context, email string, purpose auth.CodePurpose) error
+	// Verify redeems the code, which must have been sent to the email for the same purpose
+	Verify(ctx context.Context, inputCode string, email string, purpose auth.CodePurpose) error
 }
 
 func NewVerificationCodeService(email email.EmailService, codeRepo auth.CodeRepository) VerificationCodeService {
@@ -36,34 +38,56 @@ type verificationCodeService struct {
 	codeCharset string
 }
 
-func (v *verificationCodeService) Verify(ctx context.Context, inputCode string, email string) error {
-	code, err := v.codeRepo.Get(ctx, email)
+func (v *verificationCodeService) Verify(ctx context.Context, inputCode string, email string, purpose auth.CodePurpose) error {
+	code, err := v.codeRepo.Get(ctx, email, purpose)
 	if err != nil {
 		return err
 	}
+	if code == nil || code.Purpose != purpose {
+		return apperror.ErrWrongInput
+	}
 	if code.IsExpired(time.Now()) {
 		return apperror.ErrExpired
 	}
-	if code.Code != inputCode {
-		return apperror.ErrWrongInput
+	if subtle.ConstantTimeCompare([]byte(code.Code), []byte(inputCode)) != 1 {
+		return v.recordFailedAttempt(ctx, code)
 	}
 	err = v.codeRepo.Delete(ctx, code)
 	return err
 }
 
-func (v *verificationCodeService) createCode(ctx context.Context, email string) *auth.VerificationCode {
+// recordFailedAttempt counts a wrong guess and invalidates the code after MaxCodeAttempts,
+// so a code cannot be brute-forced within its lifetime
+// The count is raised in the database, so guesses sent concurrently are all counted
+func (v *verificationCodeService) recordFailedAttempt(ctx context.Context, code *auth.VerificationCode) error {
+	attempts, err := v.codeRepo.IncrementAttempts(ctx, code)
+	if err != nil {
+		return err
+	}
+	// A code that is already gone was used up by another guess
+	if attempts == 0 || attempts >= auth.MaxCodeAttempts {
+		if err := v.codeRepo.Delete(ctx, code); err != nil {
+			return err
+		}
+		return apperror.ErrWrongInput.WithUserMessage("Too many wrong attempts, please request a new code")
+	}
+	return apperror.ErrWrongInput
+}
+
+func (v *verificationCodeService) createCode(ctx context.Context, email string, purpose auth.CodePurpose) *auth.VerificationCode {
 	now := time.Now()
 	code := &auth.VerificationCode{
 		Code:      GenerateRandomCode(v.codeCharset, v.codeLength),
 		Email:     email,
+		Purpose:   purpose,
 		ExpiresAt: now.Add(v.ttl),
 		CreatedAt: now,
 	}
 	return code
 }
 
-func (v *verificationCodeService) canSendCode(ctx context.Context, email string) error {
-	code, err := v.codeRepo.Get(ctx, email)
+func (v *verificationCodeService) canSendCode(ctx context.Context, email string, purpose auth.CodePurpose) error {
+	code, err := v.codeRepo.Get(ctx, email, purpose)
 	if err != nil {
 		return err
 	}
@@ -78,11 +102,11 @@ func (v *verificationCodeService) canSendCode(ctx context.Context, email string)
 	return nil
 }
 
-func (v *verificationCodeService) SendCode(ctx context.Context, email string) error {
-	if err := v.canSendCode(ctx, email); err != nil {
+func (v *verificationCodeService) SendCode(ctx context.Context, email string, purpose auth.CodePurpose) error {
+	if err := v.canSendCode(ctx, email, purpose); err != nil {
 		return err
 	}
-	code := v.createCode(ctx, email)
+	code := v.createCode(ctx, email, purpose)
 	if err := v.codeRepo.Save(ctx, code); err != nil {
 		return err
 	}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+type DeadLetterCommandService interface {
+	// Store records a delivery that exhausted its retries; it is the sink of the event bus
+	Store(ctx context.Context, e event.Event, handler string, attempts int, lastErr error) error
+	Replay(commonCtx *common.CommonContext, id int64) error
+	Discard(commonCtx *common.CommonContext, id int64) error
+}
+
+type deadLetterCommandService struct {
+	deadLetterRepo event.DeadLetterRepository
+	eventBus       event.EventBus
+}
+
+func NewDeadLetterCommandService(deadLetterRepo event.DeadLetterRepository, eventBus event.EventBus) DeadLetterCommandService {
+	return &deadLetterCommandService{
+		deadLetterRepo: deadLetterRepo,
+		eventBus:       eventBus,
+	}
+}
+
+func (s *deadLetterCommandService) Store(ctx context.Context, e event.Event, handler string, attempts int, lastErr error) error {
+	deadLetter, err := event.NewDeadLetter(e, handler, attempts, lastErr)
+	if err != nil {
+		return err
+	}
+	if err := s.deadLetterRepo.Save(ctx, &deadLetter); err != nil {
+		return err
+	}
+
+	log.Printf("Stored dead letter %d for event %s and handler %s", deadLetter.ID, e.ID(), handler)
+	return nil
+}
+
+// Replay hands the event to the failed handler again and records the outcome
+func (s *deadLetterCommandService) Replay(commonCtx *common.CommonContext, id int64) error {
+	deadLetter, err := s.getFailed(commonCtx, id)
+	if err != nil {
+		return err
+	}
+	if s.eventBus == nil {
+		return apperror.ErrInternal.WithMessage("event bus is disabled").
+			WithMetadata("dead_letter_id", id)
+	}
+
+	e, err := deadLetter.ToEvent()
+	if err != nil {
+		return apperror.WrapInternal(err).WithMetadata("dead_letter_id", id)
+	}
+
+	replayErr := s.eventBus.Redeliver(commonCtx.Ctx, e, deadLetter.Handler)
+
+	deadLetter.Attempts++
+	deadLetter.UpdatedAt = time.Now()
+	if replayErr != nil {
+		deadLetter.LastError = replayErr.Error()
+	} else {
+		deadLetter.Status = event.DeadLetterStatusReplayed
+	}
+	if err := s.deadLetterRepo.Save(commonCtx.Ctx, deadLetter); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "replay_dead_letter").
+			WithMetadata("dead_letter_id", id)
+	}
+
+	if replayErr != nil {
+		return apperror.ErrExternal.WithMessage(fmt.Sprintf("replay failed: %v", replayErr)).
+			WithMetadata("dead_letter_id", id).
+			WithMetadata("handler", deadLetter.Handler)
+	}
+	return nil
+}
+
+func (s *deadLetterCommandService) Discard(commonCtx *common.CommonContext, id int64) error {
+	deadLetter, err := s.getFailed(commonCtx, id)
+	if err != nil {
+		return err
+	}
+
+	deadLetter.Status = event.DeadLetterStatusDiscarded
+	deadLetter.UpdatedAt = time.Now()
+	if err := s.deadLetterRepo.Save(commonCtx.Ctx, deadLetter); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "discard_dead_letter").
+			WithMetadata("dead_letter_id", id)
+	}
+	return nil
+}
+
+// getFailed loads a dead letter that is still waiting for an admin decision
+func (s *deadLetterCommandService) getFailed(commonCtx *common.CommonContext, id int64) (*event.DeadLetter, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can manage dead letters").
+			WithMetadata("dead_letter_id", id)
+	}
+
+	deadLetter, err := s.deadLetterRepo.Get(commonCtx.Ctx, id)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_dead_letter").
+			WithMetadata("dead_letter_id", id)
+	}
+	if deadLetter == nil {
+		return nil, apperror.ErrNotFound.WithMessage("dead letter not found").
+			WithMetadata("dead_letter_id", id)
+	}
+	if deadLetter.Status != event.DeadLetterStatusFailed {
+		return nil, apperror.ErrWrongInput.WithMessage("dead letter has already been "+string(deadLetter.Status)).
+			WithMetadata("dead_letter_id", id)
+	}
+	return deadLetter, nil
+}
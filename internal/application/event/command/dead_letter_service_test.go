@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+func adminContext() *common.CommonContext {
+	return &common.CommonContext{
+		Ctx:  context.Background(),
+		User: &common.User{UserID: 1, Role: common.RoleAdmin},
+	}
+}
+
+func storeDeadLetter(t *testing.T, service DeadLetterCommandService) {
+	e := event.NewBaseEvent(event.TypeReviewCreated, &event.ReviewPayload{ReviewID: 7, UserID: 3, Action: "created"})
+	err := service.Store(context.Background(), e, "*handler.PointEventHandler", 4, errors.New("database error"))
+	assert.NoError(t, err)
+}
+
+func TestDeadLetterCommandService_Store(t *testing.T) {
+	repo := NewMockDeadLetterRepository()
+	service := NewDeadLetterCommandService(repo, &MockEventBus{})
+
+	storeDeadLetter(t, service)
+
+	deadLetter := repo.DeadLetters[1]
+	assert.Equal(t, "*handler.PointEventHandler", deadLetter.Handler)
+	assert.Equal(t, 4, deadLetter.Attempts)
+	assert.Equal(t, "database error", deadLetter.LastError)
+	assert.Equal(t, event.DeadLetterStatusFailed, deadLetter.Status)
+	assert.JSONEq(t, `{"review_id":7,"user_id":3,"course_id":0,"rating":0,"content":"","action":"created"}`, string(deadLetter.Payload))
+}
+
+func TestDeadLetterCommandService_Replay(t *testing.T) {
+	repo := NewMockDeadLetterRepository()
+	bus := &MockEventBus{}
+	service := NewDeadLetterCommandService(repo, bus)
+	storeDeadLetter(t, service)
+
+	err := service.Replay(adminContext(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*handler.PointEventHandler"}, bus.Redelivered)
+	assert.Equal(t, event.DeadLetterStatusReplayed, repo.DeadLetters[1].Status)
+	assert.Equal(t, 5, repo.DeadLetters[1].Attempts)
+
+	// A replayed dead letter cannot be replayed again
+	err = service.Replay(adminContext(), 1)
+	assert.ErrorIs(t, err, apperror.ErrWrongInput)
+}
+
+func TestDeadLetterCommandService_ReplayFailure(t *testing.T) {
+	repo := NewMockDeadLetterRepository()
+	service := NewDeadLetterCommandService(repo, &MockEventBus{Err: errors.New("still broken")})
+	storeDeadLetter(t, service)
+
+	err := service.Replay(adminContext(), 1)
+
+	assert.ErrorIs(t, err, apperror.ErrExternal)
+	assert.Equal(t, event.DeadLetterStatusFailed, repo.DeadLetters[1].Status)
+	assert.Equal(t, "still broken", repo.DeadLetters[1].LastError)
+	assert.Equal(t, 5, repo.DeadLetters[1].Attempts)
+}
+
+func TestDeadLetterCommandService_Discard(t *testing.T) {
+	repo := NewMockDeadLetterRepository()
+	service := NewDeadLetterCommandService(repo, &MockEventBus{})
+	storeDeadLetter(t, service)
+
+	assert.NoError(t, service.Discard(adminContext(), 1))
+	assert.Equal(t, event.DeadLetterStatusDiscarded, repo.DeadLetters[1].Status)
+
+	err := service.Discard(adminContext(), 2)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestDeadLetterCommandService_RequiresAdmin(t *testing.T) {
+	repo := NewMockDeadLetterRepository()
+	service := NewDeadLetterCommandService(repo, &MockEventBus{})
+	storeDeadLetter(t, service)
+
+	userCtx := &common.CommonContext{
+		Ctx:  context.Background(),
+		User: &common.User{UserID: 2, Role: common.RoleUser},
+	}
+
+	assert.ErrorIs(t, service.Replay(userCtx, 1), apperror.ErrPermission)
+}
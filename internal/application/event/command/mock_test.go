@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/event"
+)
+
+// MockDeadLetterRepository is an in-memory implementation of event.DeadLetterRepository for testing
+type MockDeadLetterRepository struct {
+	DeadLetters map[int64]*event.DeadLetter
+	nextID      int64
+}
+
+func NewMockDeadLetterRepository() *MockDeadLetterRepository {
+	return &MockDeadLetterRepository{DeadLetters: make(map[int64]*event.DeadLetter)}
+}
+
+func (m *MockDeadLetterRepository) Save(ctx context.Context, deadLetter *event.DeadLetter) error {
+	if deadLetter.ID == 0 {
+		m.nextID++
+		deadLetter.ID = m.nextID
+	}
+	stored := *deadLetter
+	m.DeadLetters[deadLetter.ID] = &stored
+	return nil
+}
+
+func (m *MockDeadLetterRepository) Get(ctx context.Context, id int64) (*event.DeadLetter, error) {
+	deadLetter, ok := m.DeadLetters[id]
+	if !ok {
+		return nil, nil
+	}
+	loaded := *deadLetter
+	return &loaded, nil
+}
+
+func (m *MockDeadLetterRepository) List(ctx context.Context, filter event.DeadLetterFilter) ([]event.DeadLetter, int64, error) {
+	var deadLetters []event.DeadLetter
+	for _, deadLetter := range m.DeadLetters {
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+	return deadLetters, int64(len(deadLetters)), nil
+}
+
+// MockEventBus records redelivered events
+type MockEventBus struct {
+	Redelivered []string
+	Err         error
+}
+
+func (m *MockEventBus) Register(eventType event.Type, handler event.Handler) error {
+	return nil
+}
+
+func (m *MockEventBus) Dispatch(ctx context.Context, events ...event.Event) error {
+	return nil
+}
+
+func (m *MockEventBus) Redeliver(ctx context.Context, e event.Event, handlerName string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Redelivered = append(m.Redelivered, handlerName)
+	return nil
+}
+
+func (m *MockEventBus) SetDeadLetterSink(sink event.DeadLetterSink) {}
+
+func (m *MockEventBus) Start() error {
+	return nil
+}
+
+func (m *MockEventBus) Shutdown() error {
+	return nil
+}
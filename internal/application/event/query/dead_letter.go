@@ -0,0 +1,56 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+type DeadLetterQueryService interface {
+	ListDeadLetters(commonCtx *common.CommonContext, filter event.DeadLetterFilter) (*viewobject.DeadLetterListVO, error)
+	GetDeadLetter(commonCtx *common.CommonContext, id int64) (*viewobject.DeadLetterVO, error)
+}
+
+type deadLetterQueryService struct {
+	deadLetterRepo event.DeadLetterRepository
+}
+
+func NewDeadLetterQueryService(deadLetterRepo event.DeadLetterRepository) DeadLetterQueryService {
+	return &deadLetterQueryService{
+		deadLetterRepo: deadLetterRepo,
+	}
+}
+
+func (s *deadLetterQueryService) ListDeadLetters(commonCtx *common.CommonContext, filter event.DeadLetterFilter) (*viewobject.DeadLetterListVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can view dead letters")
+	}
+
+	deadLetters, total, err := s.deadLetterRepo.List(commonCtx.Ctx, filter)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_dead_letters")
+	}
+
+	vo := viewobject.NewDeadLetterListVO(deadLetters, total)
+	return &vo, nil
+}
+
+func (s *deadLetterQueryService) GetDeadLetter(commonCtx *common.CommonContext, id int64) (*viewobject.DeadLetterVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can view dead letters")
+	}
+
+	deadLetter, err := s.deadLetterRepo.Get(commonCtx.Ctx, id)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_dead_letter").
+			WithMetadata("dead_letter_id", id)
+	}
+	if deadLetter == nil {
+		return nil, apperror.ErrNotFound.WithMessage("dead letter not found").
+			WithMetadata("dead_letter_id", id)
+	}
+
+	vo := viewobject.NewDeadLetterVO(deadLetter, true)
+	return &vo, nil
+}
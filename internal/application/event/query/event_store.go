@@ -0,0 +1,53 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+type EventStoreQueryService interface {
+	// ListEvents searches the event store, oldest first
+	ListEvents(commonCtx *common.CommonContext, filter event.EventStoreFilter) (*viewobject.StoredEventListVO, error)
+	// GetTimeline returns everything that happened to one entity, oldest first
+	GetTimeline(commonCtx *common.CommonContext, aggregate event.AggregateRef, pagination common.Pagination) (*viewobject.StoredEventListVO, error)
+}
+
+type eventStoreQueryService struct {
+	eventStoreRepo event.EventStoreRepository
+}
+
+func NewEventStoreQueryService(eventStoreRepo event.EventStoreRepository) EventStoreQueryService {
+	return &eventStoreQueryService{
+		eventStoreRepo: eventStoreRepo,
+	}
+}
+
+func (s *eventStoreQueryService) ListEvents(commonCtx *common.CommonContext, filter event.EventStoreFilter) (*viewobject.StoredEventListVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can view the event store")
+	}
+	if filter.Aggregate != nil && !filter.Aggregate.Type.IsValid() {
+		return nil, apperror.ErrWrongInput.WithMessage("unknown aggregate type").
+			WithMetadata("aggregate_type", filter.Aggregate.Type)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, apperror.ErrWrongInput.WithMessage("time range start must be before its end")
+	}
+
+	storedEvents, total, err := s.eventStoreRepo.List(commonCtx.Ctx, filter)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_stored_events")
+	}
+
+	vo := viewobject.NewStoredEventListVO(storedEvents, total)
+	return &vo, nil
+}
+
+func (s *eventStoreQueryService) GetTimeline(commonCtx *common.CommonContext, aggregate event.AggregateRef, pagination common.Pagination) (*viewobject.StoredEventListVO, error) {
+	return s.ListEvents(commonCtx, event.EventStoreFilter{
+		Aggregate:  &aggregate,
+		Pagination: pagination,
+	})
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+type EventStoreService interface {
+	// Append keeps the published event in the event store
+	Append(ctx context.Context, e event.Event) error
+}
+
+type eventStoreService struct {
+	eventStoreRepo event.EventStoreRepository
+}
+
+func NewEventStoreService(eventStoreRepo event.EventStoreRepository) EventStoreService {
+	return &eventStoreService{
+		eventStoreRepo: eventStoreRepo,
+	}
+}
+
+func (s *eventStoreService) Append(ctx context.Context, e event.Event) error {
+	storedEvent, err := event.NewStoredEvent(e)
+	if err != nil {
+		return apperror.WrapInternal(err).WithMetadata("event_id", e.ID())
+	}
+	if err := s.eventStoreRepo.Append(ctx, &storedEvent); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "append_event").
+			WithMetadata("event_id", e.ID())
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+func TestEventStoreService_Append(t *testing.T) {
+	repo := &MockEventStoreRepository{}
+	service := NewEventStoreService(repo)
+
+	e := event.NewBaseEvent(event.TypeReviewDeleted, &event.ReviewPayload{ReviewID: 7, UserID: 3, CourseID: 5, Action: "deleted"})
+
+	assert.NoError(t, service.Append(context.Background(), e))
+	// Appending a redelivered event keeps a single copy
+	assert.NoError(t, service.Append(context.Background(), e))
+
+	assert.Len(t, repo.Events, 1)
+	stored := repo.Events[0]
+	assert.Equal(t, e.ID(), stored.EventID)
+	assert.Equal(t, event.TypeReviewDeleted, stored.EventType)
+	assert.ElementsMatch(t, []event.AggregateRef{
+		{Type: event.AggregateReview, ID: 7},
+		{Type: event.AggregateUser, ID: 3},
+		{Type: event.AggregateCourse, ID: 5},
+	}, stored.Aggregates)
+}
+
+func TestEventStoreService_AppendError(t *testing.T) {
+	repo := &MockEventStoreRepository{Err: errors.New("connection lost")}
+	service := NewEventStoreService(repo)
+
+	e := event.NewBaseEvent(event.TypeUserCreated, &event.UserPayload{UserID: 3})
+	err := service.Append(context.Background(), e)
+
+	var appErr *apperror.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrDB.Code, appErr.Code)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"jcourse_go/internal/domain/event"
+)
+
+// MockTransactor runs the function directly without a real transaction
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// MockOutboxRepository is an in-memory implementation of event.OutboxRepository for testing
+type MockOutboxRepository struct {
+	Messages []event.OutboxMessage
+	Sent     []int64
+	Failed   map[int64]bool
+}
+
+func (m *MockOutboxRepository) Save(ctx context.Context, messages ...event.OutboxMessage) error {
+	m.Messages = append(m.Messages, messages...)
+	return nil
+}
+
+func (m *MockOutboxRepository) FetchPending(ctx context.Context, limit int) ([]event.OutboxMessage, error) {
+	return m.Messages, nil
+}
+
+func (m *MockOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	m.Sent = append(m.Sent, id)
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, giveUp bool) error {
+	if m.Failed == nil {
+		m.Failed = make(map[int64]bool)
+	}
+	m.Failed[id] = giveUp
+	return nil
+}
+
+func (m *MockOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	return int64(len(m.Sent)), nil
+}
+
+// MockPublisher records the published events
+type MockPublisher struct {
+	Events []event.Event
+	Err    error
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Events = append(m.Events, events...)
+	return nil
+}
+
+// MockEventStoreRepository is an in-memory implementation of event.EventStoreRepository for testing
+type MockEventStoreRepository struct {
+	Events []event.StoredEvent
+	Err    error
+}
+
+func (m *MockEventStoreRepository) Append(ctx context.Context, storedEvent *event.StoredEvent) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for _, stored := range m.Events {
+		if stored.EventID == storedEvent.EventID {
+			return nil
+		}
+	}
+	storedEvent.ID = int64(len(m.Events) + 1)
+	m.Events = append(m.Events, *storedEvent)
+	return nil
+}
+
+func (m *MockEventStoreRepository) List(ctx context.Context, filter event.EventStoreFilter) ([]event.StoredEvent, int64, error) {
+	return m.Events, int64(len(m.Events)), m.Err
+}
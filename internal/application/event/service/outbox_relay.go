@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/pkg/apperror"
+)
+
+const (
+	DefaultRelayBatchSize = 100
+	MaxRelayAttempts      = 10
+)
+
+type OutboxRelayService interface {
+	// RelayPending delivers one batch of pending outbox messages and returns how many were sent
+	RelayPending(ctx context.Context) (int, error)
+	// PurgeSent removes messages that were delivered before the given time
+	PurgeSent(ctx context.Context, before time.Time) (int64, error)
+}
+
+type outboxRelayService struct {
+	outboxRepo event.OutboxRepository
+	transactor common.Transactor
+	publisher  event.Publisher
+	batchSize  int
+}
+
+func NewOutboxRelayService(
+	outboxRepo event.OutboxRepository,
+	transactor common.Transactor,
+	publisher event.Publisher,
+) OutboxRelayService {
+	return &outboxRelayService{
+		outboxRepo: outboxRepo,
+		transactor: transactor,
+		publisher:  publisher,
+		batchSize:  DefaultRelayBatchSize,
+	}
+}
+
+func (s *outboxRelayService) RelayPending(ctx context.Context) (int, error) {
+	sent := 0
+	// Rows stay locked until the batch is marked, so concurrent relays skip them
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		messages, err := s.outboxRepo.FetchPending(ctx, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if err := s.relay(ctx, &message); err != nil {
+				giveUp := message.Attempts+1 >= MaxRelayAttempts
+				log.Printf("Failed to relay outbox message %d (event %s, attempt %d): %v",
+					message.ID, message.EventID, message.Attempts+1, err)
+				if err := s.outboxRepo.MarkFailed(ctx, message.ID, err.Error(), giveUp); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.outboxRepo.MarkSent(ctx, message.ID); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "relay_outbox")
+	}
+	return sent, nil
+}
+
+func (s *outboxRelayService) relay(ctx context.Context, message *event.OutboxMessage) error {
+	e, err := message.ToEvent()
+	if err != nil {
+		return err
+	}
+	return s.publisher.Publish(ctx, e)
+}
+
+func (s *outboxRelayService) PurgeSent(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.outboxRepo.DeleteSentBefore(ctx, before)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "purge_outbox")
+	}
+	return deleted, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/event"
+)
+
+func newOutboxMessage(t *testing.T, id int64, attempts int) event.OutboxMessage {
+	e := event.NewBaseEvent(event.TypeReviewCreated, &event.ReviewPayload{ReviewID: 7, UserID: 3, Action: "created"})
+	message, err := event.NewOutboxMessage(e)
+	assert.NoError(t, err)
+	message.ID = id
+	message.Attempts = attempts
+	return message
+}
+
+func TestOutboxRelayService_RelayPending(t *testing.T) {
+	repo := &MockOutboxRepository{}
+	message := newOutboxMessage(t, 1, 0)
+	repo.Messages = []event.OutboxMessage{message}
+	publisher := &MockPublisher{}
+	service := NewOutboxRelayService(repo, &MockTransactor{}, publisher)
+
+	sent, err := service.RelayPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int64{1}, repo.Sent)
+	// The relayed event keeps its identity and typed payload
+	assert.Len(t, publisher.Events, 1)
+	assert.Equal(t, message.EventID, publisher.Events[0].ID())
+	payload, ok := publisher.Events[0].Payload().(*event.ReviewPayload)
+	assert.True(t, ok)
+	assert.Equal(t, 7, payload.ReviewID)
+}
+
+func TestOutboxRelayService_PublishFailure(t *testing.T) {
+	repo := &MockOutboxRepository{}
+	repo.Messages = []event.OutboxMessage{
+		newOutboxMessage(t, 1, 0),
+		newOutboxMessage(t, 2, MaxRelayAttempts-1),
+	}
+	publisher := &MockPublisher{Err: errors.New("queue full")}
+	service := NewOutboxRelayService(repo, &MockTransactor{}, publisher)
+
+	sent, err := service.RelayPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, repo.Sent)
+	// The message is retried until it reaches the attempt limit
+	assert.Equal(t, map[int64]bool{1: false, 2: true}, repo.Failed)
+}
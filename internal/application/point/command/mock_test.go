@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/point"
+)
+
+// MockUserPointRepository is an in-memory implementation of point.UserPointRepository for testing
+type MockUserPointRepository struct {
+	Records []point.UserPointRecord
+}
+
+func (m *MockUserPointRepository) GetUserAllPoints(ctx context.Context, userID int) (*point.UserPoint, error) {
+	userPoint := &point.UserPoint{}
+	for _, record := range m.Records {
+		if record.UserID == userID {
+			userPoint.Records = append(userPoint.Records, record)
+			userPoint.TotalPoint += record.Point
+		}
+	}
+	return userPoint, nil
+}
+
+func (m *MockUserPointRepository) GetPointRecord(ctx context.Context, itemID int) (*point.UserPointRecord, error) {
+	for _, record := range m.Records {
+		if record.ItemID == itemID {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockUserPointRepository) Save(ctx context.Context, record *point.UserPointRecord) error {
+	record.ItemID = len(m.Records) + 1
+	m.Records = append(m.Records, *record)
+	return nil
+}
+
+func (m *MockUserPointRepository) SumReviewPoints(ctx context.Context, userID int, reviewID int) (int, error) {
+	total := 0
+	for _, record := range m.Records {
+		if record.UserID == userID && record.ReviewID == reviewID {
+			total += record.Point
+		}
+	}
+	return total, nil
+}
@@ -12,8 +12,12 @@ type PointCommandService interface {
 	CreatePoint(commonCtx *common.CommonContext, userID int, amount int, reason string) error
 	Transaction(commonCtx *common.CommonContext, fromUserID int, toUserID int, amount int, reason string) error
 	AwardPointsForReview(commonCtx *common.CommonContext, userID int, reviewID int) error
+	RevokePointsForReview(commonCtx *common.CommonContext, userID int, reviewID int) error
 }
 
+// ReviewCreationPoints is awarded for writing a review
+const ReviewCreationPoints = 10
+
 func NewPointCommandService(repo point.UserPointRepository) PointCommandService {
 	return &pointCommandService{
 		repo: repo,
@@ -76,12 +80,9 @@ func (s *pointCommandService) Transaction(commonCtx *common.CommonContext, fromU
 
 // AwardPointsForReview awards points to a user for creating a review (internal system operation)
 func (s *pointCommandService) AwardPointsForReview(commonCtx *common.CommonContext, userID int, reviewID int) error {
-	// Define points to award for review creation
-	const reviewCreationPoints = 10
-
 	// Create point record with description
 	reason := fmt.Sprintf("Points awarded for creating review #%d", reviewID)
-	pointRecord := point.NewUserPointRecord(userID, reviewCreationPoints, reason)
+	pointRecord := point.NewReviewPointRecord(userID, reviewID, ReviewCreationPoints, reason)
 
 	// Save the point record
 	if err := s.repo.Save(commonCtx.Ctx, &pointRecord); err != nil {
@@ -89,7 +90,35 @@ func (s *pointCommandService) AwardPointsForReview(commonCtx *common.CommonConte
 			WithMetadata("operation", "award_points_for_review").
 			WithMetadata("user_id", userID).
 			WithMetadata("review_id", reviewID).
-			WithMetadata("points", reviewCreationPoints)
+			WithMetadata("points", ReviewCreationPoints)
+	}
+
+	return nil
+}
+
+// RevokePointsForReview takes back the points still held for a review that was deleted (internal system operation).
+// Reviews that were never awarded points, or whose points were already revoked, are left alone.
+func (s *pointCommandService) RevokePointsForReview(commonCtx *common.CommonContext, userID int, reviewID int) error {
+	awarded, err := s.repo.SumReviewPoints(commonCtx.Ctx, userID, reviewID)
+	if err != nil {
+		return apperror.WrapDB(err).
+			WithMetadata("operation", "sum_review_points").
+			WithMetadata("user_id", userID).
+			WithMetadata("review_id", reviewID)
+	}
+	if awarded <= 0 {
+		return nil
+	}
+
+	reason := fmt.Sprintf("Points revoked for deleting review #%d", reviewID)
+	pointRecord := point.NewReviewPointRecord(userID, reviewID, -awarded, reason)
+
+	if err := s.repo.Save(commonCtx.Ctx, &pointRecord); err != nil {
+		return apperror.WrapDB(err).
+			WithMetadata("operation", "revoke_points_for_review").
+			WithMetadata("user_id", userID).
+			WithMetadata("review_id", reviewID).
+			WithMetadata("points", -awarded)
 	}
 
 	return nil
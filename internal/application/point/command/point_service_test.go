@@ -0,0 +1,69 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/point"
+)
+
+func systemContext() *common.CommonContext {
+	return &common.CommonContext{
+		Ctx:  context.Background(),
+		User: &common.User{Role: common.RoleAdmin},
+	}
+}
+
+func userTotal(t *testing.T, repo *MockUserPointRepository, userID int) int {
+	userPoint, err := repo.GetUserAllPoints(context.Background(), userID)
+	assert.NoError(t, err)
+	return userPoint.TotalPoint
+}
+
+func TestPointCommandService_RevokeTakesBackAwardedPoints(t *testing.T) {
+	repo := &MockUserPointRepository{}
+	service := NewPointCommandService(repo)
+	assert.NoError(t, service.AwardPointsForReview(systemContext(), 3, 7))
+
+	assert.NoError(t, service.RevokePointsForReview(systemContext(), 3, 7))
+
+	assert.Equal(t, 0, userTotal(t, repo, 3))
+	assert.Equal(t, 7, repo.Records[1].ReviewID)
+}
+
+func TestPointCommandService_RevokeTakesBackOnlyWhatWasGranted(t *testing.T) {
+	// Awarded before the review points were raised
+	record := point.NewReviewPointRecord(3, 7, 5, "Points awarded for creating review #7")
+	repo := &MockUserPointRepository{Records: []point.UserPointRecord{record}}
+	service := NewPointCommandService(repo)
+
+	assert.NoError(t, service.RevokePointsForReview(systemContext(), 3, 7))
+
+	assert.Equal(t, 0, userTotal(t, repo, 3))
+}
+
+func TestPointCommandService_RevokeWithoutAwardKeepsPoints(t *testing.T) {
+	repo := &MockUserPointRepository{}
+	service := NewPointCommandService(repo)
+	assert.NoError(t, service.AwardPointsForReview(systemContext(), 3, 7))
+
+	assert.NoError(t, service.RevokePointsForReview(systemContext(), 3, 8))
+
+	assert.Equal(t, ReviewCreationPoints, userTotal(t, repo, 3))
+	assert.Len(t, repo.Records, 1)
+}
+
+func TestPointCommandService_RevokeTwiceRevokesOnce(t *testing.T) {
+	repo := &MockUserPointRepository{}
+	service := NewPointCommandService(repo)
+	assert.NoError(t, service.AwardPointsForReview(systemContext(), 3, 7))
+
+	assert.NoError(t, service.RevokePointsForReview(systemContext(), 3, 7))
+	assert.NoError(t, service.RevokePointsForReview(systemContext(), 3, 7))
+
+	assert.Equal(t, 0, userTotal(t, repo, 3))
+	assert.Len(t, repo.Records, 2)
+}
@@ -1,8 +1,12 @@
 package command
 
 import (
+	"context"
+
 	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
 	"jcourse_go/internal/domain/review"
+	"jcourse_go/pkg/apperror"
 )
 
 type CourseCommandService interface {
@@ -11,21 +15,67 @@ type CourseCommandService interface {
 }
 
 type courseCommandService struct {
-	courseRepo review.CourseRepository
+	courseRepo     review.CourseRepository
+	transactor     common.Transactor
+	eventPublisher event.Publisher
 }
 
 func NewCourseCommandService(
 	courseRepo review.CourseRepository,
+	transactor common.Transactor,
+	eventPublisher event.Publisher,
 ) CourseCommandService {
 	return &courseCommandService{
-		courseRepo: courseRepo,
+		courseRepo:     courseRepo,
+		transactor:     transactor,
+		eventPublisher: eventPublisher,
 	}
 }
 
 func (s *courseCommandService) AddUserEnrolledCourse(commonCtx *common.CommonContext, courseID int) error {
-	return s.courseRepo.AddUserEnrolledCourse(commonCtx.Ctx, commonCtx.User.UserID, courseID)
+	userID := commonCtx.User.UserID
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.courseRepo.AddUserEnrolledCourse(ctx, userID, courseID); err != nil {
+			return err
+		}
+
+		payload := &event.CoursePayload{
+			UserID:   userID,
+			CourseID: courseID,
+			Action:   "enrolled",
+		}
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeCourseEnrolled, payload)); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "publish_course_enrolled_event").WithMetadata("course_id", courseID)
+		}
+		return nil
+	})
 }
 
 func (s *courseCommandService) WatchCourse(commonCtx *common.CommonContext, courseID int, watch bool) error {
-	return s.courseRepo.WatchCourse(commonCtx.Ctx, commonCtx.User.UserID, courseID, watch)
+	userID := commonCtx.User.UserID
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		changed, err := s.courseRepo.WatchCourse(ctx, userID, courseID, watch)
+		if err != nil {
+			return err
+		}
+		// Watching a watched course, or unwatching an unwatched one, is no event
+		if !changed {
+			return nil
+		}
+
+		payload := &event.CoursePayload{
+			UserID:   userID,
+			CourseID: courseID,
+			Action:   "watched",
+		}
+		eventType := event.TypeCourseWatched
+		if !watch {
+			payload.Action = "unwatched"
+			eventType = event.TypeCourseUnwatched
+		}
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(eventType, payload)); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "publish_course_watch_event").WithMetadata("course_id", courseID)
+		}
+		return nil
+	})
 }
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"time"
@@ -31,6 +32,7 @@ type reviewCommandService struct {
 	reviewRepo        review.ReviewRepository
 	courseRepo        review.CourseRepository
 	permissionService permission.PermissionService
+	transactor        common.Transactor
 	eventPublisher    event.Publisher
 }
 
@@ -38,11 +40,13 @@ func NewReviewCommandService(
 	reviewRepo review.ReviewRepository,
 	courseRepo review.CourseRepository,
 	permissionService permission.PermissionService,
+	transactor common.Transactor,
 	eventPublisher event.Publisher) ReviewCommandService {
 	return &reviewCommandService{
 		reviewRepo:        reviewRepo,
 		courseRepo:        courseRepo,
 		permissionService: permissionService,
+		transactor:        transactor,
 		eventPublisher:    eventPublisher,
 	}
 }
@@ -72,11 +76,12 @@ func (s *reviewCommandService) WriteReview(commonCtx *common.CommonContext, cmd
 	if err := s.ValidateReview(commonCtx, &r); err != nil {
 		return err
 	}
-	if err := s.reviewRepo.Save(commonCtx.Ctx, &r, nil); err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "write_review").WithMetadata("user_id", commonCtx.User.UserID)
-	}
+	// The review and its event are committed together, so the event is never lost or orphaned
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.Save(ctx, &r, nil); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "write_review").WithMetadata("user_id", commonCtx.User.UserID)
+		}
 
-	if s.eventPublisher != nil {
 		payload := &event.ReviewPayload{
 			ReviewID: r.ID,
 			UserID:   r.UserID,
@@ -85,14 +90,11 @@ func (s *reviewCommandService) WriteReview(commonCtx *common.CommonContext, cmd
 			Content:  r.Comment,
 			Action:   "created",
 		}
-
-		reviewEvent := event.NewBaseEvent(event.TypeReviewCreated, payload)
-		if err := s.eventPublisher.Publish(commonCtx.Ctx, reviewEvent); err != nil {
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeReviewCreated, payload)); err != nil {
 			return apperror.WrapDB(err).WithMetadata("operation", "publish_review_created_event").WithMetadata("review_id", r.ID)
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 func (s *reviewCommandService) UpdateReview(commonCtx *common.CommonContext, cmd *review.UpdateReviewCommand) error {
@@ -123,11 +125,11 @@ func (s *reviewCommandService) UpdateReview(commonCtx *common.CommonContext, cmd
 	if err := s.ValidateReview(commonCtx, r); err != nil {
 		return err
 	}
-	if err := s.reviewRepo.Save(commonCtx.Ctx, r, &revision); err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "update_review").WithMetadata("review_id", cmd.ReviewID)
-	}
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.Save(ctx, r, &revision); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "update_review").WithMetadata("review_id", cmd.ReviewID)
+		}
 
-	if s.eventPublisher != nil {
 		payload := &event.ReviewPayload{
 			ReviewID: r.ID,
 			UserID:   r.UserID,
@@ -136,14 +138,11 @@ func (s *reviewCommandService) UpdateReview(commonCtx *common.CommonContext, cmd
 			Content:  r.Comment,
 			Action:   "modified",
 		}
-
-		reviewEvent := event.NewBaseEvent(event.TypeReviewModified, payload)
-		if err := s.eventPublisher.Publish(commonCtx.Ctx, reviewEvent); err != nil {
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeReviewModified, payload)); err != nil {
 			return apperror.WrapDB(err).WithMetadata("operation", "publish_review_modified_event").WithMetadata("review_id", r.ID)
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 func (s *reviewCommandService) DeleteReview(commonCtx *common.CommonContext, cmd *review.DeleteReviewCommand) error {
@@ -169,10 +168,24 @@ func (s *reviewCommandService) DeleteReview(commonCtx *common.CommonContext, cmd
 			WithMetadata("owner_id", r.UserID)
 	}
 
-	if err := s.reviewRepo.Delete(commonCtx.Ctx, review.ReviewFilter{ReviewID: &cmd.ReviewID}); err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "delete_review").WithMetadata("review_id", cmd.ReviewID)
-	}
-	return nil
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.Delete(ctx, review.ReviewFilter{ReviewID: &cmd.ReviewID}); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "delete_review").WithMetadata("review_id", cmd.ReviewID)
+		}
+
+		payload := &event.ReviewPayload{
+			ReviewID: r.ID,
+			UserID:   r.UserID,
+			CourseID: r.CourseID,
+			Rating:   r.Rating.Int(),
+			Content:  r.Comment,
+			Action:   "deleted",
+		}
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeReviewDeleted, payload)); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "publish_review_deleted_event").WithMetadata("review_id", r.ID)
+		}
+		return nil
+	})
 }
 
 func (s *reviewCommandService) PostReviewAction(commonCtx *common.CommonContext, reviewID int, actionType string) error {
@@ -193,10 +206,23 @@ func (s *reviewCommandService) PostReviewAction(commonCtx *common.CommonContext,
 	}
 
 	action := review.NewReviewAction(reviewID, commonCtx.User.UserID, actionType)
-	if err := s.reviewRepo.SaveReviewAction(commonCtx.Ctx, &action); err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "post_review_action").WithMetadata("review_id", reviewID).WithMetadata("action_type", actionType)
-	}
-	return nil
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.SaveReviewAction(ctx, &action); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "post_review_action").WithMetadata("review_id", reviewID).WithMetadata("action_type", actionType)
+		}
+
+		payload := &event.ReviewActionPayload{
+			ActionID:   action.ID,
+			ReviewID:   action.ReviewID,
+			UserID:     action.UserID,
+			ActionType: action.ActionType,
+			Action:     "posted",
+		}
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeReviewActionPosted, payload)); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "publish_review_action_posted_event").WithMetadata("review_id", reviewID)
+		}
+		return nil
+	})
 }
 
 func (s *reviewCommandService) DeleteReviewAction(commonCtx *common.CommonContext, reviewID int, actionID int) error {
@@ -232,10 +258,23 @@ func (s *reviewCommandService) DeleteReviewAction(commonCtx *common.CommonContex
 			WithMetadata("action_owner_id", action.UserID)
 	}
 
-	if err := s.reviewRepo.DeleteReviewAction(commonCtx.Ctx, actionID); err != nil {
-		return apperror.WrapDB(err).WithMetadata("operation", "delete_review_action").WithMetadata("review_id", reviewID).WithMetadata("action_id", actionID)
-	}
-	return nil
+	return s.transactor.WithinTransaction(commonCtx.Ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.DeleteReviewAction(ctx, actionID); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "delete_review_action").WithMetadata("review_id", reviewID).WithMetadata("action_id", actionID)
+		}
+
+		payload := &event.ReviewActionPayload{
+			ActionID:   action.ID,
+			ReviewID:   action.ReviewID,
+			UserID:     action.UserID,
+			ActionType: action.ActionType,
+			Action:     "deleted",
+		}
+		if err := s.eventPublisher.Publish(ctx, event.NewBaseEvent(event.TypeReviewActionDeleted, payload)); err != nil {
+			return apperror.WrapDB(err).WithMetadata("operation", "publish_review_action_deleted_event").WithMetadata("action_id", actionID)
+		}
+		return nil
+	})
 }
 
 func (s *reviewCommandService) checkRateLimit(commonCtx *common.CommonContext, userID int) error {
@@ -8,9 +8,9 @@ import (
 )
 
 type ReviewQueryService interface {
-	LatestReviews(commonCtx *common.CommonContext) ([]viewobject.ReviewVO, error)
-	CourseReviews(commonCtx *common.CommonContext, courseID int) ([]viewobject.ReviewVO, error)
-	GetUserReviews(commonCtx *common.CommonContext) ([]viewobject.ReviewVO, error)
+	LatestReviews(commonCtx *common.CommonContext, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error)
+	CourseReviews(commonCtx *common.CommonContext, courseID int, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error)
+	GetUserReviews(commonCtx *common.CommonContext, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error)
 	GetReviewRevisions(commonCtx *common.CommonContext, reviewID int) ([]viewobject.ReviewRevisionVO, error)
 }
 
@@ -29,36 +29,35 @@ func NewReviewQueryService(
 	}
 }
 
-func (s *reviewQueryService) LatestReviews(commonCtx *common.CommonContext) ([]viewobject.ReviewVO, error) {
-	reviews, err := s.reviewRepo.FindBy(commonCtx.Ctx, review.ReviewFilter{})
-	if err != nil {
-		return nil, apperror.ErrDB
-	}
-	return s.listReviews(commonCtx, reviews, true), nil
+func (s *reviewQueryService) LatestReviews(commonCtx *common.CommonContext, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error) {
+	return s.listReviews(commonCtx, review.ReviewFilter{}, opts, true)
 }
 
-func (s *reviewQueryService) CourseReviews(commonCtx *common.CommonContext, courseID int) ([]viewobject.ReviewVO, error) {
-	reviews, err := s.reviewRepo.FindBy(commonCtx.Ctx, review.ReviewFilter{CourseID: &courseID})
-	if err != nil {
-		return nil, apperror.ErrDB
-	}
-	return s.listReviews(commonCtx, reviews, false), nil
+func (s *reviewQueryService) CourseReviews(commonCtx *common.CommonContext, courseID int, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error) {
+	return s.listReviews(commonCtx, review.ReviewFilter{CourseID: &courseID}, opts, false)
 }
 
-func (s *reviewQueryService) listReviews(commonCtx *common.CommonContext, reviews []review.Review, withCourse bool) []viewobject.ReviewVO {
-	reviewList := make([]viewobject.ReviewVO, len(reviews))
-	for i, r := range reviews {
-		reviewList[i] = viewobject.NewReviewVO(&r, withCourse)
-	}
-	return reviewList
+func (s *reviewQueryService) GetUserReviews(commonCtx *common.CommonContext, opts review.ReviewListOptions) (*viewobject.ReviewListVO, error) {
+	return s.listReviews(commonCtx, review.ReviewFilter{UserID: &commonCtx.User.UserID}, opts, true)
 }
 
-func (s *reviewQueryService) GetUserReviews(commonCtx *common.CommonContext) ([]viewobject.ReviewVO, error) {
-	reviews, err := s.reviewRepo.FindBy(commonCtx.Ctx, review.ReviewFilter{UserID: &commonCtx.User.UserID})
+// listReviews returns one page of the reviews matching filter, with the total across all pages
+func (s *reviewQueryService) listReviews(commonCtx *common.CommonContext, filter review.ReviewFilter, opts review.ReviewListOptions, withCourse bool) (*viewobject.ReviewListVO, error) {
+	if err := opts.Apply(&filter); err != nil {
+		return nil, apperror.ErrValidation.WithMessage(err.Error())
+	}
+
+	reviews, err := s.reviewRepo.FindBy(commonCtx.Ctx, filter)
 	if err != nil {
-		return nil, apperror.ErrDB.Wrap(err)
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_reviews")
 	}
-	return s.listReviews(commonCtx, reviews, true), nil
+	total, err := s.reviewRepo.CountBy(commonCtx.Ctx, filter)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "count_reviews")
+	}
+
+	list := viewobject.NewReviewListVO(reviews, total, review.NextReviewCursor(filter, reviews), withCourse)
+	return &list, nil
 }
 
 func (s *reviewQueryService) GetReviewRevisions(commonCtx *common.CommonContext, reviewID int) ([]viewobject.ReviewRevisionVO, error) {
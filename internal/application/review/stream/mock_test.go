@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/review"
+)
+
+// MockReviewRepository serves reviews from memory for testing
+type MockReviewRepository struct {
+	Reviews map[int]review.Review
+}
+
+func (m *MockReviewRepository) Get(ctx context.Context, id int) (*review.Review, error) {
+	r, ok := m.Reviews[id]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (m *MockReviewRepository) FindBy(ctx context.Context, filter review.ReviewFilter) ([]review.Review, error) {
+	if filter.ReviewID == nil {
+		return nil, nil
+	}
+	r, ok := m.Reviews[*filter.ReviewID]
+	if !ok {
+		return nil, nil
+	}
+	return []review.Review{r}, nil
+}
+
+func (m *MockReviewRepository) CountBy(ctx context.Context, filter review.ReviewFilter) (int64, error) {
+	reviews, err := m.FindBy(ctx, filter)
+	return int64(len(reviews)), err
+}
+
+func (m *MockReviewRepository) Save(ctx context.Context, r *review.Review, revision *review.ReviewRevision) error {
+	return nil
+}
+
+func (m *MockReviewRepository) Delete(ctx context.Context, filter review.ReviewFilter) error {
+	return nil
+}
+
+func (m *MockReviewRepository) SaveReviewAction(ctx context.Context, action *review.ReviewAction) error {
+	return nil
+}
+
+func (m *MockReviewRepository) DeleteReviewAction(ctx context.Context, actionID int) error {
+	return nil
+}
+
+func (m *MockReviewRepository) GetReviewAction(ctx context.Context, actionID int) (*review.ReviewAction, error) {
+	return nil, nil
+}
+
+func (m *MockReviewRepository) GetUserReviewActions(ctx context.Context, userID int) ([]review.ReviewAction, error) {
+	return nil, nil
+}
+
+func (m *MockReviewRepository) GetReviewRevisions(ctx context.Context, reviewID int) ([]review.ReviewRevision, error) {
+	return nil, nil
+}
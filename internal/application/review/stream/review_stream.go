@@ -0,0 +1,183 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"jcourse_go/internal/application/viewobject"
+	"jcourse_go/internal/domain/review"
+)
+
+const (
+	// DefaultHistorySize is how many recent reviews are kept for Last-Event-ID resume
+	DefaultHistorySize = 100
+	// subscriberBufferSize is how many reviews a subscriber may lag behind before it is disconnected
+	subscriberBufferSize = 16
+)
+
+// Filter narrows a stream to one course or one main teacher; nil fields match everything
+type Filter struct {
+	CourseID  *int
+	TeacherID *int
+}
+
+type entry struct {
+	review        viewobject.ReviewVO
+	courseID      int
+	mainTeacherID int
+}
+
+func (f Filter) matches(e entry) bool {
+	if f.CourseID != nil && *f.CourseID != e.courseID {
+		return false
+	}
+	if f.TeacherID != nil && *f.TeacherID != e.mainTeacherID {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter  Filter
+	updates chan viewobject.ReviewVO
+}
+
+type ReviewStreamService interface {
+	// Broadcast loads the new review and pushes it to the matching subscribers
+	Broadcast(ctx context.Context, reviewID int) error
+	// Refresh reloads an edited review kept in the history, so resuming subscribers get the current version
+	Refresh(ctx context.Context, reviewID int) error
+	// Forget removes a deleted review from the history, so resuming subscribers no longer get it
+	Forget(reviewID int)
+	// Subscribe returns the recent reviews newer than lastEventID followed by a channel of new ones.
+	// The channel is closed when the subscriber falls too far behind; unsubscribe must always be called.
+	Subscribe(filter Filter, lastEventID int) (backlog []viewobject.ReviewVO, updates <-chan viewobject.ReviewVO, unsubscribe func())
+}
+
+type reviewStreamService struct {
+	reviewRepo review.ReviewRepository
+
+	mu          sync.Mutex
+	history     []entry
+	historySize int
+	subscribers map[*subscriber]struct{}
+}
+
+func NewReviewStreamService(reviewRepo review.ReviewRepository) ReviewStreamService {
+	return &reviewStreamService{
+		reviewRepo:  reviewRepo,
+		historySize: DefaultHistorySize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func (s *reviewStreamService) Broadcast(ctx context.Context, reviewID int) error {
+	e, err := s.load(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		return fmt.Errorf("review %d not found", reviewID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, *e)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+
+	for sub := range s.subscribers {
+		if !sub.filter.matches(*e) {
+			continue
+		}
+		select {
+		case sub.updates <- e.review:
+		default:
+			// A stalled client reconnects with Last-Event-ID and resumes from the history
+			close(sub.updates)
+			delete(s.subscribers, sub)
+		}
+	}
+	return nil
+}
+
+func (s *reviewStreamService) Refresh(ctx context.Context, reviewID int) error {
+	e, err := s.load(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		s.Forget(reviewID)
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.history {
+		if s.history[i].review.ID == reviewID {
+			s.history[i] = *e
+		}
+	}
+	return nil
+}
+
+func (s *reviewStreamService) Forget(reviewID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = slices.DeleteFunc(s.history, func(e entry) bool { return e.review.ID == reviewID })
+}
+
+// load returns the stream entry of the review, or nil when it no longer exists
+func (s *reviewStreamService) load(ctx context.Context, reviewID int) (*entry, error) {
+	reviews, err := s.reviewRepo.FindBy(ctx, review.ReviewFilter{ReviewID: &reviewID})
+	if err != nil || len(reviews) == 0 {
+		return nil, err
+	}
+
+	r := reviews[0]
+	e := &entry{
+		review:   viewobject.NewReviewVO(&r, true),
+		courseID: r.CourseID,
+	}
+	if r.Course != nil {
+		e.mainTeacherID = r.Course.MainTeacherID
+	}
+	return e, nil
+}
+
+func (s *reviewStreamService) Subscribe(filter Filter, lastEventID int) ([]viewobject.ReviewVO, <-chan viewobject.ReviewVO, func()) {
+	sub := &subscriber{
+		filter:  filter,
+		updates: make(chan viewobject.ReviewVO, subscriberBufferSize),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var backlog []viewobject.ReviewVO
+	if lastEventID > 0 {
+		for _, e := range s.history {
+			if e.review.ID > lastEventID && filter.matches(e) {
+				backlog = append(backlog, e.review)
+			}
+		}
+	}
+	s.subscribers[sub] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.subscribers[sub]; ok {
+			close(sub.updates)
+			delete(s.subscribers, sub)
+		}
+	}
+	return backlog, sub.updates, unsubscribe
+}
@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/application/viewobject"
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/review"
+)
+
+func newTestStream() ReviewStreamService {
+	course1 := &review.Course{ID: 1, Code: "CS101", Name: "程序设计", MainTeacherID: 10}
+	course2 := &review.Course{ID: 2, Code: "MA101", Name: "高等数学", MainTeacherID: 20}
+	repo := &MockReviewRepository{Reviews: map[int]review.Review{
+		1: {ID: 1, CourseID: 1, Course: course1, User: &auth.User{ID: 7, Username: "alice@example.com"}, Comment: "good"},
+		2: {ID: 2, CourseID: 2, Course: course2, Comment: "hard"},
+		3: {ID: 3, CourseID: 1, Course: course1, Comment: "fun"},
+	}}
+	return NewReviewStreamService(repo)
+}
+
+func TestReviewStreamService_BroadcastToSubscribers(t *testing.T) {
+	service := newTestStream()
+	backlog, updates, unsubscribe := service.Subscribe(Filter{}, 0)
+	defer unsubscribe()
+
+	assert.NoError(t, service.Broadcast(context.Background(), 1))
+
+	assert.Empty(t, backlog)
+	review := <-updates
+	assert.Equal(t, 1, review.ID)
+	assert.Equal(t, "CS101", review.Course.Code)
+	assert.Equal(t, viewobject.AnonymousUserVO, review.User)
+}
+
+func TestReviewStreamService_Filters(t *testing.T) {
+	service := newTestStream()
+	courseID, teacherID := 2, 10
+	_, byCourse, unsubscribeCourse := service.Subscribe(Filter{CourseID: &courseID}, 0)
+	defer unsubscribeCourse()
+	_, byTeacher, unsubscribeTeacher := service.Subscribe(Filter{TeacherID: &teacherID}, 0)
+	defer unsubscribeTeacher()
+
+	for _, id := range []int{1, 2, 3} {
+		assert.NoError(t, service.Broadcast(context.Background(), id))
+	}
+
+	assert.Len(t, byCourse, 1)
+	assert.Equal(t, 2, (<-byCourse).ID)
+	assert.Len(t, byTeacher, 2)
+	assert.Equal(t, 1, (<-byTeacher).ID)
+	assert.Equal(t, 3, (<-byTeacher).ID)
+}
+
+func TestReviewStreamService_ResumeFromLastEventID(t *testing.T) {
+	service := newTestStream()
+	for _, id := range []int{1, 2, 3} {
+		assert.NoError(t, service.Broadcast(context.Background(), id))
+	}
+
+	backlog, _, unsubscribe := service.Subscribe(Filter{}, 1)
+	defer unsubscribe()
+
+	assert.Len(t, backlog, 2)
+	assert.Equal(t, 2, backlog[0].ID)
+	assert.Equal(t, 3, backlog[1].ID)
+}
+
+func TestReviewStreamService_DisconnectsStalledSubscriber(t *testing.T) {
+	service := newTestStream()
+	_, updates, unsubscribe := service.Subscribe(Filter{}, 0)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberBufferSize; i++ {
+		assert.NoError(t, service.Broadcast(context.Background(), 1))
+	}
+
+	received := 0
+	for range updates {
+		received++
+	}
+	assert.Equal(t, subscriberBufferSize, received)
+}
+
+func TestReviewStreamService_UnknownReview(t *testing.T) {
+	service := newTestStream()
+
+	assert.Error(t, service.Broadcast(context.Background(), 99))
+}
+
+func TestReviewStreamService_ResumeSkipsDeletedReviews(t *testing.T) {
+	service := newTestStream()
+	for _, id := range []int{1, 2, 3} {
+		assert.NoError(t, service.Broadcast(context.Background(), id))
+	}
+
+	service.Forget(2)
+	backlog, _, unsubscribe := service.Subscribe(Filter{}, 1)
+	defer unsubscribe()
+
+	assert.Len(t, backlog, 1)
+	assert.Equal(t, 3, backlog[0].ID)
+}
+
+func TestReviewStreamService_ResumeGetsEditedReviews(t *testing.T) {
+	course := &review.Course{ID: 1, Code: "CS101", Name: "程序设计", MainTeacherID: 10}
+	repo := &MockReviewRepository{Reviews: map[int]review.Review{
+		1: {ID: 1, CourseID: 1, Course: course, Comment: "good"},
+		2: {ID: 2, CourseID: 1, Course: course, Comment: "fun"},
+		3: {ID: 3, CourseID: 1, Course: course, Comment: "hard"},
+	}}
+	service := NewReviewStreamService(repo)
+	for _, id := range []int{1, 2, 3} {
+		assert.NoError(t, service.Broadcast(context.Background(), id))
+	}
+
+	edited := repo.Reviews[2]
+	edited.Comment = "edited"
+	repo.Reviews[2] = edited
+	assert.NoError(t, service.Refresh(context.Background(), 2))
+	// A review that no longer exists is dropped like a deleted one
+	delete(repo.Reviews, 3)
+	assert.NoError(t, service.Refresh(context.Background(), 3))
+
+	backlog, _, unsubscribe := service.Subscribe(Filter{}, 1)
+	defer unsubscribe()
+
+	assert.Len(t, backlog, 1)
+	assert.Equal(t, 2, backlog[0].ID)
+	assert.Equal(t, "edited", backlog[0].Comment)
+}
@@ -15,6 +15,8 @@ type DailyStatisticsService interface {
 	GetDailyStatisticsRange(commonCtx *common.CommonContext, startDate, endDate time.Time) ([]viewobject.DailyStatisticsVO, error)
 	GetLatestDailyStatistics(commonCtx *common.CommonContext) (*viewobject.DailyStatisticsVO, error)
 	CalculateAndSaveDailyStatistics(ctx context.Context, date time.Time) error
+	// IncrementDailyCounter adds delta to today's value of the named counter
+	IncrementDailyCounter(ctx context.Context, name string, delta int) error
 }
 
 type dailyStatisticsService struct {
@@ -73,6 +75,7 @@ func (s *dailyStatisticsService) CalculateAndSaveDailyStatistics(ctx context.Con
 		TotalReviews:       currentStats.TotalReviews,
 		TotalCourses:       currentStats.TotalCourses,
 		CoursesWithReviews: currentStats.CoursesWithReviews,
+		DailyLikes:         currentStats.DailyLikes,
 	}
 
 	// Save daily statistics
@@ -83,3 +86,11 @@ func (s *dailyStatisticsService) CalculateAndSaveDailyStatistics(ctx context.Con
 
 	return nil
 }
+
+func (s *dailyStatisticsService) IncrementDailyCounter(ctx context.Context, name string, delta int) error {
+	if err := s.statisticsRepo.IncrementDailyCounter(ctx, name, delta); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "increment_daily_counter").
+			WithMetadata("counter", name)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package viewobject
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/review"
+)
+
+type AccountDeletionVO struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// AccountExportVO is the archive of everything the user contributed, see GET /user/export
+type AccountExportVO struct {
+	ExportedAt        time.Time                `json:"exported_at"`
+	Profile           UserInfoVO               `json:"profile"`
+	Reviews           []ExportedReviewVO       `json:"reviews"`
+	ReviewActions     []ExportedReviewActionVO `json:"review_actions"`
+	Points            UserPointVO              `json:"points"`
+	EnrolledCourseIDs []int                    `json:"enrolled_course_ids"`
+}
+
+type ExportedReviewVO struct {
+	ID        int                `json:"id"`
+	CourseID  int                `json:"course_id"`
+	Semester  string             `json:"semester"`
+	Grade     string             `json:"grade"`
+	Comment   string             `json:"comment"`
+	Rating    int                `json:"rating"`
+	CreatedAt int64              `json:"created_at"`
+	UpdatedAt int64              `json:"updated_at"`
+	Revisions []ReviewRevisionVO `json:"revisions"`
+}
+
+func NewExportedReviewVO(r *review.Review, revisions []review.ReviewRevision) ExportedReviewVO {
+	vo := ExportedReviewVO{
+		ID:        r.ID,
+		CourseID:  r.CourseID,
+		Semester:  r.Semester.String(),
+		Grade:     r.Grade,
+		Comment:   r.Comment,
+		Rating:    r.Rating.Int(),
+		CreatedAt: r.CreatedAt.Unix(),
+		UpdatedAt: r.UpdatedAt.Unix(),
+		Revisions: make([]ReviewRevisionVO, len(revisions)),
+	}
+	for i, revision := range revisions {
+		vo.Revisions[i] = NewReviewRevisionVO(&revision)
+	}
+	return vo
+}
+
+type ExportedReviewActionVO struct {
+	ID         int    `json:"id"`
+	ReviewID   int    `json:"review_id"`
+	ActionType string `json:"action_type"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+func NewExportedReviewActionVO(a *review.ReviewAction) ExportedReviewActionVO {
+	return ExportedReviewActionVO{
+		ID:         a.ID,
+		ReviewID:   a.ReviewID,
+		ActionType: a.ActionType,
+		CreatedAt:  a.CreatedAt.Unix(),
+	}
+}
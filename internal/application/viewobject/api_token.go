@@ -0,0 +1,34 @@
+package viewobject
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+)
+
+type APITokenVO struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Scopes     []common.Scope `json:"scopes"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+func NewAPITokenVO(token auth.APIToken) APITokenVO {
+	return APITokenVO{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// CreatedAPITokenVO carries the secret of a new token, which is never shown again
+type CreatedAPITokenVO struct {
+	APITokenVO
+	Token string `json:"token"`
+}
@@ -0,0 +1,59 @@
+package viewobject
+
+import (
+	"encoding/json"
+
+	"jcourse_go/internal/domain/event"
+)
+
+type DeadLetterVO struct {
+	ID         int64           `json:"id"`
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	Version    int             `json:"version"`
+	Handler    string          `json:"handler"`
+	Status     string          `json:"status"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	OccurredAt int64           `json:"occurred_at"`
+	CreatedAt  int64           `json:"created_at"`
+	UpdatedAt  int64           `json:"updated_at"`
+}
+
+type DeadLetterListVO struct {
+	Total int64          `json:"total"`
+	Items []DeadLetterVO `json:"items"`
+}
+
+// NewDeadLetterVO builds the view of a dead letter; the payload is only included when inspecting a single one
+func NewDeadLetterVO(d *event.DeadLetter, withPayload bool) DeadLetterVO {
+	vo := DeadLetterVO{
+		ID:         d.ID,
+		EventID:    d.EventID,
+		EventType:  string(d.EventType),
+		Version:    d.Version,
+		Handler:    d.Handler,
+		Status:     string(d.Status),
+		Attempts:   d.Attempts,
+		LastError:  d.LastError,
+		OccurredAt: d.OccurredAt.Unix(),
+		CreatedAt:  d.CreatedAt.Unix(),
+		UpdatedAt:  d.UpdatedAt.Unix(),
+	}
+	if withPayload {
+		vo.Payload = json.RawMessage(d.Payload)
+	}
+	return vo
+}
+
+func NewDeadLetterListVO(deadLetters []event.DeadLetter, total int64) DeadLetterListVO {
+	vo := DeadLetterListVO{
+		Total: total,
+		Items: make([]DeadLetterVO, len(deadLetters)),
+	}
+	for i, d := range deadLetters {
+		vo.Items[i] = NewDeadLetterVO(&d, false)
+	}
+	return vo
+}
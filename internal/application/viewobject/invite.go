@@ -0,0 +1,45 @@
+package viewobject
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/auth"
+)
+
+type InviteCodeVO struct {
+	ID        int        `json:"id"`
+	Code      string     `json:"code"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedBy int        `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type InviteCodeListVO struct {
+	Total int64          `json:"total"`
+	Items []InviteCodeVO `json:"items"`
+}
+
+func NewInviteCodeVO(invite auth.InviteCode) InviteCodeVO {
+	return InviteCodeVO{
+		ID:        invite.ID,
+		Code:      invite.Code,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedBy: invite.CreatedBy,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+func NewInviteCodeListVO(invites []auth.InviteCode, total int64) InviteCodeListVO {
+	vo := InviteCodeListVO{
+		Total: total,
+		Items: make([]InviteCodeVO, len(invites)),
+	}
+	for i, invite := range invites {
+		vo.Items[i] = NewInviteCodeVO(invite)
+	}
+	return vo
+}
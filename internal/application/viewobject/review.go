@@ -1,6 +1,9 @@
 package viewobject
 
-import "jcourse_go/internal/domain/review"
+import (
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/review"
+)
 
 type CourseInReviewVO struct {
 	ID          int               `json:"id"`
@@ -10,26 +13,39 @@ type CourseInReviewVO struct {
 }
 
 func NewCourseInReviewVO(c *review.Course) CourseInReviewVO {
-	return CourseInReviewVO{
+	vo := CourseInReviewVO{
 		ID:          c.ID,
 		Code:        c.Code,
 		Name:        c.Name,
-		MainTeacher: NewTeacherVO(c.MainTeacher),
+		MainTeacher: TeacherListItemVO{ID: c.MainTeacherID},
 	}
+	if c.MainTeacher != nil {
+		vo.MainTeacher = NewTeacherVO(c.MainTeacher)
+	}
+	return vo
 }
 
 type ReviewVO struct {
 	Course    *CourseInReviewVO
+	User      UserInReviewVO
 	ID        int
 	CourseID  int
 	Semester  string
 	Grade     string
 	Comment   string
 	Rating    int
+	LikeCount int
 	CreatedAt int64
 	UpdatedAt int64
 }
 
+// ReviewListVO is a page of reviews; NextCursor is empty on the last page
+type ReviewListVO struct {
+	Total      int64      `json:"total"`
+	Items      []ReviewVO `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
 type UserInReviewVO struct {
 	ID   int    `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
@@ -40,24 +56,61 @@ var AnonymousUserVO = UserInReviewVO{
 	Name: "匿名用户",
 }
 
+// NewUserInReviewVO names the author for admins and the author's own export;
+// the reviews of deleted accounts are shown as written by AnonymousUserVO
+func NewUserInReviewVO(u *auth.User) UserInReviewVO {
+	if u == nil || u.DeletedAt != nil {
+		return AnonymousUserVO
+	}
+	return UserInReviewVO{
+		ID:   u.ID,
+		Name: u.Username,
+	}
+}
+
+// NewReviewVO is the public view of a review, which never names its author:
+// usernames default to the email address
 func NewReviewVO(r *review.Review, withCourse bool) ReviewVO {
 	rvo := ReviewVO{
 		ID:        r.ID,
 		CourseID:  r.CourseID,
+		User:      AnonymousUserVO,
 		Semester:  r.Semester.String(),
 		Grade:     r.Grade,
 		Comment:   r.Comment,
 		Rating:    r.Rating.Int(),
+		LikeCount: r.LikeCount,
 		CreatedAt: r.CreatedAt.Unix(),
 		UpdatedAt: r.UpdatedAt.Unix(),
 	}
-	if withCourse {
+	if withCourse && r.Course != nil {
 		course := NewCourseInReviewVO(r.Course)
 		rvo.Course = &course
 	}
 	return rvo
 }
 
+func NewReviewListVO(reviews []review.Review, total int64, nextCursor string, withCourse bool) ReviewListVO {
+	vo := ReviewListVO{
+		Total:      total,
+		Items:      make([]ReviewVO, len(reviews)),
+		NextCursor: nextCursor,
+	}
+	for i, r := range reviews {
+		vo.Items[i] = NewReviewVO(&r, withCourse)
+	}
+	return vo
+}
+
+// NewAdminReviewListVO is NewReviewListVO naming the authors, for admin views only
+func NewAdminReviewListVO(reviews []review.Review, total int64, nextCursor string, withCourse bool) ReviewListVO {
+	vo := NewReviewListVO(reviews, total, nextCursor, withCourse)
+	for i, r := range reviews {
+		vo.Items[i].User = NewUserInReviewVO(r.User)
+	}
+	return vo
+}
+
 type ReviewRevisionVO struct {
 	ID        int    `json:"id"`
 	ReviewID  int    `json:"review_id"`
@@ -0,0 +1,32 @@
+package viewobject
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/auth"
+)
+
+type SessionVO struct {
+	ID         int       `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current marks the session the request was made with
+	Current bool `json:"current"`
+}
+
+func NewSessionVO(session auth.Session, current bool) SessionVO {
+	return SessionVO{
+		ID:         session.ID,
+		Device:     session.Metadata.Device(),
+		UserAgent:  session.Metadata.UserAgent,
+		IP:         session.Metadata.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		Current:    current,
+	}
+}
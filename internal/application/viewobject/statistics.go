@@ -13,6 +13,7 @@ type DailyStatisticsVO struct {
 	TotalReviews       int    `json:"total_reviews"`
 	TotalCourses       int    `json:"total_courses"`
 	CoursesWithReviews int    `json:"courses_with_reviews"`
+	DailyLikes         int    `json:"daily_likes"`
 }
 
 func NewDailyStatisticsVO(stats *statistics.DailyStatistics) DailyStatisticsVO {
@@ -25,6 +26,7 @@ func NewDailyStatisticsVO(stats *statistics.DailyStatistics) DailyStatisticsVO {
 		TotalReviews:       stats.TotalReviews,
 		TotalCourses:       stats.TotalCourses,
 		CoursesWithReviews: stats.CoursesWithReviews,
+		DailyLikes:         stats.DailyLikes,
 	}
 }
 
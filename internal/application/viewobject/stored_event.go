@@ -0,0 +1,58 @@
+package viewobject
+
+import (
+	"encoding/json"
+
+	"jcourse_go/internal/domain/event"
+)
+
+type AggregateRefVO struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+}
+
+type StoredEventVO struct {
+	ID         int64            `json:"id"`
+	EventID    string           `json:"event_id"`
+	EventType  string           `json:"event_type"`
+	Version    int              `json:"version"`
+	Aggregates []AggregateRefVO `json:"aggregates"`
+	Payload    json.RawMessage  `json:"payload"`
+	OccurredAt int64            `json:"occurred_at"`
+}
+
+type StoredEventListVO struct {
+	Total int64           `json:"total"`
+	Items []StoredEventVO `json:"items"`
+}
+
+func NewStoredEventVO(e *event.StoredEvent) StoredEventVO {
+	aggregates := make([]AggregateRefVO, len(e.Aggregates))
+	for i, aggregate := range e.Aggregates {
+		aggregates[i] = AggregateRefVO{
+			Type: string(aggregate.Type),
+			ID:   aggregate.ID,
+		}
+	}
+
+	return StoredEventVO{
+		ID:         e.ID,
+		EventID:    e.EventID,
+		EventType:  string(e.EventType),
+		Version:    e.Version,
+		Aggregates: aggregates,
+		Payload:    json.RawMessage(e.Payload),
+		OccurredAt: e.OccurredAt.Unix(),
+	}
+}
+
+func NewStoredEventListVO(storedEvents []event.StoredEvent, total int64) StoredEventListVO {
+	vo := StoredEventListVO{
+		Total: total,
+		Items: make([]StoredEventVO, len(storedEvents)),
+	}
+	for i, e := range storedEvents {
+		vo.Items[i] = NewStoredEventVO(&e)
+	}
+	return vo
+}
@@ -0,0 +1,8 @@
+package viewobject
+
+// TwoFactorEnrollmentVO is shown once while enrolling an authenticator app
+type TwoFactorEnrollmentVO struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth:// URI to render as a QR code
+	ProvisioningURI string `json:"provisioning_uri"`
+}
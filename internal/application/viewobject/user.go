@@ -3,6 +3,7 @@ package viewobject
 import (
 	"time"
 
+	"jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/domain/common"
 )
 
@@ -12,6 +13,58 @@ type UserInfoVO struct {
 	Email       string      `json:"email"`
 	Role        common.Role `json:"role"`
 	IsSuspended bool        `json:"is_suspended"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	// DeletionScheduledAt is when the account will be anonymised, unless the deletion is cancelled
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	LastSeenAt          time.Time  `json:"last_seen_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+func NewUserInfoVO(user *auth.User) UserInfoVO {
+	return UserInfoVO{
+		UserID:              user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		Role:                user.Role,
+		IsSuspended:         user.IsSuspended(),
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		LastSeenAt:          user.LastSeenAt,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+	}
+}
+
+// AdminUserVO is a user as shown to admins, with the details of a suspension
+type AdminUserVO struct {
+	UserInfoVO
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+}
+
+type AdminUserListVO struct {
+	Total int64         `json:"total"`
+	Items []AdminUserVO `json:"items"`
+}
+
+func NewAdminUserVO(user *auth.User) AdminUserVO {
+	vo := AdminUserVO{UserInfoVO: NewUserInfoVO(user)}
+	// An expired suspension is shown as over, even before it is lifted
+	if user.IsSuspended() {
+		vo.SuspendedAt = user.SuspendedAt
+		vo.SuspendedUntil = user.SuspendedUntil
+		vo.SuspensionReason = user.SuspensionReason
+	}
+	return vo
+}
+
+func NewAdminUserListVO(users []auth.User, total int64) AdminUserListVO {
+	vo := AdminUserListVO{
+		Total: total,
+		Items: make([]AdminUserVO, len(users)),
+	}
+	for i, user := range users {
+		vo.Items[i] = NewAdminUserVO(&user)
+	}
+	return vo
 }
@@ -0,0 +1,72 @@
+package viewobject
+
+import "jcourse_go/internal/domain/webhook"
+
+// WebhookSubscriptionVO never exposes the signing secret
+type WebhookSubscriptionVO struct {
+	ID        int64  `json:"id"`
+	EventType string `json:"event_type"`
+	URL       string `json:"url"`
+	Enabled   bool   `json:"enabled"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+type WebhookDeliveryVO struct {
+	ID            int64  `json:"id"`
+	EventID       string `json:"event_id"`
+	EventType     string `json:"event_type"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	ResponseCode  int    `json:"response_code"`
+	LastError     string `json:"last_error"`
+	NextAttemptAt int64  `json:"next_attempt_at"`
+	DeliveredAt   *int64 `json:"delivered_at"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+type WebhookDeliveryListVO struct {
+	Total int64               `json:"total"`
+	Items []WebhookDeliveryVO `json:"items"`
+}
+
+func NewWebhookSubscriptionVO(s *webhook.Subscription) WebhookSubscriptionVO {
+	return WebhookSubscriptionVO{
+		ID:        s.ID,
+		EventType: string(s.EventType),
+		URL:       s.URL,
+		Enabled:   s.Enabled,
+		CreatedAt: s.CreatedAt.Unix(),
+		UpdatedAt: s.UpdatedAt.Unix(),
+	}
+}
+
+func NewWebhookDeliveryVO(d *webhook.Delivery) WebhookDeliveryVO {
+	vo := WebhookDeliveryVO{
+		ID:            d.ID,
+		EventID:       d.EventID,
+		EventType:     string(d.EventType),
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt.Unix(),
+		CreatedAt:     d.CreatedAt.Unix(),
+	}
+	if d.DeliveredAt != nil {
+		deliveredAt := d.DeliveredAt.Unix()
+		vo.DeliveredAt = &deliveredAt
+	}
+	return vo
+}
+
+func NewWebhookDeliveryListVO(deliveries []webhook.Delivery, total int64) WebhookDeliveryListVO {
+	vo := WebhookDeliveryListVO{
+		Total: total,
+		Items: make([]WebhookDeliveryVO, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		vo.Items[i] = NewWebhookDeliveryVO(&d)
+	}
+	return vo
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/webhook"
+	"jcourse_go/pkg/apperror"
+)
+
+type WebhookCommandService interface {
+	CreateSubscription(commonCtx *common.CommonContext, cmd *webhook.CreateSubscriptionCommand) (int64, error)
+	UpdateSubscription(commonCtx *common.CommonContext, cmd *webhook.UpdateSubscriptionCommand) error
+	DeleteSubscription(commonCtx *common.CommonContext, id int64) error
+}
+
+type webhookCommandService struct {
+	subscriptionRepo webhook.SubscriptionRepository
+}
+
+func NewWebhookCommandService(subscriptionRepo webhook.SubscriptionRepository) WebhookCommandService {
+	return &webhookCommandService{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+func (s *webhookCommandService) CreateSubscription(commonCtx *common.CommonContext, cmd *webhook.CreateSubscriptionCommand) (int64, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return 0, apperror.ErrPermission.WithMessage("only admins can manage webhooks")
+	}
+	if !event.IsRegistered(event.Type(cmd.EventType)) {
+		return 0, apperror.ErrValidation.WithMessage("unknown event type").
+			WithMetadata("event_type", cmd.EventType)
+	}
+	if err := validateURL(cmd.URL); err != nil {
+		return 0, err
+	}
+
+	subscription := webhook.NewSubscription(cmd)
+	if err := s.subscriptionRepo.Save(commonCtx.Ctx, &subscription); err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "create_webhook_subscription")
+	}
+	return subscription.ID, nil
+}
+
+func (s *webhookCommandService) UpdateSubscription(commonCtx *common.CommonContext, cmd *webhook.UpdateSubscriptionCommand) error {
+	subscription, err := s.getSubscription(commonCtx, cmd.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if cmd.URL != nil {
+		if err := validateURL(*cmd.URL); err != nil {
+			return err
+		}
+		subscription.URL = *cmd.URL
+	}
+	if cmd.Secret != nil && *cmd.Secret != "" {
+		subscription.Secret = *cmd.Secret
+	}
+	if cmd.Enabled != nil {
+		subscription.Enabled = *cmd.Enabled
+	}
+	subscription.UpdatedAt = time.Now()
+
+	if err := s.subscriptionRepo.Save(commonCtx.Ctx, subscription); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "update_webhook_subscription").
+			WithMetadata("subscription_id", cmd.SubscriptionID)
+	}
+	return nil
+}
+
+func (s *webhookCommandService) DeleteSubscription(commonCtx *common.CommonContext, id int64) error {
+	if _, err := s.getSubscription(commonCtx, id); err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepo.Delete(commonCtx.Ctx, id); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "delete_webhook_subscription").
+			WithMetadata("subscription_id", id)
+	}
+	return nil
+}
+
+func (s *webhookCommandService) getSubscription(commonCtx *common.CommonContext, id int64) (*webhook.Subscription, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can manage webhooks").
+			WithMetadata("subscription_id", id)
+	}
+
+	subscription, err := s.subscriptionRepo.Get(commonCtx.Ctx, id)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "get_webhook_subscription").
+			WithMetadata("subscription_id", id)
+	}
+	if subscription == nil {
+		return nil, apperror.ErrNotFound.WithMessage("webhook subscription not found").
+			WithMetadata("subscription_id", id)
+	}
+	return subscription, nil
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperror.ErrValidation.WithMessage("webhook url must be an absolute http(s) url").
+			WithMetadata("url", rawURL)
+	}
+	// Host names are checked again once resolved, when deliveries are sent
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	addr, err := netip.ParseAddr(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || (err == nil && webhook.IsForbiddenAddress(addr)) {
+		return apperror.ErrValidation.WithMessage("webhook url must not point to a local or private address").
+			WithMetadata("url", rawURL)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"jcourse_go/internal/application/viewobject"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/webhook"
+	"jcourse_go/pkg/apperror"
+)
+
+type WebhookQueryService interface {
+	ListSubscriptions(commonCtx *common.CommonContext) ([]viewobject.WebhookSubscriptionVO, error)
+	ListDeliveries(commonCtx *common.CommonContext, subscriptionID int64, pagination common.Pagination) (*viewobject.WebhookDeliveryListVO, error)
+}
+
+type webhookQueryService struct {
+	subscriptionRepo webhook.SubscriptionRepository
+	deliveryRepo     webhook.DeliveryRepository
+}
+
+func NewWebhookQueryService(subscriptionRepo webhook.SubscriptionRepository, deliveryRepo webhook.DeliveryRepository) WebhookQueryService {
+	return &webhookQueryService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+	}
+}
+
+func (s *webhookQueryService) ListSubscriptions(commonCtx *common.CommonContext) ([]viewobject.WebhookSubscriptionVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can view webhooks")
+	}
+
+	subscriptions, err := s.subscriptionRepo.FindAll(commonCtx.Ctx)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_webhook_subscriptions")
+	}
+
+	subscriptionList := make([]viewobject.WebhookSubscriptionVO, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionList[i] = viewobject.NewWebhookSubscriptionVO(&subscription)
+	}
+	return subscriptionList, nil
+}
+
+func (s *webhookQueryService) ListDeliveries(commonCtx *common.CommonContext, subscriptionID int64, pagination common.Pagination) (*viewobject.WebhookDeliveryListVO, error) {
+	if commonCtx.User.Role != common.RoleAdmin {
+		return nil, apperror.ErrPermission.WithMessage("only admins can view webhooks")
+	}
+
+	deliveries, total, err := s.deliveryRepo.FindBySubscription(commonCtx.Ctx, subscriptionID, pagination)
+	if err != nil {
+		return nil, apperror.WrapDB(err).WithMetadata("operation", "list_webhook_deliveries").
+			WithMetadata("subscription_id", subscriptionID)
+	}
+
+	vo := viewobject.NewWebhookDeliveryListVO(deliveries, total)
+	return &vo, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/webhook"
+	"jcourse_go/pkg/apperror"
+)
+
+const (
+	DefaultDeliveryBatchSize = 20
+	MaxDeliveryAttempts      = 8
+	InitialRetryBackoff      = 30 * time.Second
+	MaxRetryBackoff          = 6 * time.Hour
+	// DeliveryLease is how long a claimed delivery is hidden from other workers; it outlasts a batch of sends,
+	// and a delivery whose worker died is retried once it runs out
+	DeliveryLease = 5 * time.Minute
+)
+
+type WebhookDeliveryService interface {
+	// Enqueue records a pending delivery of the event for every enabled subscription of its type
+	Enqueue(ctx context.Context, e event.Event) error
+	// DeliverDue attempts one batch of due deliveries and returns how many were attempted
+	DeliverDue(ctx context.Context) (int, error)
+	// PurgeFinished removes succeeded and failed deliveries last updated before the given time
+	PurgeFinished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type webhookDeliveryService struct {
+	subscriptionRepo webhook.SubscriptionRepository
+	deliveryRepo     webhook.DeliveryRepository
+	sender           webhook.Sender
+	batchSize        int
+}
+
+func NewWebhookDeliveryService(
+	subscriptionRepo webhook.SubscriptionRepository,
+	deliveryRepo webhook.DeliveryRepository,
+	sender webhook.Sender,
+) WebhookDeliveryService {
+	return &webhookDeliveryService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		sender:           sender,
+		batchSize:        DefaultDeliveryBatchSize,
+	}
+}
+
+func (s *webhookDeliveryService) Enqueue(ctx context.Context, e event.Event) error {
+	subscriptions, err := s.subscriptionRepo.FindEnabledByEventType(ctx, e.Type())
+	if err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "find_webhook_subscriptions").
+			WithMetadata("event_type", e.Type())
+	}
+
+	deliveries := make([]webhook.Delivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		delivery, err := webhook.NewDelivery(subscription.ID, e)
+		if err != nil {
+			return apperror.WrapInternal(err).WithMetadata("event_id", e.ID())
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := s.deliveryRepo.Save(ctx, deliveries...); err != nil {
+		return apperror.WrapDB(err).WithMetadata("operation", "enqueue_webhook_deliveries").
+			WithMetadata("event_id", e.ID())
+	}
+	return nil
+}
+
+func (s *webhookDeliveryService) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, now, now.Add(DeliveryLease), s.batchSize)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "claim_webhook_deliveries")
+	}
+
+	// Each outcome is recorded on its own; a delivery whose outcome is lost is retried when its lease runs out
+	for _, delivery := range deliveries {
+		if err := s.deliver(ctx, &delivery); err != nil {
+			log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, err)
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (s *webhookDeliveryService) deliver(ctx context.Context, delivery *webhook.Delivery) error {
+	subscription, err := s.subscriptionRepo.Get(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	delivery.UpdatedAt = now
+
+	if subscription == nil || !subscription.Enabled {
+		delivery.Status = webhook.DeliveryStatusFailed
+		delivery.LastError = "subscription was removed or disabled"
+		return s.deliveryRepo.Update(ctx, delivery)
+	}
+
+	code, sendErr := s.sender.Send(ctx, webhook.Request{
+		DeliveryID: delivery.ID,
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventType:  string(delivery.EventType),
+		Body:       delivery.Body,
+	})
+
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	switch {
+	case sendErr == nil && webhook.IsSuccessCode(code):
+		delivery.Status = webhook.DeliveryStatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	default:
+		if sendErr != nil {
+			delivery.LastError = sendErr.Error()
+		} else {
+			delivery.LastError = fmt.Sprintf("unexpected response status %d", code)
+		}
+		if delivery.Attempts >= MaxDeliveryAttempts {
+			delivery.Status = webhook.DeliveryStatusFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(retryBackoff(delivery.Attempts))
+		}
+		log.Printf("Webhook delivery %d to subscription %d failed (attempt %d/%d): %s",
+			delivery.ID, delivery.SubscriptionID, delivery.Attempts, MaxDeliveryAttempts, delivery.LastError)
+	}
+
+	return s.deliveryRepo.Update(ctx, delivery)
+}
+
+// retryBackoff doubles the wait after every failed attempt, up to MaxRetryBackoff
+func retryBackoff(attempts int) time.Duration {
+	backoff := InitialRetryBackoff
+	for i := 1; i < attempts && backoff < MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxRetryBackoff {
+		return MaxRetryBackoff
+	}
+	return backoff
+}
+
+func (s *webhookDeliveryService) PurgeFinished(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.deliveryRepo.DeleteFinishedBefore(ctx, before)
+	if err != nil {
+		return 0, apperror.WrapDB(err).WithMetadata("operation", "purge_webhook_deliveries")
+	}
+	return deleted, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/webhook"
+)
+
+func newTestService(sender *MockSender) (WebhookDeliveryService, *MockDeliveryRepository) {
+	subscriptions := &MockSubscriptionRepository{Subscriptions: []webhook.Subscription{
+		{ID: 1, EventType: event.TypeReviewCreated, URL: "http://example.com/hook", Secret: "s3cret", Enabled: true},
+		{ID: 2, EventType: event.TypeReviewCreated, URL: "http://example.com/off", Secret: "s3cret", Enabled: false},
+		{ID: 3, EventType: event.TypeReviewModified, URL: "http://example.com/modified", Secret: "s3cret", Enabled: true},
+	}}
+	deliveries := &MockDeliveryRepository{}
+	return NewWebhookDeliveryService(subscriptions, deliveries, sender), deliveries
+}
+
+func enqueueReviewCreated(t *testing.T, service WebhookDeliveryService) event.Event {
+	e := event.NewBaseEvent(event.TypeReviewCreated, &event.ReviewPayload{ReviewID: 7, UserID: 3, Action: "created"})
+	assert.NoError(t, service.Enqueue(context.Background(), e))
+	return e
+}
+
+func TestWebhookDeliveryService_EnqueueMatchesEnabledSubscriptions(t *testing.T) {
+	service, deliveries := newTestService(&MockSender{})
+
+	e := enqueueReviewCreated(t, service)
+
+	assert.Len(t, deliveries.Deliveries, 1)
+	assert.Equal(t, int64(1), deliveries.Deliveries[0].SubscriptionID)
+	assert.Equal(t, e.ID(), deliveries.Deliveries[0].EventID)
+	assert.Equal(t, webhook.DeliveryStatusPending, deliveries.Deliveries[0].Status)
+}
+
+func TestWebhookDeliveryService_DeliverSuccess(t *testing.T) {
+	sender := &MockSender{Code: http.StatusOK}
+	service, deliveries := newTestService(sender)
+	enqueueReviewCreated(t, service)
+
+	attempted, err := service.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	assert.Len(t, sender.Requests, 1)
+	assert.Equal(t, "http://example.com/hook", sender.Requests[0].URL)
+	assert.Equal(t, "s3cret", sender.Requests[0].Secret)
+	assert.Equal(t, deliveries.Deliveries[0].Body, sender.Requests[0].Body)
+
+	delivery := deliveries.Deliveries[0]
+	assert.Equal(t, webhook.DeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, http.StatusOK, delivery.ResponseCode)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestWebhookDeliveryService_DeliverFailureSchedulesRetry(t *testing.T) {
+	sender := &MockSender{Code: http.StatusInternalServerError}
+	service, deliveries := newTestService(sender)
+	enqueueReviewCreated(t, service)
+
+	_, err := service.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	delivery := deliveries.Deliveries[0]
+	assert.Equal(t, webhook.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, http.StatusInternalServerError, delivery.ResponseCode)
+	assert.Contains(t, delivery.LastError, "500")
+	assert.WithinDuration(t, time.Now().Add(InitialRetryBackoff), delivery.NextAttemptAt, time.Second)
+
+	// Not due again until the backoff has passed
+	attempted, err := service.DeliverDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, attempted)
+}
+
+func TestWebhookDeliveryService_GivesUpAfterMaxAttempts(t *testing.T) {
+	sender := &MockSender{Err: errors.New("connection refused")}
+	service, deliveries := newTestService(sender)
+	enqueueReviewCreated(t, service)
+	deliveries.Deliveries[0].Attempts = MaxDeliveryAttempts - 1
+
+	_, err := service.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	delivery := deliveries.Deliveries[0]
+	assert.Equal(t, webhook.DeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, MaxDeliveryAttempts, delivery.Attempts)
+	assert.Equal(t, "connection refused", delivery.LastError)
+}
+
+func TestWebhookDeliveryService_DisabledSubscriptionFails(t *testing.T) {
+	sender := &MockSender{Code: http.StatusOK}
+	service, deliveries := newTestService(sender)
+	e := event.NewBaseEvent(event.TypeReviewCreated, &event.ReviewPayload{ReviewID: 7})
+	delivery, err := webhook.NewDelivery(2, e)
+	assert.NoError(t, err)
+	assert.NoError(t, deliveries.Save(context.Background(), delivery))
+
+	_, err = service.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, sender.Requests)
+	assert.Equal(t, webhook.DeliveryStatusFailed, deliveries.Deliveries[0].Status)
+}
+
+func TestWebhookDeliveryService_ClaimedDeliveryIsNotResent(t *testing.T) {
+	sender := &MockSender{Code: http.StatusOK}
+	service, deliveries := newTestService(sender)
+	enqueueReviewCreated(t, service)
+	deliveries.UpdateErr = errors.New("connection lost")
+
+	// The send is not rolled back by the failed update, and the lease keeps it from going out again at once
+	attempted, err := service.DeliverDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	assert.Len(t, sender.Requests, 1)
+	assert.WithinDuration(t, time.Now().Add(DeliveryLease), deliveries.Deliveries[0].NextAttemptAt, time.Second)
+
+	attempted, err = service.DeliverDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, attempted)
+	assert.Len(t, sender.Requests, 1)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, InitialRetryBackoff, retryBackoff(1))
+	assert.Equal(t, 2*InitialRetryBackoff, retryBackoff(2))
+	assert.Equal(t, 8*InitialRetryBackoff, retryBackoff(4))
+	assert.Equal(t, MaxRetryBackoff, retryBackoff(100))
+}
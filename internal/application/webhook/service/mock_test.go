@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/webhook"
+)
+
+// MockSubscriptionRepository is an in-memory implementation of webhook.SubscriptionRepository for testing
+type MockSubscriptionRepository struct {
+	Subscriptions []webhook.Subscription
+}
+
+func (m *MockSubscriptionRepository) Get(ctx context.Context, id int64) (*webhook.Subscription, error) {
+	for _, s := range m.Subscriptions {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockSubscriptionRepository) FindAll(ctx context.Context) ([]webhook.Subscription, error) {
+	return m.Subscriptions, nil
+}
+
+func (m *MockSubscriptionRepository) FindEnabledByEventType(ctx context.Context, eventType event.Type) ([]webhook.Subscription, error) {
+	var subscriptions []webhook.Subscription
+	for _, s := range m.Subscriptions {
+		if s.Enabled && s.EventType == eventType {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (m *MockSubscriptionRepository) Save(ctx context.Context, subscription *webhook.Subscription) error {
+	m.Subscriptions = append(m.Subscriptions, *subscription)
+	return nil
+}
+
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, id int64) error {
+	return nil
+}
+
+// MockDeliveryRepository is an in-memory implementation of webhook.DeliveryRepository for testing
+type MockDeliveryRepository struct {
+	Deliveries []webhook.Delivery
+	UpdateErr  error
+}
+
+func (m *MockDeliveryRepository) Save(ctx context.Context, deliveries ...webhook.Delivery) error {
+	for _, d := range deliveries {
+		d.ID = int64(len(m.Deliveries) + 1)
+		m.Deliveries = append(m.Deliveries, d)
+	}
+	return nil
+}
+
+func (m *MockDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]webhook.Delivery, error) {
+	var due []webhook.Delivery
+	for i, d := range m.Deliveries {
+		if d.Status == webhook.DeliveryStatusPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			m.Deliveries[i].NextAttemptAt = leaseUntil
+			due = append(due, m.Deliveries[i])
+		}
+	}
+	return due, nil
+}
+
+func (m *MockDeliveryRepository) Update(ctx context.Context, delivery *webhook.Delivery) error {
+	if m.UpdateErr != nil {
+		return m.UpdateErr
+	}
+	for i := range m.Deliveries {
+		if m.Deliveries[i].ID == delivery.ID {
+			m.Deliveries[i] = *delivery
+		}
+	}
+	return nil
+}
+
+func (m *MockDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID int64, pagination common.Pagination) ([]webhook.Delivery, int64, error) {
+	return m.Deliveries, int64(len(m.Deliveries)), nil
+}
+
+func (m *MockDeliveryRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// MockSender answers every request with the configured status code and error
+type MockSender struct {
+	Code     int
+	Err      error
+	Requests []webhook.Request
+}
+
+func (m *MockSender) Send(ctx context.Context, req webhook.Request) (int, error) {
+	m.Requests = append(m.Requests, req)
+	return m.Code, m.Err
+}
@@ -1,15 +1,109 @@
 package config
 
+import "time"
+
 type Config struct {
-	DB    DBConfig    `yaml:"db"`
-	SMTP  SMTPConfig  `yaml:"smtp"`
-	Event EventConfig `yaml:"event"`
+	// SiteURL is the public address of the frontend, used for links in emails, e.g. https://course.sjtu.plus
+	SiteURL  string         `yaml:"site_url"`
+	DB       DBConfig       `yaml:"db"`
+	SMTP     SMTPConfig     `yaml:"smtp"`
+	Event    EventConfig    `yaml:"event"`
+	Password PasswordConfig `yaml:"password"`
+	OAuth    OAuthConfig    `yaml:"oauth"`
+	// Registration restricts who can sign up
+	Registration RegistrationConfig `yaml:"registration"`
+	Auth         AuthConfig         `yaml:"auth"`
+	// AllowedOrigins may call the API with credentials, such as the session cookie, and default to the origin
+	// of SiteURL. Other origins may only make requests without credentials.
+	AllowedOrigins []string `yaml:"allowed_origins"`
 }
 
 type DBConfig struct {
 	DSN string `yaml:"dsn"`
 }
 
+// PasswordConfig sets the argon2id cost; zero values use the recommended defaults
+type PasswordConfig struct {
+	// Memory is the amount of memory used per hash in KiB
+	Memory      uint32 `yaml:"memory"`
+	Iterations  uint32 `yaml:"iterations"`
+	Parallelism uint8  `yaml:"parallelism"`
+}
+
+// RegistrationConfig restricts sign-ups to email domains, or to holders of an invite code
+type RegistrationConfig struct {
+	// AllowedDomains, e.g. sjtu.edu.cn, also admit their subdomains; empty admits every domain
+	AllowedDomains []string `yaml:"allowed_domains"`
+	// BlockedDomains extend the built-in list of disposable email providers
+	BlockedDomains []string `yaml:"blocked_domains"`
+	InviteOnly     bool     `yaml:"invite_only"`
+}
+
+const (
+	AuthModeSession = "session"
+	AuthModeToken   = "token"
+)
+
+// AuthConfig selects what clients get when they log in
+type AuthConfig struct {
+	// Mode is "session" (default), an opaque session ID checked against the database on every request,
+	// or "token", a short-lived signed access token renewed with rotating refresh tokens
+	Mode string `yaml:"mode"`
+	// SigningKeys sign the access tokens in token mode. The first key signs, the others are still accepted:
+	// to rotate, put a new key first and drop the old one once its tokens have expired.
+	SigningKeys []SigningKeyConfig `yaml:"signing_keys"`
+	// Cookie also hands the session to browsers in an HttpOnly cookie, in session mode
+	Cookie SessionCookieConfig `yaml:"cookie"`
+}
+
+// SessionCookieConfig describes the session cookie. Requests authenticated with it that change state
+// must echo the value of the readable CSRF cookie in the X-CSRF-Token header.
+type SessionCookieConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Name defaults to jcourse_session; the CSRF cookie is named after it with a _csrf suffix
+	Name   string `yaml:"name"`
+	Domain string `yaml:"domain"`
+	// Path defaults to /
+	Path string `yaml:"path"`
+	// SameSite is lax (default), strict or none
+	SameSite string `yaml:"same_site"`
+	// Insecure drops the Secure attribute, for local development over plain http only
+	Insecure bool `yaml:"insecure"`
+}
+
+type SigningKeyConfig struct {
+	// ID is sent as the kid header of the tokens signed with the key
+	ID string `yaml:"id"`
+	// Algorithm is HS256 or EdDSA
+	Algorithm string `yaml:"algorithm"`
+	// Secret is the HMAC secret of at least 32 bytes for HS256, or the base64 encoded Ed25519 seed for EdDSA
+	Secret string `yaml:"secret"`
+}
+
+// OAuthConfig lists the external identity providers users can sign in with
+type OAuthConfig struct {
+	Providers []OIDCProviderConfig `yaml:"providers"`
+}
+
+// OIDCProviderConfig describes an OAuth2/OIDC authorization server. Providers with a known
+// name, such as "jaccount", fall back to their public endpoints when these are left empty.
+type OIDCProviderConfig struct {
+	Name         string `yaml:"name"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// RedirectURL is our callback, e.g. https://course.sjtu.plus/api/v1/auth/oauth/jaccount/callback
+	RedirectURL string `yaml:"redirect_url"`
+	// Issuer is compared with the iss claim of ID tokens when set
+	Issuer   string   `yaml:"issuer"`
+	AuthURL  string   `yaml:"auth_url"`
+	TokenURL string   `yaml:"token_url"`
+	JWKSURL  string   `yaml:"jwks_url"`
+	Scopes   []string `yaml:"scopes"`
+	// EmailDomain turns the subject into a verified email, subject@domain, when the ID token has no email;
+	// for providers whose accounts are the mailboxes of a domain
+	EmailDomain string `yaml:"email_domain"`
+}
+
 type SMTPConfig struct {
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
@@ -18,6 +112,26 @@ type SMTPConfig struct {
 	Sender   string `yaml:"sender"`
 }
 
+const (
+	EventDriverMemory   = "memory"
+	EventDriverPostgres = "postgres"
+)
+
+// EventConfig configures the event bus. Zero values use the defaults; a negative max_retries disables retries.
 type EventConfig struct {
 	Enabled bool `yaml:"enabled"`
+	// Driver selects the event bus: "memory" (default) or "postgres" for multi-replica deployments
+	Driver       string              `yaml:"driver"`
+	Workers      int                 `yaml:"workers"`
+	QueueSize    int                 `yaml:"queue_size"`
+	MaxRetries   int                 `yaml:"max_retries"`
+	RetryBackoff time.Duration       `yaml:"retry_backoff"`
+	Postgres     PostgresEventConfig `yaml:"postgres"`
+}
+
+type PostgresEventConfig struct {
+	Channel string `yaml:"channel"`
+	// ConsumerGroup makes exactly one replica of the group handle each event; empty broadcasts to every replica
+	ConsumerGroup string        `yaml:"consumer_group"`
+	Retention     time.Duration `yaml:"retention"`
 }
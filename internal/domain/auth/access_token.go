@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/common"
+)
+
+// AccessTokenTTL bounds how long a signed access token is accepted. Access tokens are checked without
+// a lookup, so ending the session, suspending the user or changing their role only takes effect once they lapse.
+const AccessTokenTTL = 15 * time.Minute
+
+// AccessClaims are carried by a signed access token
+type AccessClaims struct {
+	UserID int
+	Role   common.Role
+	// SessionID is the session the token was issued for
+	SessionID        int
+	TwoFactorPending bool
+	IssuedAt         time.Time
+	ExpiresAt        time.Time
+}
+
+// NewAccessClaims describes the authenticated user for an access token valid for AccessTokenTTL
+func NewAccessClaims(user *common.User, sessionID int, now time.Time) AccessClaims {
+	return AccessClaims{
+		UserID:           user.UserID,
+		Role:             user.Role,
+		SessionID:        sessionID,
+		TwoFactorPending: user.TwoFactorPending,
+		IssuedAt:         now,
+		ExpiresAt:        now.Add(AccessTokenTTL),
+	}
+}
+
+// User returns the request user the claims stand for
+func (c *AccessClaims) User() *common.User {
+	return &common.User{
+		UserID:           c.UserID,
+		Role:             c.Role,
+		SessionID:        c.SessionID,
+		TwoFactorPending: c.TwoFactorPending,
+	}
+}
+
+// AccessTokenSigner signs access tokens and verifies their signature and expiry
+type AccessTokenSigner interface {
+	Sign(claims AccessClaims) (string, error)
+	Verify(token string, now time.Time) (*AccessClaims, error)
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+)
+
+const (
+	// APITokenPrefix tells personal API tokens apart from other bearer tokens and makes leaked ones easy to spot
+	APITokenPrefix = "jct_"
+
+	DefaultAPITokenLifetime = 90 * 24 * time.Hour
+	MaxAPITokenLifetime     = 365 * 24 * time.Hour
+	MaxAPITokensPerUser     = 20
+)
+
+// APIToken is a named personal access token with a limited set of scopes, for scripts and bots
+type APIToken struct {
+	ID         int
+	UserID     int
+	Name       string
+	TokenHash  string
+	Scopes     []common.Scope
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAPIToken returns the token and its secret, which is shown to the user once; only the hash is stored
+func NewAPIToken(userID int, name string, scopes []common.Scope, lifetime time.Duration, now time.Time) (APIToken, string) {
+	secret := APITokenPrefix + NewSessionToken()
+	return APIToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: HashSessionToken(secret),
+		Scopes:    scopes,
+		ExpiresAt: now.Add(lifetime),
+		CreatedAt: now,
+	}, secret
+}
+
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// NeedsTouch reports whether the last use is stale enough to be written back, see SessionTouchInterval
+func (t *APIToken) NeedsTouch(now time.Time) bool {
+	return t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= SessionTouchInterval
+}
+
+// IsAPIToken reports whether the bearer token is a personal API token
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *APIToken) error
+	// GetByToken returns the token with the secret, or nil when there is none; expired tokens are returned too
+	GetByToken(ctx context.Context, secret string) (*APIToken, error)
+	ListByUser(ctx context.Context, userID int) ([]APIToken, error)
+	CountByUser(ctx context.Context, userID int) (int64, error)
+	// Delete removes the token of the user and reports whether it existed
+	Delete(ctx context.Context, userID int, id int) (bool, error)
+	// DeleteByUser removes every token of the user and returns how many were removed
+	DeleteByUser(ctx context.Context, userID int) (int64, error)
+	TouchLastUsed(ctx context.Context, id int, at time.Time) error
+}
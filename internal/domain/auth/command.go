@@ -1,14 +1,24 @@
 package auth
 
+import "jcourse_go/internal/domain/common"
+
 type LoginCommand struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+
+	// Session describes the client, it is filled in by the controller
+	Session SessionMetadata `json:"-"`
 }
 
 type RegisterCommand struct {
 	Email    string `json:"email"`
 	Code     string `json:"code"`
 	Password string `json:"password"`
+	// InviteCode is required in invite-only mode and for emails outside the allowed domains
+	InviteCode string `json:"invite_code"`
+
+	// Session describes the client, it is filled in by the controller
+	Session SessionMetadata `json:"-"`
 }
 
 type LogoutCommand struct {
@@ -19,3 +29,78 @@ type LogoutCommand struct {
 type SendVerificationCodeCommand struct {
 	Email string
 }
+
+type RequestPasswordResetCommand struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordCommand struct {
+	Email    string `json:"email"`
+	Code     string `json:"code"`
+	Password string `json:"password"`
+}
+
+// OAuthCallbackCommand carries the parameters the identity provider redirects back with
+type OAuthCallbackCommand struct {
+	Provider string
+	Code     string
+	State    string
+
+	// Session describes the client, it is filled in by the controller
+	Session SessionMetadata
+}
+
+// TwoFactorCodeCommand carries a TOTP code or a recovery code of the current user
+type TwoFactorCodeCommand struct {
+	Code string `json:"code"`
+}
+
+// VerifyTwoFactorCommand completes a login challenge with a TOTP code or a recovery code
+type VerifyTwoFactorCommand struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// CreateAPITokenCommand creates a personal API token; without ExpiresInDays it expires after DefaultAPITokenLifetime
+type CreateAPITokenCommand struct {
+	Name          string         `json:"name"`
+	Scopes        []common.Scope `json:"scopes"`
+	ExpiresInDays int            `json:"expires_in_days"`
+}
+
+// RequestEmailChangeCommand sends a code to the new address of the current user
+type RequestEmailChangeCommand struct {
+	NewEmail string `json:"new_email"`
+}
+
+// ConfirmEmailChangeCommand switches the current user to the new address with the code sent there
+type ConfirmEmailChangeCommand struct {
+	NewEmail string `json:"new_email"`
+	Code     string `json:"code"`
+}
+
+// RevertEmailChangeCommand undoes an email change with the token sent to the previous address
+type RevertEmailChangeCommand struct {
+	Token string `json:"token"`
+}
+
+// SuspendUserCommand suspends a user; without ExpiresInDays the suspension lasts until it is lifted
+type SuspendUserCommand struct {
+	Reason        string `json:"reason"`
+	ExpiresInDays int    `json:"expires_in_days"`
+}
+
+type ChangeRoleCommand struct {
+	Role common.Role `json:"role"`
+}
+
+// CreateInviteCommand creates an invite code; without ExpiresInDays it never expires
+type CreateInviteCommand struct {
+	MaxUses       int `json:"max_uses"`
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+// RefreshTokenCommand carries a refresh token, to exchange or to revoke
+type RefreshTokenCommand struct {
+	RefreshToken string `json:"refresh_token"`
+}
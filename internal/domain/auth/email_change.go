@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// EmailChangeRevertPeriod is how long the previous address can undo an email change
+const EmailChangeRevertPeriod = 7 * 24 * time.Hour
+
+// EmailChange records a change of the login email, so the previous owner of the account can take it back
+type EmailChange struct {
+	ID       int
+	UserID   int
+	OldEmail string
+	NewEmail string
+	// RevertTokenHash is the digest of the token sent to the previous address
+	RevertTokenHash string
+	ExpiresAt       time.Time
+	RevertedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+// NewEmailChange returns the change and its revert token, which is only sent to the previous address
+func NewEmailChange(userID int, oldEmail, newEmail string, now time.Time) (EmailChange, string) {
+	token := NewSessionToken()
+	return EmailChange{
+		UserID:          userID,
+		OldEmail:        oldEmail,
+		NewEmail:        newEmail,
+		RevertTokenHash: HashSessionToken(token),
+		ExpiresAt:       now.Add(EmailChangeRevertPeriod),
+		CreatedAt:       now,
+	}, token
+}
+
+func (c *EmailChange) CanRevert(now time.Time) bool {
+	return c.RevertedAt == nil && now.Before(c.ExpiresAt)
+}
+
+func (c *EmailChange) Revert(now time.Time) {
+	c.RevertedAt = &now
+}
+
+type EmailChangeRepository interface {
+	Create(ctx context.Context, change *EmailChange) error
+	// GetByRevertToken returns the change with the token, or nil when there is none
+	GetByRevertToken(ctx context.Context, token string) (*EmailChange, error)
+	Update(ctx context.Context, change *EmailChange) error
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailChange_CanRevert(t *testing.T) {
+	now := time.Now()
+	change, token := NewEmailChange(1, "old@example.com", "new@example.com", now)
+
+	assert.NotEmpty(t, token)
+	assert.Equal(t, HashSessionToken(token), change.RevertTokenHash)
+	assert.True(t, change.CanRevert(now))
+	assert.False(t, change.CanRevert(now.Add(EmailChangeRevertPeriod)))
+
+	change.Revert(now)
+	assert.False(t, change.CanRevert(now))
+}
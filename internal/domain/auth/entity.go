@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"fmt"
 	"time"
 
 	"jcourse_go/internal/domain/common"
@@ -15,6 +16,11 @@ type User struct {
 	Role        common.Role
 	LastSeenAt  time.Time
 	SuspendedAt *time.Time
+	// SuspendedUntil ends a timed suspension, nil suspends until an admin lifts it
+	SuspendedUntil   *time.Time
+	SuspensionReason string
+	// DeletionScheduledAt is when the account will be anonymised, nil unless the user asked to leave
+	DeletionScheduledAt *time.Time
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -22,21 +28,90 @@ type User struct {
 }
 
 func (u *User) IsSuspended() bool {
-	return u.SuspendedAt != nil
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || time.Now().Before(*u.SuspendedUntil))
+}
+
+// Suspend locks the user out, until the given time or, without one, until Unsuspend
+func (u *User) Suspend(reason string, until *time.Time, now time.Time) {
+	u.SuspendedAt = &now
+	u.SuspendedUntil = until
+	u.SuspensionReason = reason
+	u.UpdatedAt = now
+}
+
+func (u *User) Unsuspend(now time.Time) {
+	u.SuspendedAt = nil
+	u.SuspendedUntil = nil
+	u.SuspensionReason = ""
+	u.UpdatedAt = now
+}
+
+func (u *User) ChangeRole(role common.Role, now time.Time) {
+	u.Role = role
+	u.UpdatedAt = now
 }
 
 func (u *User) IsAdmin() bool {
 	return u.Role == common.RoleAdmin
 }
 
+// AccountDeletionGracePeriod is how long a user can still change their mind after asking to delete the account
+const AccountDeletionGracePeriod = 14 * 24 * time.Hour
+
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// ScheduleDeletion marks the account for anonymisation once the grace period is over
+func (u *User) ScheduleDeletion(now time.Time) {
+	scheduledAt := now.Add(AccountDeletionGracePeriod)
+	u.DeletionScheduledAt = &scheduledAt
+	u.UpdatedAt = now
+}
+
+func (u *User) CancelDeletion(now time.Time) {
+	u.DeletionScheduledAt = nil
+	u.UpdatedAt = now
+}
+
+// Anonymize replaces the personal data of the account and deletes it. The ID is kept,
+// so the reviews of the user stay in place without pointing at anyone.
+func (u *User) Anonymize(now time.Time) {
+	u.Username = fmt.Sprintf("deleted-%d", u.ID)
+	u.Email = fmt.Sprintf("deleted-%d@invalid", u.ID)
+	u.Password = ""
+	u.DeletionScheduledAt = nil
+	u.UpdatedAt = now
+	u.DeletedAt = &now
+}
+
+func (u *User) ChangeEmail(email string, now time.Time) {
+	u.Email = email
+	u.UpdatedAt = now
+}
+
 func (u *User) UpdateNickname(nickname string) {
 	u.Username = nickname
 	u.UpdatedAt = time.Now()
 }
 
+// CodePurpose scopes a verification code to the flow it was sent for,
+// so that a code sent for one flow cannot be redeemed in another
+type CodePurpose string
+
+const (
+	CodePurposeRegister      CodePurpose = "register"
+	CodePurposeResetPassword CodePurpose = "reset_password"
+	// CodePurposeChangeEmail codes are sent to the new address of an email change
+	CodePurposeChangeEmail CodePurpose = "change_email"
+)
+
 type VerificationCode struct {
-	Code      string
-	Email     string
+	Code    string
+	Email   string
+	Purpose CodePurpose
+	// Attempts counts the wrong guesses, see MaxCodeAttempts
+	Attempts  int
 	ExpiresAt time.Time
 	CreatedAt time.Time
 }
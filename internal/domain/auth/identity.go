@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	"jcourse_go/pkg/random"
+)
+
+// OAuthStateTTL is how long a started external login can be completed
+const OAuthStateTTL = 10 * time.Minute
+
+// IdentityClaims is what an identity provider asserts about the user who signed in
+type IdentityClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// ExternalIdentity links an account of an identity provider to a user
+type ExternalIdentity struct {
+	ID        int
+	UserID    int
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// OAuthState remembers an authorization request until the provider redirects back.
+// State and Nonce tie the callback and the ID token to this request; CodeVerifier is the PKCE secret.
+type OAuthState struct {
+	State        string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func NewOAuthState(provider string, now time.Time) OAuthState {
+	return OAuthState{
+		State:        randomURLToken(),
+		Provider:     provider,
+		Nonce:        randomURLToken(),
+		CodeVerifier: randomURLToken(),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(OAuthStateTTL),
+	}
+}
+
+func (s *OAuthState) IsExpired(now time.Time) bool {
+	return now.After(s.ExpiresAt)
+}
+
+// CodeChallenge derives the S256 PKCE challenge sent with the authorization request
+func (s *OAuthState) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(s.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomURLToken() string {
+	token := random.Bytes(32)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+type ExternalIdentityRepository interface {
+	// Get returns the identity of the provider's subject, or nil when it is not linked
+	Get(ctx context.Context, provider string, subject string) (*ExternalIdentity, error)
+	Save(ctx context.Context, identity *ExternalIdentity) error
+}
+
+type OAuthStateRepository interface {
+	Save(ctx context.Context, state *OAuthState) error
+	// Take removes and returns the state, so a callback can only be completed once; nil when unknown
+	Take(ctx context.Context, state string) (*OAuthState, error)
+}
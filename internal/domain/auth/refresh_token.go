@@ -0,0 +1,39 @@
+package auth
+
+import "time"
+
+// RefreshTokenPrefix tells refresh tokens apart from session IDs and API tokens
+const RefreshTokenPrefix = "jcr_"
+
+// RefreshToken renews the access token of a session. Each refresh token is exchanged once for a new one;
+// the tokens issued for a session form a family, which ends with the session when a used token comes back.
+type RefreshToken struct {
+	ID        int
+	SessionID int
+	UserID    int
+	TokenHash string
+	// ExpiresAt follows the session, which slides forward on every refresh
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewRefreshToken returns a refresh token for the session and its secret, which is only handed to the client
+func NewRefreshToken(session *Session, now time.Time) (RefreshToken, string) {
+	secret := RefreshTokenPrefix + NewSessionToken()
+	return RefreshToken{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: HashSessionToken(secret),
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: now,
+	}, secret
+}
+
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
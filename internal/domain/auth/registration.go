@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/random"
+)
+
+// DisposableEmailDomains are throwaway mail providers that are always refused
+var DisposableEmailDomains = []string{
+	"10minutemail.com",
+	"dispostable.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"maildrop.cc",
+	"mailinator.com",
+	"sharklasers.com",
+	"temp-mail.org",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// RegistrationPolicy decides which email addresses may sign up
+type RegistrationPolicy struct {
+	// AllowedDomains admits addresses of these domains and their subdomains; empty admits every domain.
+	// An invite code admits addresses of other domains too.
+	AllowedDomains []string
+	// BlockedDomains are refused even with an invite code, in addition to DisposableEmailDomains
+	BlockedDomains []string
+	// InviteOnly requires an invite code for every registration
+	InviteOnly bool
+}
+
+// IsBlocked reports whether the email belongs to a blocked or disposable domain
+func (p RegistrationPolicy) IsBlocked(email string) bool {
+	domain := emailDomain(email)
+	return matchesDomain(domain, p.BlockedDomains) || matchesDomain(domain, DisposableEmailDomains)
+}
+
+// IsAllowedDomain reports whether the email belongs to one of the allowed domains, if any are configured
+func (p RegistrationPolicy) IsAllowedDomain(email string) bool {
+	return len(p.AllowedDomains) == 0 || matchesDomain(emailDomain(email), p.AllowedDomains)
+}
+
+// RequiresInvite reports whether the email can only register with an invite code
+func (p RegistrationPolicy) RequiresInvite(email string) bool {
+	return p.InviteOnly || !p.IsAllowedDomain(email)
+}
+
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// matchesDomain reports whether the domain is one of the domains or a subdomain of one
+func matchesDomain(domain string, domains []string) bool {
+	if domain == "" {
+		return false
+	}
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "@"))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	inviteCodeLength = 12
+	// inviteCodeAlphabet leaves out characters that are easily mistaken for one another
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// InviteCode lets up to MaxUses people register, see RegistrationPolicy
+type InviteCode struct {
+	ID        int
+	Code      string
+	MaxUses   int
+	Uses      int
+	ExpiresAt *time.Time
+	CreatedBy int
+	CreatedAt time.Time
+}
+
+func NewInviteCode(maxUses int, expiresAt *time.Time, createdBy int, now time.Time) InviteCode {
+	return InviteCode{
+		Code:      random.String(inviteCodeLength, inviteCodeAlphabet),
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+}
+
+// NormalizeInviteCode ignores case, spaces and dashes as people type them
+func NormalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func (c *InviteCode) IsUsable(now time.Time) bool {
+	if c.ExpiresAt != nil && !now.Before(*c.ExpiresAt) {
+		return false
+	}
+	return c.Uses < c.MaxUses
+}
+
+type InviteCodeRepository interface {
+	Create(ctx context.Context, invite *InviteCode) error
+	// Get returns the invite with the normalized code, or nil when there is none
+	Get(ctx context.Context, code string) (*InviteCode, error)
+	List(ctx context.Context, pagination common.Pagination) ([]InviteCode, int64, error)
+	// Redeem uses the invite once and reports false when it has no uses left
+	Redeem(ctx context.Context, id int) (bool, error)
+	Delete(ctx context.Context, id int) (bool, error)
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrationPolicy_Domains(t *testing.T) {
+	policy := RegistrationPolicy{AllowedDomains: []string{"sjtu.edu.cn"}, BlockedDomains: []string{"spam.example"}}
+
+	assert.False(t, policy.RequiresInvite("alice@sjtu.edu.cn"))
+	assert.False(t, policy.RequiresInvite("Alice@SJTU.edu.cn"))
+	assert.False(t, policy.RequiresInvite("alice@alumni.sjtu.edu.cn"))
+	assert.True(t, policy.RequiresInvite("alice@notsjtu.edu.cn"))
+	assert.True(t, policy.RequiresInvite("alice@example.com"))
+
+	assert.True(t, policy.IsBlocked("bob@spam.example"))
+	assert.True(t, policy.IsBlocked("bob@mail.spam.example"))
+	assert.True(t, policy.IsBlocked("bob@mailinator.com"))
+	assert.False(t, policy.IsBlocked("bob@example.com"))
+
+	assert.False(t, RegistrationPolicy{}.RequiresInvite("alice@example.com"))
+	assert.True(t, RegistrationPolicy{AllowedDomains: []string{"sjtu.edu.cn"}, InviteOnly: true}.RequiresInvite("alice@sjtu.edu.cn"))
+}
+
+func TestInviteCode_IsUsable(t *testing.T) {
+	now := time.Now()
+	invite := NewInviteCode(2, nil, 1, now)
+
+	assert.Len(t, invite.Code, inviteCodeLength)
+	assert.Equal(t, invite.Code, NormalizeInviteCode(invite.Code[:4]+"-"+invite.Code[4:]))
+	assert.True(t, invite.IsUsable(now))
+
+	invite.Uses = 2
+	assert.False(t, invite.IsUsable(now))
+
+	expiresAt := now.Add(time.Hour)
+	invite = NewInviteCode(2, &expiresAt, 1, now)
+	assert.True(t, invite.IsUsable(now))
+	assert.False(t, invite.IsUsable(expiresAt))
+}
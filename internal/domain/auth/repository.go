@@ -1,10 +1,20 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+)
 
 type CodeRepository interface {
-	Get(ctx context.Context, email string) (*VerificationCode, error)
+	// Get returns the code sent to the email for the purpose, or nil when there is none
+	Get(ctx context.Context, email string, purpose CodePurpose) (*VerificationCode, error)
+	// Save stores a new code for the email and purpose, replacing the previous one along with its attempts
 	Save(ctx context.Context, code *VerificationCode) error
+	// IncrementAttempts atomically counts a wrong guess of the code and returns the attempts after it,
+	// or 0 when the code no longer exists
+	IncrementAttempts(ctx context.Context, code *VerificationCode) (int, error)
 	Delete(ctx context.Context, code *VerificationCode) error
 }
 
@@ -12,16 +22,62 @@ type UserFilter struct {
 	UserIDs []int
 }
 
+// UserSearchFilter finds users for admins; Query matches part of the email or nickname
+type UserSearchFilter struct {
+	Query      string
+	Pagination common.Pagination
+}
+
 type UserRepository interface {
 	Get(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, userID int) (*User, error)
 	FindBy(ctx context.Context, filter UserFilter) ([]User, error)
+	// Search returns a page of the users matching the filter and how many match in total
+	Search(ctx context.Context, filter UserSearchFilter) ([]User, int64, error)
 	Save(ctx context.Context, user *User) (int, error)
 	Update(ctx context.Context, user *User) error
+	// UpdatePassword replaces only the password hash, leaving changes made to the rest of the user alone
+	UpdatePassword(ctx context.Context, userID int, passwordHash string, at time.Time) error
+	// ChangeEmail sets the email of the user and reports false, without changing anything, when another account uses it
+	ChangeEmail(ctx context.Context, userID int, email string) (bool, error)
+	// TouchLastSeen records when the user was last active
+	TouchLastSeen(ctx context.Context, userID int, at time.Time) error
+	// LiftExpiredSuspensions ends the suspensions whose time is up and returns how many were lifted
+	LiftExpiredSuspensions(ctx context.Context, now time.Time) (int64, error)
+	// FindDeletionDue returns the users whose deletion was scheduled at or before the given time
+	FindDeletionDue(ctx context.Context, before time.Time) ([]User, error)
+	// Anonymize stores the anonymised user and purges the personal data tied to the account:
+	// sessions and their refresh tokens, verification codes, login failures, API tokens, second factors,
+	// linked identities and email changes
+	Anonymize(ctx context.Context, user *User) error
 }
 
 type SessionRepository interface {
-	Store(ctx context.Context, userID int) (string, error)
-	Get(ctx context.Context, sessionID string) (int, error)
-	Delete(ctx context.Context, sessionID string) error
+	// Store opens a session and returns its token; only a hash of the token is persisted
+	Store(ctx context.Context, userID int, metadata SessionMetadata) (string, error)
+	// Get returns the unexpired session of the token, or nil when there is none
+	Get(ctx context.Context, token string) (*Session, error)
+	// GetByID returns the unexpired session, or nil when there is none
+	GetByID(ctx context.Context, sessionID int) (*Session, error)
+	Delete(ctx context.Context, token string) error
+	// ListByUser returns the unexpired sessions of the user, most recently active first
+	ListByUser(ctx context.Context, userID int) ([]Session, error)
+	// Update persists the activity and expiry of the session
+	Update(ctx context.Context, session *Session) error
+	// DeleteByID removes a session of the user and reports whether it existed
+	DeleteByID(ctx context.Context, userID int, sessionID int) (bool, error)
+	// DeleteOthers removes every session of the user except keepID and returns how many were removed
+	DeleteOthers(ctx context.Context, userID int, keepID int) (int64, error)
+	// DeleteByUser removes every session of the user and returns how many were removed
+	DeleteByUser(ctx context.Context, userID int) (int64, error)
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	// GetByToken returns the refresh token of the secret, used or not, or nil when there is none
+	GetByToken(ctx context.Context, secret string) (*RefreshToken, error)
+	// MarkUsed records the exchange of the token and reports false when it had already been used
+	MarkUsed(ctx context.Context, id int, at time.Time) (bool, error)
+	// DeleteExpired removes the tokens that expired before the given time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"jcourse_go/pkg/random"
+)
+
+// SessionTokenBytes is the entropy of a session token: 256 bits
+const SessionTokenBytes = 32
+
+const (
+	// SessionIdleTimeout is how long a session survives without activity
+	SessionIdleTimeout = 24 * time.Hour
+	// SessionMaxLifetime bounds how far activity can extend a session
+	SessionMaxLifetime = 30 * 24 * time.Hour
+	// SessionTouchInterval throttles how often activity is written back
+	SessionTouchInterval = 5 * time.Minute
+)
+
+// SessionMetadata describes the client that opened a session
+type SessionMetadata struct {
+	IP        string
+	UserAgent string
+}
+
+// Device returns a short human readable description of the client, e.g. "Firefox on Linux"
+func (m SessionMetadata) Device() string {
+	browser := matchUserAgent(m.UserAgent, []uaRule{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	})
+	os := matchUserAgent(m.UserAgent, []uaRule{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Unknown device"
+	}
+}
+
+type uaRule struct {
+	token string
+	name  string
+}
+
+// matchUserAgent returns the name of the first rule whose token occurs in the user agent
+func matchUserAgent(userAgent string, rules []uaRule) string {
+	for _, rule := range rules {
+		if strings.Contains(userAgent, rule.token) {
+			return rule.name
+		}
+	}
+	return ""
+}
+
+type Session struct {
+	ID         int
+	UserID     int
+	TokenHash  string
+	Metadata   SessionMetadata
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+// NewSession opens a session for the user, valid until it has been idle for SessionIdleTimeout
+func NewSession(userID int, tokenHash string, metadata SessionMetadata, now time.Time) Session {
+	session := Session{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Metadata:  metadata,
+		CreatedAt: now,
+	}
+	session.Touch(now)
+	return session
+}
+
+// NeedsTouch reports whether enough time has passed since the last recorded activity to record it again
+func (s *Session) NeedsTouch(now time.Time) bool {
+	return now.Sub(s.LastSeenAt) >= SessionTouchInterval
+}
+
+// Touch records activity and slides the expiry forward, never beyond SessionMaxLifetime after creation
+func (s *Session) Touch(now time.Time) {
+	s.LastSeenAt = now
+	s.ExpiresAt = now.Add(SessionIdleTimeout)
+	if deadline := s.CreatedAt.Add(SessionMaxLifetime); s.ExpiresAt.After(deadline) {
+		s.ExpiresAt = deadline
+	}
+}
+
+// NewSessionToken returns a random opaque token; it is handed to the client and never stored
+func NewSessionToken() string {
+	token := random.Bytes(SessionTokenBytes)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// HashSessionToken returns the digest of the token that is persisted in its place
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CSRFToken derives the CSRF token of a cookie session. It is bound to the session, so a cross-site
+// page can neither guess it nor plant its own, and the session token cannot be recovered from it.
+func CSRFToken(sessionToken string) string {
+	sum := sha256.Sum256([]byte("csrf:" + sessionToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSessionToken(t *testing.T) {
+	token := NewSessionToken()
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	assert.Len(t, raw, SessionTokenBytes)
+	assert.NotEqual(t, token, NewSessionToken())
+}
+
+func TestHashSessionToken(t *testing.T) {
+	token := NewSessionToken()
+
+	hash := HashSessionToken(token)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashSessionToken(token))
+	assert.NotEqual(t, hash, HashSessionToken(NewSessionToken()))
+	assert.NotContains(t, hash, token)
+}
+
+func TestSession_TouchSlidesExpiry(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := NewSession(1, "hash", SessionMetadata{}, created)
+	assert.Equal(t, created.Add(SessionIdleTimeout), session.ExpiresAt)
+
+	assert.False(t, session.NeedsTouch(created.Add(time.Minute)))
+	later := created.Add(time.Hour)
+	assert.True(t, session.NeedsTouch(later))
+
+	session.Touch(later)
+	assert.Equal(t, later, session.LastSeenAt)
+	assert.Equal(t, later.Add(SessionIdleTimeout), session.ExpiresAt)
+}
+
+func TestSession_TouchIsBoundedByMaxLifetime(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := NewSession(1, "hash", SessionMetadata{}, created)
+
+	session.Touch(created.Add(SessionMaxLifetime - time.Hour))
+	assert.Equal(t, created.Add(SessionMaxLifetime), session.ExpiresAt)
+}
+
+func TestSessionMetadata_Device(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome on Windows"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1", "Safari on iOS"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"curl/8.4.0", "curl"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, SessionMetadata{UserAgent: tt.userAgent}.Device(), tt.userAgent)
+	}
+}
+
+func TestCSRFToken(t *testing.T) {
+	token := NewSessionToken()
+
+	assert.Equal(t, CSRFToken(token), CSRFToken(token))
+	assert.NotEqual(t, CSRFToken(token), CSRFToken(NewSessionToken()))
+	assert.NotContains(t, CSRFToken(token), token)
+	assert.NotEqual(t, HashSessionToken(token), CSRFToken(token))
+}
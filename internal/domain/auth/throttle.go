@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// ThrottlePolicy describes when repeated failures lock a key out and for how long
+type ThrottlePolicy struct {
+	// Threshold is the number of consecutive failures allowed before the first lockout
+	Threshold int
+	// BaseLockout is the first lockout; every further failure doubles it, up to MaxLockout
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	// Window forgets failures after this long without a new one
+	Window time.Duration
+}
+
+var (
+	// EmailThrottlePolicy protects a single account against password guessing
+	EmailThrottlePolicy = ThrottlePolicy{Threshold: 5, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: 15 * time.Minute}
+	// IPThrottlePolicy is more lenient, as campus networks put many users behind one address
+	IPThrottlePolicy = ThrottlePolicy{Threshold: 20, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: 15 * time.Minute}
+)
+
+// MaxCodeAttempts is the number of wrong guesses after which a verification code is invalidated
+const MaxCodeAttempts = 5
+
+// ThrottleKey identifies what failures are counted for, e.g. "email:user@example.com" or "ip:203.0.113.7"
+type ThrottleKey string
+
+func EmailThrottleKey(email string) ThrottleKey {
+	return ThrottleKey("email:" + email)
+}
+
+func IPThrottleKey(ip string) ThrottleKey {
+	return ThrottleKey("ip:" + ip)
+}
+
+// FailureCounter counts the consecutive failures of a key
+type FailureCounter struct {
+	Key           ThrottleKey
+	Failures      int
+	LockedUntil   *time.Time
+	LastFailureAt time.Time
+}
+
+// RetryAfter returns how long the key is still locked out, 0 when it is not
+func (c *FailureCounter) RetryAfter(now time.Time) time.Duration {
+	if c == nil || c.LockedUntil == nil || !now.Before(*c.LockedUntil) {
+		return 0
+	}
+	return c.LockedUntil.Sub(now)
+}
+
+// Lockout returns how long a key with the given number of consecutive failures is locked out, 0 below the threshold
+func (p ThrottlePolicy) Lockout(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+	lockout := p.BaseLockout
+	for i := p.Threshold; i < failures && lockout < p.MaxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, p.MaxLockout)
+}
+
+// RecordFailure counts a failure and locks the key out once the policy's threshold is reached
+func (c *FailureCounter) RecordFailure(policy ThrottlePolicy, now time.Time) {
+	if c.Failures > 0 && now.Sub(c.LastFailureAt) > policy.Window && c.RetryAfter(now) == 0 {
+		c.Failures = 0
+	}
+	c.Failures++
+	c.LastFailureAt = now
+	c.lockOut(policy, now)
+}
+
+// lockOut locks the key out for as long as its failures call for, never shortening a lockout already in place
+func (c *FailureCounter) lockOut(policy ThrottlePolicy, now time.Time) {
+	lockout := policy.Lockout(c.Failures)
+	if lockout == 0 {
+		return
+	}
+	lockedUntil := now.Add(lockout)
+	if c.LockedUntil == nil || lockedUntil.After(*c.LockedUntil) {
+		c.LockedUntil = &lockedUntil
+	}
+}
+
+type FailureCounterRepository interface {
+	// Get returns the counter of the key, or nil when it has no recorded failures
+	Get(ctx context.Context, key ThrottleKey) (*FailureCounter, error)
+	// RecordFailure atomically counts a failure of the key as FailureCounter.RecordFailure does, so concurrent
+	// failures are all counted, and returns the counter after it
+	RecordFailure(ctx context.Context, key ThrottleKey, policy ThrottlePolicy, now time.Time) (*FailureCounter, error)
+	Delete(ctx context.Context, key ThrottleKey) error
+}
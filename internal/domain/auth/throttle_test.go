@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureCounter_ProgressiveLockout(t *testing.T) {
+	policy := ThrottlePolicy{Threshold: 3, BaseLockout: time.Minute, MaxLockout: 4 * time.Minute, Window: 15 * time.Minute}
+	now := time.Now()
+	counter := &FailureCounter{}
+
+	counter.RecordFailure(policy, now)
+	counter.RecordFailure(policy, now)
+	assert.Zero(t, counter.RetryAfter(now))
+
+	counter.RecordFailure(policy, now)
+	assert.Equal(t, time.Minute, counter.RetryAfter(now))
+
+	// Every failure after the lockout doubles it, up to the maximum
+	now = now.Add(time.Minute)
+	counter.RecordFailure(policy, now)
+	assert.Equal(t, 2*time.Minute, counter.RetryAfter(now))
+	now = now.Add(2 * time.Minute)
+	counter.RecordFailure(policy, now)
+	counter.RecordFailure(policy, now)
+	assert.Equal(t, 4*time.Minute, counter.RetryAfter(now))
+}
+
+func TestFailureCounter_ForgetsAfterWindow(t *testing.T) {
+	policy := ThrottlePolicy{Threshold: 3, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: 15 * time.Minute}
+	now := time.Now()
+	counter := &FailureCounter{}
+
+	counter.RecordFailure(policy, now)
+	counter.RecordFailure(policy, now)
+	counter.RecordFailure(policy, now.Add(time.Hour))
+
+	assert.Equal(t, 1, counter.Failures)
+	assert.Zero(t, counter.RetryAfter(now.Add(time.Hour)))
+}
+
+func TestThrottlePolicy_Lockout(t *testing.T) {
+	policy := ThrottlePolicy{Threshold: 3, BaseLockout: time.Minute, MaxLockout: 4 * time.Minute}
+
+	assert.Zero(t, policy.Lockout(2))
+	assert.Equal(t, time.Minute, policy.Lockout(3))
+	assert.Equal(t, 2*time.Minute, policy.Lockout(4))
+	assert.Equal(t, 4*time.Minute, policy.Lockout(100))
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"jcourse_go/pkg/random"
+)
+
+const (
+	RecoveryCodeCount = 10
+	// LoginChallengeTTL is how long the second step of a login can be completed
+	LoginChallengeTTL = 5 * time.Minute
+	// MaxLoginChallengeAttempts is how many wrong codes end a challenge
+	MaxLoginChallengeAttempts = 5
+
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+)
+
+// TwoFactor is a user's TOTP second factor. It only protects logins once enrollment is confirmed.
+type TwoFactor struct {
+	UserID int
+	// Secret is the base32 TOTP secret shared with the authenticator app
+	Secret string
+	// RecoveryCodes are the SHA-256 digests of the unused recovery codes
+	RecoveryCodes []string
+	// LastUsedStep is the time step of the last accepted code, so no code is accepted twice
+	LastUsedStep int64
+	EnabledAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (t *TwoFactor) IsEnabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// UseStep records an accepted code and reports false when its step was already used
+func (t *TwoFactor) UseStep(step int64) bool {
+	if step <= t.LastUsedStep {
+		return false
+	}
+	t.LastUsedStep = step
+	return true
+}
+
+// UseRecoveryCode consumes the recovery code and reports whether it was valid
+func (t *TwoFactor) UseRecoveryCode(code string) bool {
+	hash := HashRecoveryCode(code)
+	for i, candidate := range t.RecoveryCodes {
+		if candidate == hash {
+			t.RecoveryCodes = append(t.RecoveryCodes[:i:i], t.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// NewRecoveryCodes returns fresh recovery codes to show to the user once, and their digests to store
+func NewRecoveryCodes() ([]string, []string) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		raw := random.String(recoveryCodeLength, recoveryCodeAlphabet)
+		codes[i] = raw[:recoveryCodeLength/2] + "-" + raw[recoveryCodeLength/2:]
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes
+}
+
+// HashRecoveryCode digests the code, ignoring case, spaces and dashes as people type them
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoginChallenge is a login that passed the password check and waits for the second factor
+type LoginChallenge struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	Session   SessionMetadata
+	Attempts  int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// NewLoginChallenge returns the challenge and its token; like session tokens, only the hash is stored
+func NewLoginChallenge(userID int, metadata SessionMetadata, now time.Time) (LoginChallenge, string) {
+	token := NewSessionToken()
+	return LoginChallenge{
+		UserID:    userID,
+		TokenHash: HashSessionToken(token),
+		Session:   metadata,
+		CreatedAt: now,
+		ExpiresAt: now.Add(LoginChallengeTTL),
+	}, token
+}
+
+type TwoFactorRepository interface {
+	// Get returns the second factor of the user, or nil when none was enrolled
+	Get(ctx context.Context, userID int) (*TwoFactor, error)
+	// GetForUpdate is Get that also locks the row for the transaction carried by ctx,
+	// so a code or a recovery code cannot be used by two requests at once
+	GetForUpdate(ctx context.Context, userID int) (*TwoFactor, error)
+	// Save creates or replaces the second factor of the user
+	Save(ctx context.Context, twoFactor *TwoFactor) error
+	Delete(ctx context.Context, userID int) error
+}
+
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, challenge *LoginChallenge) error
+	// Get returns the unexpired challenge of the token, or nil when there is none
+	Get(ctx context.Context, token string) (*LoginChallenge, error)
+	// IncrementAttempts atomically counts a wrong code for the challenge and returns the attempts after it,
+	// or 0 when the challenge no longer exists
+	IncrementAttempts(ctx context.Context, id int) (int, error)
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactor_UseStepRejectsReplay(t *testing.T) {
+	twoFactor := &TwoFactor{}
+
+	assert.True(t, twoFactor.UseStep(100))
+	assert.False(t, twoFactor.UseStep(100))
+	assert.False(t, twoFactor.UseStep(99))
+	assert.True(t, twoFactor.UseStep(101))
+}
+
+func TestTwoFactor_UseRecoveryCode(t *testing.T) {
+	codes, hashes := NewRecoveryCodes()
+	twoFactor := &TwoFactor{RecoveryCodes: hashes}
+
+	// Codes are accepted regardless of case and dashes
+	typed := strings.ToUpper(strings.ReplaceAll(codes[3], "-", ""))
+	assert.True(t, twoFactor.UseRecoveryCode(typed))
+	assert.Len(t, twoFactor.RecoveryCodes, RecoveryCodeCount-1)
+	assert.False(t, twoFactor.UseRecoveryCode(codes[3]))
+	assert.Equal(t, hashes[4], twoFactor.RecoveryCodes[3])
+}
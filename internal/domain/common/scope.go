@@ -0,0 +1,51 @@
+package common
+
+import "strings"
+
+// Scope limits what a personal API token may do on behalf of its user
+type Scope string
+
+const (
+	ScopeReviewRead      Scope = "review:read"
+	ScopeReviewWrite     Scope = "review:write"
+	ScopeCourseRead      Scope = "course:read"
+	ScopeCourseWrite     Scope = "course:write"
+	ScopeUserRead        Scope = "user:read"
+	ScopeUserWrite       Scope = "user:write"
+	ScopeAdminPoints     Scope = "admin:points"
+	ScopeAdminEvents     Scope = "admin:events"
+	ScopeAdminWebhooks   Scope = "admin:webhooks"
+	ScopeAdminStatistics Scope = "admin:statistics"
+	ScopeAdminInvites    Scope = "admin:invites"
+	ScopeAdminUsers      Scope = "admin:users"
+)
+
+// AllScopes lists every scope a token can be granted
+var AllScopes = []Scope{
+	ScopeReviewRead,
+	ScopeReviewWrite,
+	ScopeCourseRead,
+	ScopeCourseWrite,
+	ScopeUserRead,
+	ScopeUserWrite,
+	ScopeAdminPoints,
+	ScopeAdminEvents,
+	ScopeAdminWebhooks,
+	ScopeAdminStatistics,
+	ScopeAdminInvites,
+	ScopeAdminUsers,
+}
+
+func (s Scope) IsValid() bool {
+	for _, scope := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin reports whether the scope can only be granted to admins
+func (s Scope) IsAdmin() bool {
+	return strings.HasPrefix(string(s), "admin:")
+}
@@ -0,0 +1,9 @@
+package common
+
+import "context"
+
+// Transactor runs fn inside a database transaction carried by the context passed to fn.
+// Repositories called with that context take part in the same transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
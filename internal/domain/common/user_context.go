@@ -12,6 +12,27 @@ type CommonContext struct {
 type User struct {
 	UserID int
 	Role   Role
+	// SessionID is the session the request was authenticated with, 0 when anonymous
+	SessionID int
+	// TwoFactorPending marks an admin without two-factor authentication, who acts as a regular user until enrolled
+	TwoFactorPending bool
+	// APITokenID is the personal API token the request was authenticated with, 0 otherwise
+	APITokenID int
+	// Scopes restrict a request authenticated with an API token; sessions are not restricted
+	Scopes []Scope
+}
+
+// HasScope reports whether the request may act within the scope
+func (u *User) HasScope(scope Scope) bool {
+	if u == nil || u.APITokenID == 0 {
+		return true
+	}
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
 func NewCommonContext(ctx context.Context, user *User) *CommonContext {
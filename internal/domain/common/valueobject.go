@@ -7,6 +7,10 @@ const (
 	RoleUser  Role = "user"
 )
 
+func (r Role) IsValid() bool {
+	return r == RoleAdmin || r == RoleUser
+}
+
 // SystemUser represents the system user for internal operations
 var SystemUser = &User{
 	UserID: 0, // System user ID
@@ -3,6 +3,7 @@ package email
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"jcourse_go/internal/domain/auth"
 )
@@ -11,6 +12,17 @@ type Template interface {
 	Execute(ctx context.Context, input *auth.VerificationCode) RenderedEmail
 }
 
+// EmailChangedNotice tells the previous address that the login email was changed, with a link to undo it
+type EmailChangedNotice struct {
+	NewEmail  string
+	RevertURL string
+	ExpiresAt time.Time
+}
+
+type EmailChangedTemplate interface {
+	Execute(ctx context.Context, input *EmailChangedNotice) RenderedEmail
+}
+
 type RenderedEmail struct {
 	Title string
 	Body  string
@@ -22,23 +34,26 @@ type Sender interface {
 
 type EmailService interface {
 	SendVerificationCode(ctx context.Context, emailAddr string, input *auth.VerificationCode) error
+	SendEmailChangedNotice(ctx context.Context, emailAddr string, input *EmailChangedNotice) error
 }
 
 // EmailServiceImpl implements EmailService interface
 type EmailServiceImpl struct {
-	sender   Sender
-	template Template
+	sender               Sender
+	template             Template
+	emailChangedTemplate EmailChangedTemplate
 }
 
 func NewEmailService() EmailService {
 	return nil
 }
 
-// NewEmailServiceImpl creates a new email service with sender and template
-func NewEmailServiceImpl(sender Sender, template Template) EmailService {
+// NewEmailServiceImpl creates a new email service with sender and templates
+func NewEmailServiceImpl(sender Sender, template Template, emailChangedTemplate EmailChangedTemplate) EmailService {
 	return &EmailServiceImpl{
-		sender:   sender,
-		template: template,
+		sender:               sender,
+		template:             template,
+		emailChangedTemplate: emailChangedTemplate,
 	}
 }
 
@@ -50,3 +65,12 @@ func (s *EmailServiceImpl) SendVerificationCode(ctx context.Context, emailAddr s
 	renderedEmail := s.template.Execute(ctx, input)
 	return s.sender.Send(ctx, emailAddr, renderedEmail)
 }
+
+func (s *EmailServiceImpl) SendEmailChangedNotice(ctx context.Context, emailAddr string, input *EmailChangedNotice) error {
+	if s.sender == nil || s.emailChangedTemplate == nil {
+		return fmt.Errorf("email service not properly initialized")
+	}
+
+	renderedEmail := s.emailChangedTemplate.Execute(ctx, input)
+	return s.sender.Send(ctx, emailAddr, renderedEmail)
+}
@@ -18,3 +18,8 @@ func (s *MockEmailService) SendVerificationCode(ctx context.Context, emailAddr s
 	fmt.Printf("Mock email sent to %s with code %s\n", emailAddr, input.Code)
 	return nil
 }
+
+func (s *MockEmailService) SendEmailChangedNotice(ctx context.Context, emailAddr string, input *EmailChangedNotice) error {
+	fmt.Printf("Mock email change notice sent to %s with link %s\n", emailAddr, input.RevertURL)
+	return nil
+}
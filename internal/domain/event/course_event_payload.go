@@ -0,0 +1,26 @@
+package event
+
+// CoursePayload describes a user's relation to a course changing
+type CoursePayload struct {
+	UserID   int    `json:"user_id"`
+	CourseID int    `json:"course_id"`
+	Action   string `json:"action"` // "watched", "unwatched" or "enrolled"
+}
+
+func (p *CoursePayload) Type() Type {
+	switch p.Action {
+	case "unwatched":
+		return TypeCourseUnwatched
+	case "enrolled":
+		return TypeCourseEnrolled
+	default:
+		return TypeCourseWatched
+	}
+}
+
+func (p *CoursePayload) Aggregates() []AggregateRef {
+	return []AggregateRef{
+		{Type: AggregateCourse, ID: p.CourseID},
+		{Type: AggregateUser, ID: p.UserID},
+	}
+}
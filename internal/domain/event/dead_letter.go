@@ -0,0 +1,85 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+)
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusFailed    DeadLetterStatus = "failed"
+	DeadLetterStatusReplayed  DeadLetterStatus = "replayed"
+	DeadLetterStatusDiscarded DeadLetterStatus = "discarded"
+)
+
+// DeadLetter is a delivery to a single handler that failed after every retry
+type DeadLetter struct {
+	ID         int64
+	EventID    string
+	EventType  Type
+	Handler    string
+	Version    int
+	Payload    []byte
+	OccurredAt time.Time
+
+	Status    DeadLetterStatus
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewDeadLetter(e Event, handler string, attempts int, lastErr error) (DeadLetter, error) {
+	payload, err := json.Marshal(e.Payload())
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("failed to marshal payload of event %s: %w", e.ID(), err)
+	}
+
+	lastError := ""
+	if lastErr != nil {
+		lastError = lastErr.Error()
+	}
+
+	now := time.Now()
+	return DeadLetter{
+		EventID:    e.ID(),
+		EventType:  e.Type(),
+		Handler:    handler,
+		Version:    PayloadVersion(e.Type()),
+		Payload:    payload,
+		OccurredAt: e.Timestamp(),
+		Status:     DeadLetterStatusFailed,
+		Attempts:   attempts,
+		LastError:  lastError,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// ToEvent restores the event that failed
+func (d *DeadLetter) ToEvent() (Event, error) {
+	return UnmarshalEvent(d.EventID, d.EventType, d.Version, d.Payload, d.OccurredAt)
+}
+
+// DeadLetterSink receives deliveries that exhausted their retries
+type DeadLetterSink interface {
+	Store(ctx context.Context, e Event, handler string, attempts int, lastErr error) error
+}
+
+type DeadLetterFilter struct {
+	Status     DeadLetterStatus
+	EventType  *Type
+	Handler    string
+	Pagination common.Pagination
+}
+
+type DeadLetterRepository interface {
+	Save(ctx context.Context, deadLetter *DeadLetter) error
+	Get(ctx context.Context, id int64) (*DeadLetter, error)
+	List(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, int64, error)
+}
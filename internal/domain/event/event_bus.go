@@ -1,14 +1,34 @@
 package event
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 type Handler interface {
 	Handle(ctx context.Context, e Event) error
 }
 
+// NamedHandler lets a handler choose the name it is recorded under, e.g. in dead letters
+type NamedHandler interface {
+	Name() string
+}
+
+// HandlerName returns the stable name of the handler, defaulting to its type
+func HandlerName(h Handler) string {
+	if named, ok := h.(NamedHandler); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", h)
+}
+
 type EventBus interface {
 	Register(eventType Type, handler Handler) error
 	Dispatch(ctx context.Context, events ...Event) error
+	// Redeliver hands the event to the registered handler with the given name only
+	Redeliver(ctx context.Context, e Event, handlerName string) error
+	// SetDeadLetterSink receives the deliveries that exhausted their retries
+	SetDeadLetterSink(sink DeadLetterSink)
 	Start() error
 	Shutdown() error
 }
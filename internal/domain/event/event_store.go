@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+)
+
+// AggregateType names the kind of entity an event is about
+type AggregateType string
+
+const (
+	AggregateUser   AggregateType = "user"
+	AggregateReview AggregateType = "review"
+	AggregateCourse AggregateType = "course"
+)
+
+func (t AggregateType) IsValid() bool {
+	switch t {
+	case AggregateUser, AggregateReview, AggregateCourse:
+		return true
+	}
+	return false
+}
+
+// AggregateRef points at an entity an event is about
+type AggregateRef struct {
+	Type AggregateType
+	ID   int
+}
+
+// AggregatePayload is implemented by payloads that concern specific entities,
+// so their events show up in the timelines of those entities
+type AggregatePayload interface {
+	Aggregates() []AggregateRef
+}
+
+// StoredEvent is a published event kept in the append-only event store
+type StoredEvent struct {
+	ID         int64
+	EventID    string
+	EventType  Type
+	Version    int
+	Payload    []byte
+	Aggregates []AggregateRef
+	OccurredAt time.Time
+	StoredAt   time.Time
+}
+
+func NewStoredEvent(e Event) (StoredEvent, error) {
+	payload, err := json.Marshal(e.Payload())
+	if err != nil {
+		return StoredEvent{}, fmt.Errorf("failed to marshal payload of event %s: %w", e.ID(), err)
+	}
+
+	var aggregates []AggregateRef
+	if aggregatePayload, ok := e.Payload().(AggregatePayload); ok {
+		aggregates = aggregatePayload.Aggregates()
+	}
+
+	return StoredEvent{
+		EventID:    e.ID(),
+		EventType:  e.Type(),
+		Version:    PayloadVersion(e.Type()),
+		Payload:    payload,
+		Aggregates: aggregates,
+		OccurredAt: e.Timestamp(),
+		StoredAt:   time.Now(),
+	}, nil
+}
+
+type EventStoreFilter struct {
+	// Aggregate restricts the events to those about one entity
+	Aggregate  *AggregateRef
+	EventType  *Type
+	From       *time.Time
+	To         *time.Time
+	Pagination common.Pagination
+}
+
+type EventStoreRepository interface {
+	// Append stores the event once; appending an event that is already stored does nothing
+	Append(ctx context.Context, storedEvent *StoredEvent) error
+	// List returns the matching events in the order they occurred
+	List(ctx context.Context, filter EventStoreFilter) ([]StoredEvent, int64, error)
+}
@@ -3,6 +3,7 @@ package event
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ type Event interface {
 	ID() string
 	Type() Type
 	Payload() Payload
+	Timestamp() time.Time
 	ToJSON() ([]byte, error)
 }
 
@@ -19,18 +21,32 @@ type Payload interface {
 	Type() Type
 }
 
-type Type int
+// Type is the stable name of an event; it is persisted, so existing values must never change
+type Type string
 
 const (
-	TypeUserCreated    Type = iota
-	TypeReviewCreated  Type = iota
-	TypeReviewModified Type = iota
+	TypeUserCreated         Type = "user.created"
+	TypeReviewCreated       Type = "review.created"
+	TypeReviewModified      Type = "review.modified"
+	TypeReviewDeleted       Type = "review.deleted"
+	TypeReviewActionPosted  Type = "review.action_posted"
+	TypeReviewActionDeleted Type = "review.action_deleted"
+	TypeCourseWatched       Type = "course.watched"
+	TypeCourseUnwatched     Type = "course.unwatched"
+	TypeCourseEnrolled      Type = "course.enrolled"
 )
 
 type Publisher interface {
 	Publish(ctx context.Context, events ...Event) error
 }
 
+// NopPublisher discards the events, for services running without an event bus
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, events ...Event) error {
+	return nil
+}
+
 type BaseEvent struct {
 	id        string
 	eventType Type
@@ -47,6 +63,41 @@ func NewBaseEvent(eventType Type, payload Payload) *BaseEvent {
 	}
 }
 
+// RestoreBaseEvent rebuilds an event that was persisted or received from another process
+func RestoreBaseEvent(id string, eventType Type, payload Payload, timestamp time.Time) *BaseEvent {
+	return &BaseEvent{
+		id:        id,
+		eventType: eventType,
+		payload:   payload,
+		timestamp: timestamp,
+	}
+}
+
+// UnmarshalEvent restores an event from a payload serialized at the given schema version,
+// keeping its ID so consumers can deduplicate
+func UnmarshalEvent(id string, eventType Type, version int, data []byte, timestamp time.Time) (Event, error) {
+	payload, err := DecodePayload(eventType, version, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", id, err)
+	}
+	return RestoreBaseEvent(id, eventType, payload, timestamp), nil
+}
+
+// FromJSON restores an event serialized by ToJSON
+func FromJSON(data []byte) (Event, error) {
+	var envelope struct {
+		ID        string          `json:"id"`
+		Type      Type            `json:"type"`
+		Version   int             `json:"version"`
+		Payload   json.RawMessage `json:"payload"`
+		Timestamp time.Time       `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	return UnmarshalEvent(envelope.ID, envelope.Type, envelope.Version, envelope.Payload, envelope.Timestamp)
+}
+
 func (e *BaseEvent) ID() string {
 	return e.id
 }
@@ -67,6 +118,7 @@ func (e *BaseEvent) ToJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
 		"id":        e.id,
 		"type":      e.eventType,
+		"version":   PayloadVersion(e.eventType),
 		"payload":   e.payload,
 		"timestamp": e.timestamp,
 	})
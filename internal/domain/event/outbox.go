@@ -0,0 +1,63 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// OutboxMessage is an event waiting to be relayed to the event bus
+type OutboxMessage struct {
+	ID         int64
+	EventID    string
+	EventType  Type
+	Version    int
+	Payload    []byte
+	OccurredAt time.Time
+
+	Status    OutboxStatus
+	Attempts  int
+	LastError string
+	SentAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewOutboxMessage(e Event) (OutboxMessage, error) {
+	payload, err := json.Marshal(e.Payload())
+	if err != nil {
+		return OutboxMessage{}, fmt.Errorf("failed to marshal payload of event %s: %w", e.ID(), err)
+	}
+	return OutboxMessage{
+		EventID:    e.ID(),
+		EventType:  e.Type(),
+		Version:    PayloadVersion(e.Type()),
+		Payload:    payload,
+		OccurredAt: e.Timestamp(),
+		Status:     OutboxStatusPending,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// ToEvent restores the original event
+func (m *OutboxMessage) ToEvent() (Event, error) {
+	return UnmarshalEvent(m.EventID, m.EventType, m.Version, m.Payload, m.OccurredAt)
+}
+
+type OutboxRepository interface {
+	// Save stores the messages, joining the transaction carried by ctx if any
+	Save(ctx context.Context, messages ...OutboxMessage) error
+	// FetchPending locks up to limit pending messages for the transaction carried by ctx
+	FetchPending(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, lastError string, giveUp bool) error
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,15 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessedEventRepository is the ledger of events each handler has already handled
+type ProcessedEventRepository interface {
+	// MarkProcessed records the event as handled by the handler and reports
+	// whether this is the first time; it joins the transaction carried by ctx
+	MarkProcessed(ctx context.Context, eventID string, handler string) (bool, error)
+	// DeleteProcessedBefore forgets the events handled before the given time and returns how many entries were removed
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,182 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// InitialVersion is the schema version of a payload that has never changed.
+// Events persisted before versioning existed are read as this version.
+const InitialVersion = 1
+
+// PayloadFactory returns an empty payload, ready to be unmarshalled into
+type PayloadFactory func() Payload
+
+// Upcaster rewrites a serialized payload from one schema version to the next
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+type registration struct {
+	factory   PayloadFactory
+	version   int
+	upcasters map[int]Upcaster
+}
+
+// Registry maps event type names to their payloads and schema versions
+type Registry struct {
+	mu            sync.RWMutex
+	registrations map[Type]*registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		registrations: make(map[Type]*registration),
+	}
+}
+
+// Register declares the payload of the event type and its current schema version
+func (r *Registry) Register(eventType Type, version int, factory PayloadFactory) error {
+	if version < InitialVersion {
+		return fmt.Errorf("invalid version %d for event type %s", version, eventType)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.registrations[eventType]; ok {
+		return fmt.Errorf("event type %s is already registered", eventType)
+	}
+	r.registrations[eventType] = &registration{
+		factory:   factory,
+		version:   version,
+		upcasters: make(map[int]Upcaster),
+	}
+	return nil
+}
+
+// RegisterUpcaster declares how to upgrade a payload of the event type from fromVersion to fromVersion+1
+func (r *Registry) RegisterUpcaster(eventType Type, fromVersion int, upcaster Upcaster) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reg, ok := r.registrations[eventType]
+	if !ok {
+		return fmt.Errorf("event type %s is not registered", eventType)
+	}
+	if fromVersion < InitialVersion || fromVersion >= reg.version {
+		return fmt.Errorf("invalid upcaster version %d for event type %s at version %d", fromVersion, eventType, reg.version)
+	}
+	reg.upcasters[fromVersion] = upcaster
+	return nil
+}
+
+// IsRegistered reports whether the event type is known to the registry
+func (r *Registry) IsRegistered(eventType Type) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.registrations[eventType]
+	return ok
+}
+
+// Types returns every registered event type, sorted by name
+func (r *Registry) Types() []Type {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]Type, 0, len(r.registrations))
+	for eventType := range r.registrations {
+		types = append(types, eventType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Version returns the current schema version of the event type's payload
+func (r *Registry) Version(eventType Type) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if reg, ok := r.registrations[eventType]; ok {
+		return reg.version
+	}
+	return InitialVersion
+}
+
+// Decode upcasts the payload serialized at version to the current schema and unmarshals it
+func (r *Registry) Decode(eventType Type, version int, data []byte) (Payload, error) {
+	r.mu.RLock()
+	reg, ok := r.registrations[eventType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no payload registered for event type %s", eventType)
+	}
+	if version == 0 {
+		version = InitialVersion
+	}
+	if version > reg.version {
+		return nil, fmt.Errorf("payload version %d of event type %s is newer than supported version %d", version, eventType, reg.version)
+	}
+
+	raw := json.RawMessage(data)
+	for v := version; v < reg.version; v++ {
+		upcaster, ok := reg.upcasters[v]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for event type %s from version %d", eventType, v)
+		}
+		upcasted, err := upcaster(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast event type %s from version %d: %w", eventType, v, err)
+		}
+		raw = upcasted
+	}
+
+	payload := reg.factory()
+	if err := json.Unmarshal(raw, payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload of event type %s: %w", eventType, err)
+	}
+	return payload, nil
+}
+
+// DefaultRegistry knows every event type of the application
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	mustRegister(TypeUserCreated, 1, func() Payload { return &UserPayload{} })
+	mustRegister(TypeReviewCreated, 1, func() Payload { return &ReviewPayload{} })
+	mustRegister(TypeReviewModified, 1, func() Payload { return &ReviewPayload{} })
+	mustRegister(TypeReviewDeleted, 1, func() Payload { return &ReviewPayload{} })
+	mustRegister(TypeReviewActionPosted, 1, func() Payload { return &ReviewActionPayload{} })
+	mustRegister(TypeReviewActionDeleted, 1, func() Payload { return &ReviewActionPayload{} })
+	mustRegister(TypeCourseWatched, 1, func() Payload { return &CoursePayload{} })
+	mustRegister(TypeCourseUnwatched, 1, func() Payload { return &CoursePayload{} })
+	mustRegister(TypeCourseEnrolled, 1, func() Payload { return &CoursePayload{} })
+}
+
+func mustRegister(eventType Type, version int, factory PayloadFactory) {
+	if err := DefaultRegistry.Register(eventType, version, factory); err != nil {
+		panic(err)
+	}
+}
+
+// IsRegistered reports whether the event type is known to the default registry
+func IsRegistered(eventType Type) bool {
+	return DefaultRegistry.IsRegistered(eventType)
+}
+
+// RegisteredTypes returns every event type of the default registry
+func RegisteredTypes() []Type {
+	return DefaultRegistry.Types()
+}
+
+// PayloadVersion returns the current schema version of the event type in the default registry
+func PayloadVersion(eventType Type) int {
+	return DefaultRegistry.Version(eventType)
+}
+
+// DecodePayload decodes a payload with the default registry
+func DecodePayload(eventType Type, version int, data []byte) (Payload, error) {
+	return DefaultRegistry.Decode(eventType, version, data)
+}
@@ -0,0 +1,113 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// renamedPayload stands for a payload whose "text" field was renamed to "content" in version 2
+type renamedPayload struct {
+	Content string `json:"content"`
+}
+
+func (p *renamedPayload) Type() Type {
+	return "test.renamed"
+}
+
+func newRenamedRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	assert.NoError(t, registry.Register("test.renamed", 2, func() Payload { return &renamedPayload{} }))
+	assert.NoError(t, registry.RegisterUpcaster("test.renamed", 1, func(data json.RawMessage) (json.RawMessage, error) {
+		var v1 map[string]any
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"content": v1["text"]})
+	}))
+	return registry
+}
+
+func TestFromJSON_RoundTrip(t *testing.T) {
+	original := NewBaseEvent(TypeReviewCreated, &ReviewPayload{ReviewID: 1, UserID: 2, Action: "created"})
+	data, err := original.ToJSON()
+	assert.NoError(t, err)
+
+	restored, err := FromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, original.ID(), restored.ID())
+	assert.Equal(t, TypeReviewCreated, restored.Type())
+	assert.True(t, original.Timestamp().Equal(restored.Timestamp()))
+	assert.Equal(t, &ReviewPayload{ReviewID: 1, UserID: 2, Action: "created"}, restored.Payload())
+}
+
+func TestFromJSON_UnversionedEventIsInitialVersion(t *testing.T) {
+	data := []byte(`{"id":"e1","type":"review.modified","payload":{"review_id":5},"timestamp":"2024-01-01T00:00:00Z"}`)
+
+	restored, err := FromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ReviewPayload{ReviewID: 5}, restored.Payload())
+}
+
+func TestRegistry_DecodeUpcastsOldVersions(t *testing.T) {
+	registry := newRenamedRegistry(t)
+
+	payload, err := registry.Decode("test.renamed", 1, []byte(`{"text":"hello"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &renamedPayload{Content: "hello"}, payload)
+}
+
+func TestRegistry_DecodeCurrentVersion(t *testing.T) {
+	registry := newRenamedRegistry(t)
+
+	payload, err := registry.Decode("test.renamed", 2, []byte(`{"content":"hello"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &renamedPayload{Content: "hello"}, payload)
+}
+
+func TestRegistry_DecodeErrors(t *testing.T) {
+	registry := newRenamedRegistry(t)
+
+	_, err := registry.Decode("test.unknown", 1, []byte(`{}`))
+	assert.Error(t, err)
+
+	_, err = registry.Decode("test.renamed", 3, []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestRegistry_RegisterRejectsDuplicates(t *testing.T) {
+	registry := newRenamedRegistry(t)
+
+	assert.Error(t, registry.Register("test.renamed", 1, func() Payload { return &renamedPayload{} }))
+	assert.Error(t, registry.RegisterUpcaster("test.renamed", 2, nil))
+}
+
+func TestDefaultRegistry_Catalogue(t *testing.T) {
+	payloads := []Payload{
+		&UserPayload{UserID: 1},
+		&ReviewPayload{ReviewID: 1, Action: "created"},
+		&ReviewPayload{ReviewID: 1, Action: "modified"},
+		&ReviewPayload{ReviewID: 1, Action: "deleted"},
+		&ReviewActionPayload{ActionID: 1, ActionType: "like", Action: "posted"},
+		&ReviewActionPayload{ActionID: 1, ActionType: "like", Action: "deleted"},
+		&CoursePayload{CourseID: 1, Action: "watched"},
+		&CoursePayload{CourseID: 1, Action: "unwatched"},
+		&CoursePayload{CourseID: 1, Action: "enrolled"},
+	}
+	assert.Len(t, RegisteredTypes(), len(payloads))
+
+	for _, payload := range payloads {
+		e := NewBaseEvent(payload.Type(), payload)
+		data, err := e.ToJSON()
+		assert.NoError(t, err)
+
+		restored, err := FromJSON(data)
+		assert.NoError(t, err, payload.Type())
+		assert.Equal(t, payload, restored.Payload())
+	}
+}
@@ -6,12 +6,47 @@ type ReviewPayload struct {
 	CourseID int    `json:"course_id"`
 	Rating   int    `json:"rating"`
 	Content  string `json:"content"`
-	Action   string `json:"action"` // "created" or "modified"
+	Action   string `json:"action"` // "created", "modified" or "deleted"
 }
 
 func (p *ReviewPayload) Type() Type {
-	if p.Action == "modified" {
+	switch p.Action {
+	case "modified":
 		return TypeReviewModified
+	case "deleted":
+		return TypeReviewDeleted
+	default:
+		return TypeReviewCreated
+	}
+}
+
+func (p *ReviewPayload) Aggregates() []AggregateRef {
+	return []AggregateRef{
+		{Type: AggregateReview, ID: p.ReviewID},
+		{Type: AggregateUser, ID: p.UserID},
+		{Type: AggregateCourse, ID: p.CourseID},
+	}
+}
+
+// ReviewActionPayload describes a reaction such as a like on a review
+type ReviewActionPayload struct {
+	ActionID   int    `json:"action_id"`
+	ReviewID   int    `json:"review_id"`
+	UserID     int    `json:"user_id"`
+	ActionType string `json:"action_type"`
+	Action     string `json:"action"` // "posted" or "deleted"
+}
+
+func (p *ReviewActionPayload) Type() Type {
+	if p.Action == "deleted" {
+		return TypeReviewActionDeleted
+	}
+	return TypeReviewActionPosted
+}
+
+func (p *ReviewActionPayload) Aggregates() []AggregateRef {
+	return []AggregateRef{
+		{Type: AggregateReview, ID: p.ReviewID},
+		{Type: AggregateUser, ID: p.UserID},
 	}
-	return TypeReviewCreated
 }
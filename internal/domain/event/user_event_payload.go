@@ -0,0 +1,15 @@
+package event
+
+// UserPayload only identifies the user: events are kept and sent to webhooks long after
+// an account is deleted, so they must not carry personal data
+type UserPayload struct {
+	UserID int `json:"user_id"`
+}
+
+func (p *UserPayload) Type() Type {
+	return TypeUserCreated
+}
+
+func (p *UserPayload) Aggregates() []AggregateRef {
+	return []AggregateRef{{Type: AggregateUser, ID: p.UserID}}
+}
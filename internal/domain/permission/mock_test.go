@@ -2,6 +2,7 @@ package permission
 
 import (
 	"context"
+	"time"
 
 	"jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/domain/common"
@@ -59,3 +60,43 @@ func (m *MockUserRepository) Update(ctx context.Context, user *auth.User) error
 	m.users[user.ID] = user
 	return nil
 }
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string, at time.Time) error {
+	if user, ok := m.users[userID]; ok {
+		user.Password = passwordHash
+		user.UpdatedAt = at
+	}
+	return nil
+}
+
+func (m *MockUserRepository) TouchLastSeen(ctx context.Context, userID int, at time.Time) error {
+	if user, ok := m.users[userID]; ok {
+		user.LastSeenAt = at
+	}
+	return nil
+}
+
+func (m *MockUserRepository) ChangeEmail(ctx context.Context, userID int, email string) (bool, error) {
+	if user, ok := m.users[userID]; ok {
+		user.Email = email
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *MockUserRepository) FindDeletionDue(ctx context.Context, before time.Time) ([]auth.User, error) {
+	return nil, nil
+}
+
+func (m *MockUserRepository) Anonymize(ctx context.Context, user *auth.User) error {
+	m.users[user.ID] = user
+	return nil
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, filter auth.UserSearchFilter) ([]auth.User, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockUserRepository) LiftExpiredSuspensions(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
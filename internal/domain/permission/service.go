@@ -14,6 +14,11 @@ type permissionService struct {
 }
 
 func (p *permissionService) CheckPermission(commonCtx *common.CommonContext, ref ResourceRef, action Action) (Result, error) {
+	// Requests made with an API token are further limited to the token's scopes
+	if scope := requiredScope(ref.Type, action); !commonCtx.User.HasScope(scope) {
+		return Result{Allow: false, Reason: "token scope " + string(scope) + " required"}, nil
+	}
+
 	switch ref.Type {
 	case ResourceTypeReview:
 		return p.checkReviewPermission(commonCtx, ref, action)
@@ -146,6 +151,29 @@ func (p *permissionService) checkCoursePermission(commonCtx *common.CommonContex
 	}
 }
 
+// requiredScope returns the API token scope covering the action on the resource type
+func requiredScope(resourceType ResourceType, action Action) common.Scope {
+	switch resourceType {
+	case ResourceTypeReview, ResourceTypeReviewAction:
+		if action == ActionView {
+			return common.ScopeReviewRead
+		}
+		return common.ScopeReviewWrite
+	case ResourceTypeUser:
+		if action == ActionView {
+			return common.ScopeUserRead
+		}
+		return common.ScopeUserWrite
+	case ResourceTypePoint:
+		return common.ScopeAdminPoints
+	default:
+		if action == ActionView {
+			return common.ScopeCourseRead
+		}
+		return common.ScopeCourseWrite
+	}
+}
+
 func NewPermissionService(userRepo auth.UserRepository) PermissionService {
 	return &permissionService{
 		userRepo: userRepo,
@@ -252,3 +252,23 @@ func TestPermissionService_CheckCoursePermission(t *testing.T) {
 		})
 	}
 }
+
+func TestPermissionService_CheckTokenScopes(t *testing.T) {
+	permissionService := NewPermissionService(NewMockUserRepository())
+	token := &common.User{UserID: 2, Role: common.RoleUser, APITokenID: 1, Scopes: []common.Scope{common.ScopeReviewRead}}
+	commonCtx := common.NewCommonContext(context.Background(), token)
+
+	result, err := permissionService.CheckPermission(commonCtx, NewReviewResourceRef(1, 2), ActionView)
+	assert.NoError(t, err)
+	assert.True(t, result.Allow)
+
+	result, err = permissionService.CheckPermission(commonCtx, NewReviewResourceRef(1, 2), ActionUpdate)
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Allow: false, Reason: "token scope review:write required"}, result)
+
+	// Scopes never grant more than the role does
+	admin := &common.User{UserID: 2, Role: common.RoleUser, APITokenID: 1, Scopes: []common.Scope{common.ScopeAdminPoints}}
+	result, err = permissionService.CheckPermission(common.NewCommonContext(context.Background(), admin), NewPointResourceRef(), ActionCreate)
+	assert.NoError(t, err)
+	assert.False(t, result.Allow)
+}
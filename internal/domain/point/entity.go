@@ -7,6 +7,7 @@ type UserPointRecord struct {
 	UserID      int
 	Point       int
 	Description string
+	ReviewID    int
 	CreatedAt   time.Time
 }
 
@@ -23,3 +24,9 @@ func NewUserPointRecord(userID int, point int, description string) UserPointReco
 		CreatedAt:   time.Now(),
 	}
 }
+
+func NewReviewPointRecord(userID int, reviewID int, point int, description string) UserPointRecord {
+	record := NewUserPointRecord(userID, point, description)
+	record.ReviewID = reviewID
+	return record
+}
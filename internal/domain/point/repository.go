@@ -8,4 +8,6 @@ type UserPointRepository interface {
 	GetUserAllPoints(ctx context.Context, userID int) (*UserPoint, error)
 	GetPointRecord(ctx context.Context, itemID int) (*UserPointRecord, error)
 	Save(ctx context.Context, point *UserPointRecord) error
+	// SumReviewPoints returns the net points the user currently holds for the review
+	SumReviewPoints(ctx context.Context, userID int, reviewID int) (int, error)
 }
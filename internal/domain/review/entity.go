@@ -72,6 +72,9 @@ type Review struct {
 	Semester Semester
 	Grade    string // 成绩
 
+	// LikeCount is the number of like actions on the review
+	LikeCount int
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -0,0 +1,123 @@
+package review
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+)
+
+// ReviewSort orders a review list. Every order breaks ties by review ID, so it is total
+// and a cursor marks a stable position in it.
+type ReviewSort string
+
+const (
+	ReviewSortNewest    ReviewSort = "newest"
+	ReviewSortOldest    ReviewSort = "oldest"
+	ReviewSortRating    ReviewSort = "rating"
+	ReviewSortMostLiked ReviewSort = "most_liked"
+)
+
+// MaxReviewPageSize bounds the page size a client can ask for
+const MaxReviewPageSize = 100
+
+var (
+	ErrInvalidReviewSort   = errors.New("unknown review sort order")
+	ErrInvalidReviewCursor = errors.New("invalid review cursor")
+)
+
+func (s ReviewSort) IsValid() bool {
+	switch s {
+	case ReviewSortNewest, ReviewSortOldest, ReviewSortRating, ReviewSortMostLiked:
+		return true
+	}
+	return false
+}
+
+// ReviewCursor is the position of a review in a list, holding the keys the list is sorted by
+type ReviewCursor struct {
+	Sort      ReviewSort `json:"s"`
+	ID        int        `json:"id"`
+	CreatedAt time.Time  `json:"t,omitempty"`
+	Rating    int        `json:"r,omitempty"`
+	LikeCount int        `json:"l,omitempty"`
+}
+
+func NewReviewCursor(sort ReviewSort, r *Review) ReviewCursor {
+	cursor := ReviewCursor{Sort: sort, ID: r.ID}
+	switch sort {
+	case ReviewSortRating:
+		cursor.Rating = r.Rating.Int()
+	case ReviewSortMostLiked:
+		cursor.LikeCount = r.LikeCount
+	default:
+		cursor.CreatedAt = r.CreatedAt
+	}
+	return cursor
+}
+
+// Encode returns the cursor as an opaque string for clients
+func (c ReviewCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeReviewCursor(encoded string) (*ReviewCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidReviewCursor
+	}
+	var cursor ReviewCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || !cursor.Sort.IsValid() || cursor.ID <= 0 {
+		return nil, ErrInvalidReviewCursor
+	}
+	return &cursor, nil
+}
+
+// ReviewListOptions selects a page of a review list: by page number, or after the cursor of the previous page
+type ReviewListOptions struct {
+	// Sort defaults to newest first
+	Sort   ReviewSort
+	Cursor string
+	// Pagination sets the page size; its page number is ignored when continuing from a cursor
+	Pagination common.Pagination
+}
+
+// Apply sets the order and the page on the filter; a cursor only continues the order it was made in
+func (o ReviewListOptions) Apply(filter *ReviewFilter) error {
+	filter.Sort = o.Sort
+	if filter.Sort == "" {
+		filter.Sort = ReviewSortNewest
+	}
+	if !filter.Sort.IsValid() {
+		return ErrInvalidReviewSort
+	}
+
+	pagination := common.NewPagination(o.Pagination.Page, o.Pagination.Size)
+	if pagination.Size > MaxReviewPageSize {
+		pagination.Size = MaxReviewPageSize
+	}
+	filter.Pagination = &pagination
+
+	if o.Cursor != "" {
+		cursor, err := DecodeReviewCursor(o.Cursor)
+		if err != nil {
+			return err
+		}
+		if cursor.Sort != filter.Sort {
+			return ErrInvalidReviewCursor
+		}
+		filter.After = cursor
+	}
+	return nil
+}
+
+// NextReviewCursor returns the cursor of the page after reviews, or "" when the page was not full
+func NextReviewCursor(filter ReviewFilter, reviews []Review) string {
+	if filter.Pagination == nil || len(reviews) == 0 || len(reviews) < filter.Pagination.Size {
+		return ""
+	}
+	return NewReviewCursor(filter.Sort, &reviews[len(reviews)-1]).Encode()
+}
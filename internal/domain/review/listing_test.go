@@ -0,0 +1,57 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/common"
+)
+
+func TestReviewCursor_RoundTrip(t *testing.T) {
+	r := &Review{ID: 7, Rating: NewRating(4), LikeCount: 3, CreatedAt: time.Unix(1700000000, 0).UTC()}
+
+	for _, sort := range []ReviewSort{ReviewSortNewest, ReviewSortOldest, ReviewSortRating, ReviewSortMostLiked} {
+		cursor := NewReviewCursor(sort, r)
+		decoded, err := DecodeReviewCursor(cursor.Encode())
+		assert.NoError(t, err)
+		assert.Equal(t, cursor, *decoded)
+	}
+}
+
+func TestDecodeReviewCursor_Invalid(t *testing.T) {
+	for _, encoded := range []string{"not base64!", "bm90IGpzb24", ReviewCursor{Sort: "popular", ID: 1}.Encode(), ReviewCursor{Sort: ReviewSortNewest}.Encode()} {
+		_, err := DecodeReviewCursor(encoded)
+		assert.ErrorIs(t, err, ErrInvalidReviewCursor)
+	}
+}
+
+func TestReviewListOptions_Apply(t *testing.T) {
+	var filter ReviewFilter
+	assert.NoError(t, ReviewListOptions{Pagination: common.Pagination{Page: 2, Size: 500}}.Apply(&filter))
+	assert.Equal(t, ReviewSortNewest, filter.Sort)
+	assert.Equal(t, &common.Pagination{Page: 2, Size: MaxReviewPageSize}, filter.Pagination)
+	assert.Nil(t, filter.After)
+
+	filter = ReviewFilter{}
+	assert.ErrorIs(t, ReviewListOptions{Sort: "popular"}.Apply(&filter), ErrInvalidReviewSort)
+
+	cursor := NewReviewCursor(ReviewSortRating, &Review{ID: 3, Rating: NewRating(5)})
+	filter = ReviewFilter{}
+	assert.NoError(t, ReviewListOptions{Sort: ReviewSortRating, Cursor: cursor.Encode()}.Apply(&filter))
+	assert.Equal(t, &cursor, filter.After)
+
+	// A cursor cannot continue a list in another order
+	filter = ReviewFilter{}
+	assert.ErrorIs(t, ReviewListOptions{Sort: ReviewSortNewest, Cursor: cursor.Encode()}.Apply(&filter), ErrInvalidReviewCursor)
+}
+
+func TestNextReviewCursor(t *testing.T) {
+	filter := ReviewFilter{Sort: ReviewSortMostLiked, Pagination: &common.Pagination{Page: 1, Size: 2}}
+	reviews := []Review{{ID: 9, LikeCount: 5}, {ID: 4, LikeCount: 2}}
+
+	assert.Equal(t, NewReviewCursor(ReviewSortMostLiked, &reviews[1]).Encode(), NextReviewCursor(filter, reviews))
+	assert.Empty(t, NextReviewCursor(filter, reviews[:1]))
+	assert.Empty(t, NextReviewCursor(ReviewFilter{Sort: ReviewSortNewest}, reviews))
+}
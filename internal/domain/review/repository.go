@@ -1,6 +1,10 @@
 package review
 
-import "context"
+import (
+	"context"
+
+	"jcourse_go/internal/domain/common"
+)
 
 type ReviewFilter struct {
 	ReviewID      *int
@@ -9,16 +13,26 @@ type ReviewFilter struct {
 	MainTeacherID *int
 	Semester      *string
 	Rating        *int
+
+	// Sort defaults to newest first
+	Sort ReviewSort
+	// After continues the list after the cursor instead of at the page offset
+	After *ReviewCursor
+	// Pagination limits the result to a page; nil returns every matching review
+	Pagination *common.Pagination
 }
 
 type ReviewRepository interface {
 	Get(ctx context.Context, id int) (*Review, error)
 	FindBy(ctx context.Context, filter ReviewFilter) ([]Review, error)
+	// CountBy counts the reviews matching the filter, regardless of its page
+	CountBy(ctx context.Context, filter ReviewFilter) (int64, error)
 	Save(ctx context.Context, review *Review, revision *ReviewRevision) error
 	Delete(ctx context.Context, filter ReviewFilter) error
 	SaveReviewAction(ctx context.Context, action *ReviewAction) error
 	DeleteReviewAction(ctx context.Context, actionID int) error
 	GetReviewAction(ctx context.Context, actionID int) (*ReviewAction, error)
+	GetUserReviewActions(ctx context.Context, userID int) ([]ReviewAction, error)
 	GetReviewRevisions(ctx context.Context, reviewID int) ([]ReviewRevision, error)
 }
 
@@ -44,5 +58,6 @@ type CourseRepository interface {
 	GetCategories(ctx context.Context) ([]string, error)
 	GetUserEnrolledCourses(ctx context.Context, userID int) ([]int, error)
 	AddUserEnrolledCourse(ctx context.Context, userID int, courseID int) error
-	WatchCourse(ctx context.Context, userID int, courseID int, watch bool) error
+	// WatchCourse watches or unwatches the course and reports whether that changed anything
+	WatchCourse(ctx context.Context, userID int, courseID int, watch bool) (bool, error)
 }
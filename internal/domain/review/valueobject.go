@@ -39,6 +39,9 @@ const (
 	MaxRating = 5
 )
 
+// ActionTypeLike is the review action of a user liking a review
+const ActionTypeLike = "like"
+
 type ReviewContent struct {
 	Comment  string
 	Rating   int
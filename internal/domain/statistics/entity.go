@@ -14,12 +14,19 @@ type DailyStatistics struct {
 	TotalReviews       int       `json:"total_reviews"`
 	TotalCourses       int       `json:"total_courses"`
 	CoursesWithReviews int       `json:"courses_with_reviews"`
+	DailyLikes         int       `json:"daily_likes"`
 }
 
+// CounterLikes counts the review likes of a day, net of likes withdrawn
+const CounterLikes = "likes"
+
 type StatisticsRepository interface {
 	GetCurrentStatistics(ctx context.Context) (*DailyStatistics, error)
 	GetDailyStatistics(ctx context.Context, date time.Time) (*DailyStatistics, error)
 	GetDailyStatisticsRange(ctx context.Context, startDate, endDate time.Time) ([]*DailyStatistics, error)
 	SaveDailyStatistics(ctx context.Context, stats *DailyStatistics) error
 	GetLatestDailyStatistics(ctx context.Context) (*DailyStatistics, error)
+	// IncrementDailyCounter adds delta to today's value of the named counter, today being the database's
+	// CURRENT_DATE that the statistics are read with
+	IncrementDailyCounter(ctx context.Context, name string, delta int) error
 }
@@ -0,0 +1,16 @@
+package webhook
+
+type CreateSubscriptionCommand struct {
+	EventType string `json:"event_type" binding:"required"`
+	URL       string `json:"url" binding:"required"`
+	Secret    string `json:"secret" binding:"required"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// UpdateSubscriptionCommand changes the given fields only; an empty secret keeps the current one
+type UpdateSubscriptionCommand struct {
+	SubscriptionID int64   `json:"-"`
+	URL            *string `json:"url"`
+	Secret         *string `json:"secret"`
+	Enabled        *bool   `json:"enabled"`
+}
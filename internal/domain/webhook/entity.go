@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/event"
+)
+
+// Subscription sends every event of one type to a downstream URL
+type Subscription struct {
+	ID        int64
+	EventType event.Type
+	URL       string
+	Secret    string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one event sent to one subscription, together with the outcome of its latest attempt
+type Delivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        string
+	EventType      event.Type
+	Body           []byte
+
+	Status        DeliveryStatus
+	Attempts      int
+	ResponseCode  int
+	LastError     string
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// IsSuccessCode reports whether the receiver accepted the delivery
+func IsSuccessCode(code int) bool {
+	return code >= 200 && code < 300
+}
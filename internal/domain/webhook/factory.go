@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"time"
+
+	"jcourse_go/internal/domain/event"
+)
+
+func NewSubscription(cmd *CreateSubscriptionCommand) Subscription {
+	return Subscription{
+		EventType: event.Type(cmd.EventType),
+		URL:       cmd.URL,
+		Secret:    cmd.Secret,
+		Enabled:   cmd.Enabled,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func NewDelivery(subscriptionID int64, e event.Event) (Delivery, error) {
+	body, err := e.ToJSON()
+	if err != nil {
+		return Delivery{}, err
+	}
+	return Delivery{
+		SubscriptionID: subscriptionID,
+		EventID:        e.ID(),
+		EventType:      e.Type(),
+		Body:           body,
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}, nil
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+)
+
+type SubscriptionRepository interface {
+	Get(ctx context.Context, id int64) (*Subscription, error)
+	FindAll(ctx context.Context) ([]Subscription, error)
+	FindEnabledByEventType(ctx context.Context, eventType event.Type) ([]Subscription, error)
+	Save(ctx context.Context, subscription *Subscription) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type DeliveryRepository interface {
+	Save(ctx context.Context, deliveries ...Delivery) error
+	// ClaimDue leases up to limit pending deliveries whose next attempt is due by moving that attempt to leaseUntil,
+	// so other workers skip them until then
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]Delivery, error)
+	Update(ctx context.Context, delivery *Delivery) error
+	FindBySubscription(ctx context.Context, subscriptionID int64, pagination common.Pagination) ([]Delivery, int64, error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader  = "X-Jcourse-Signature"
+	TimestampHeader  = "X-Jcourse-Timestamp"
+	EventTypeHeader  = "X-Jcourse-Event"
+	DeliveryIDHeader = "X-Jcourse-Delivery"
+	// SignatureTolerance is how far the timestamp of a delivery may be from the receiver's clock
+	SignatureTolerance = 5 * time.Minute
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the signature header value of a delivery sent at timestamp, in Unix seconds:
+// "sha256=" followed by the hex HMAC-SHA256 of the timestamp, a dot and the body.
+// The timestamp is signed so that receivers can refuse replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp header values in constant time, refusing deliveries signed
+// more than SignatureTolerance from now; receivers can use it to authenticate deliveries
+func Verify(secret string, body []byte, timestamp, signature string, now time.Time) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sentAt, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, sentAt, body)), []byte(signature))
+}
+
+// IsForbiddenAddress reports whether deliveries must not be sent to the address: loopback, private,
+// link-local, multicast and unspecified addresses would let subscribers reach the internal network
+func IsForbiddenAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified()
+}
+
+// Request is a signed delivery attempt
+type Request struct {
+	DeliveryID int64
+	URL        string
+	Secret     string
+	EventType  string
+	Body       []byte
+}
+
+// Sender posts deliveries to subscribers, returning the response status code
+type Sender interface {
+	Send(ctx context.Context, req Request) (int, error)
+}
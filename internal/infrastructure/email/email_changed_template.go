@@ -0,0 +1,114 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+
+	"jcourse_go/internal/domain/email"
+)
+
+const emailChangedTitle = "登录邮箱已更换"
+
+type EmailChangedTemplate struct{}
+
+func NewEmailChangedTemplate() email.EmailChangedTemplate {
+	return &EmailChangedTemplate{}
+}
+
+func (t *EmailChangedTemplate) Execute(ctx context.Context, input *email.EmailChangedNotice) email.RenderedEmail {
+	const templateContent = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+        .container {
+            border: 1px solid #ddd;
+            border-radius: 5px;
+            padding: 20px;
+            background-color: #f9f9f9;
+        }
+        .header {
+            text-align: center;
+            margin-bottom: 20px;
+        }
+        .action {
+            text-align: center;
+            margin: 20px 0;
+        }
+        .action a {
+            display: inline-block;
+            padding: 10px 20px;
+            color: #fff;
+            background-color: #dc3545;
+            border-radius: 5px;
+            text-decoration: none;
+        }
+        .footer {
+            margin-top: 20px;
+            font-size: 12px;
+            color: #666;
+            text-align: center;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>{{.Title}}</h2>
+        </div>
+        <p>您好！</p>
+        <p>您的账号登录邮箱已更换为 {{.NewEmail}}，其他设备上的登录已被注销。</p>
+        <p>如果这不是您本人的操作，请在 {{.ExpiresAt}} 前点击下方链接撤销更换：</p>
+        <div class="action"><a href="{{.RevertURL}}">撤销更换</a></div>
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿回复。</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	fallback := email.RenderedEmail{
+		Title: emailChangedTitle,
+		Body:  fmt.Sprintf("您的账号登录邮箱已更换为 %s。如果这不是您本人的操作，请访问以下链接撤销更换：%s", input.NewEmail, input.RevertURL),
+	}
+
+	tmpl, err := template.New("email_changed").Parse(templateContent)
+	if err != nil {
+		return fallback
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		Title     string
+		NewEmail  string
+		RevertURL string
+		ExpiresAt string
+	}{
+		Title:     emailChangedTitle,
+		NewEmail:  input.NewEmail,
+		RevertURL: input.RevertURL,
+		ExpiresAt: input.ExpiresAt.Format("2006-01-02 15:04"),
+	}
+	if err := tmpl.Execute(&body, data); err != nil {
+		return fallback
+	}
+
+	return email.RenderedEmail{
+		Title: emailChangedTitle,
+		Body:  body.String(),
+	}
+}
@@ -16,14 +16,27 @@ func NewVerificationCodeTemplate() email.Template {
 	return &VerificationCodeTemplate{}
 }
 
+// verificationCodeTitle names the flow the code was sent for
+func verificationCodeTitle(purpose auth.CodePurpose) string {
+	switch purpose {
+	case auth.CodePurposeResetPassword:
+		return "重置密码验证码"
+	case auth.CodePurposeChangeEmail:
+		return "更换邮箱验证码"
+	default:
+		return "验证码"
+	}
+}
+
 func (t *VerificationCodeTemplate) Execute(ctx context.Context, input *auth.VerificationCode) email.RenderedEmail {
+	title := verificationCodeTitle(input.Purpose)
 	const templateContent = `
 <!DOCTYPE html>
 <html>
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>验证码</title>
+    <title>{{.Title}}</title>
     <style>
         body {
             font-family: Arial, sans-serif;
@@ -64,7 +77,7 @@ func (t *VerificationCodeTemplate) Execute(ctx context.Context, input *auth.Veri
 <body>
     <div class="container">
         <div class="header">
-            <h2>验证码</h2>
+            <h2>{{.Title}}</h2>
         </div>
         <p>您好！</p>
         <p>您的验证码是：</p>
@@ -83,22 +96,26 @@ func (t *VerificationCodeTemplate) Execute(ctx context.Context, input *auth.Veri
 	if err != nil {
 		// Fallback to simple format if template parsing fails
 		return email.RenderedEmail{
-			Title: "验证码",
+			Title: title,
 			Body:  fmt.Sprintf("您的验证码是：%s\n验证码有效期为 5 分钟，请尽快使用。", input.Code),
 		}
 	}
 
 	var body bytes.Buffer
-	if err := tmpl.Execute(&body, input); err != nil {
+	data := struct {
+		Title string
+		Code  string
+	}{Title: title, Code: input.Code}
+	if err := tmpl.Execute(&body, data); err != nil {
 		// Fallback to simple format if template execution fails
 		return email.RenderedEmail{
-			Title: "验证码",
+			Title: title,
 			Body:  fmt.Sprintf("您的验证码是：%s\n验证码有效期为 5 分钟，请尽快使用。", input.Code),
 		}
 	}
 
 	return email.RenderedEmail{
-		Title: "验证码",
+		Title: title,
 		Body:  body.String(),
 	}
 }
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+)
+
+// APIToken is a personal access token of a user
+type APIToken struct {
+	ID     int    `gorm:"primaryKey"`
+	UserID int    `gorm:"not null;index"`
+	Name   string `gorm:"type:varchar(100);not null"`
+	// Token is the SHA-256 hex digest of the token handed to the user
+	Token string `gorm:"type:varchar(255);not null;uniqueIndex"`
+	// Scopes holds the comma separated scopes granted to the token
+	Scopes     string    `gorm:"type:text;not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+
+	// Relations
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for APIToken
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
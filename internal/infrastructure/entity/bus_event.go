@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+)
+
+// BusEvent represents an event broadcast to every replica through PostgreSQL notifications
+type BusEvent struct {
+	ID         int64     `gorm:"primaryKey"`
+	EventID    string    `gorm:"type:varchar(36);uniqueIndex;not null"`
+	EventType  string    `gorm:"type:varchar(100);not null"`
+	Version    int       `gorm:"not null;default:1"`
+	Payload    string    `gorm:"type:jsonb;not null"`
+	OccurredAt time.Time `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for BusEvent
+func (BusEvent) TableName() string {
+	return "bus_events"
+}
+
+// BusEventClaim represents a consumer group taking ownership of a bus event in the database.
+// HandledAt stays empty until the handlers of the claiming replica finished.
+type BusEventClaim struct {
+	BusEventID    int64     `gorm:"primaryKey;autoIncrement:false"`
+	ConsumerGroup string    `gorm:"type:varchar(100);primaryKey"`
+	ClaimedAt     time.Time `gorm:"index"`
+	HandledAt     *time.Time
+}
+
+// TableName specifies the table name for BusEventClaim
+func (BusEventClaim) TableName() string {
+	return "bus_event_claims"
+}
+
+// BusConsumerOffset represents the bus event up to which a consumer group handled every event
+type BusConsumerOffset struct {
+	ConsumerGroup string `gorm:"type:varchar(100);primaryKey"`
+	LastID        int64  `gorm:"not null"`
+	UpdatedAt     time.Time
+}
+
+// TableName specifies the table name for BusConsumerOffset
+func (BusConsumerOffset) TableName() string {
+	return "bus_consumer_offsets"
+}
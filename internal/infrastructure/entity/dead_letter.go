@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// DeadLetter represents a failed event delivery to a single handler in the database
+type DeadLetter struct {
+	ID         int64     `gorm:"primaryKey"`
+	EventID    string    `gorm:"type:varchar(36);not null;index"`
+	EventType  string    `gorm:"type:varchar(100);not null;index"`
+	Version    int       `gorm:"not null;default:1"`
+	Handler    string    `gorm:"type:varchar(255);not null;index"`
+	Payload    string    `gorm:"type:jsonb;not null"`
+	OccurredAt time.Time `gorm:"not null"`
+	Status     string    `gorm:"type:varchar(20);not null;default:'failed';index"`
+	Attempts   int       `gorm:"not null;default:0"`
+	LastError  string    `gorm:"type:text"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName specifies the table name for DeadLetter
+func (DeadLetter) TableName() string {
+	return "dead_letters"
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// EmailChange records a change of a user's login email until it can no longer be reverted
+type EmailChange struct {
+	ID       int    `gorm:"primaryKey"`
+	UserID   int    `gorm:"not null;index"`
+	OldEmail string `gorm:"type:varchar(100);not null"`
+	NewEmail string `gorm:"type:varchar(100);not null"`
+	// RevertToken is the SHA-256 hex digest of the token sent to the previous address
+	RevertToken string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	RevertedAt  *time.Time
+	CreatedAt   time.Time
+
+	// Relations
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for EmailChange
+func (EmailChange) TableName() string {
+	return "email_changes"
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+)
+
+// ExternalIdentity links an account of an identity provider to a user
+type ExternalIdentity struct {
+	ID        int    `gorm:"primaryKey"`
+	UserID    int    `gorm:"not null;index"`
+	Provider  string `gorm:"type:varchar(64);not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Subject   string `gorm:"type:varchar(255);not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Email     string `gorm:"type:varchar(100)"`
+	CreatedAt time.Time
+
+	// Relations
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for ExternalIdentity
+func (ExternalIdentity) TableName() string {
+	return "external_identities"
+}
+
+// OAuthState is an authorization request waiting for the identity provider's callback
+type OAuthState struct {
+	State        string    `gorm:"type:varchar(64);primaryKey"`
+	Provider     string    `gorm:"type:varchar(64);not null"`
+	Nonce        string    `gorm:"type:varchar(64);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// TableName specifies the table name for OAuthState
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+)
+
+// AuthFailure counts the consecutive failed logins of an email or an IP address
+type AuthFailure struct {
+	Key           string `gorm:"column:throttle_key;type:varchar(255);primaryKey"`
+	Failures      int    `gorm:"not null;default:0"`
+	LockedUntil   *time.Time
+	LastFailureAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for AuthFailure
+func (AuthFailure) TableName() string {
+	return "auth_failures"
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+)
+
+// InviteCode lets a limited number of people register
+type InviteCode struct {
+	ID        int    `gorm:"primaryKey"`
+	Code      string `gorm:"type:varchar(32);not null;uniqueIndex"`
+	MaxUses   int    `gorm:"not null"`
+	Uses      int    `gorm:"not null;default:0"`
+	ExpiresAt *time.Time
+	CreatedBy int `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for InviteCode
+func (InviteCode) TableName() string {
+	return "invite_codes"
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// OutboxMessage represents a domain event waiting to be relayed, stored in the database
+type OutboxMessage struct {
+	ID         int64     `gorm:"primaryKey;index:idx_outbox_status_id,priority:2"`
+	EventID    string    `gorm:"type:varchar(36);uniqueIndex;not null"`
+	EventType  string    `gorm:"type:varchar(100);not null"`
+	Version    int       `gorm:"not null;default:1"`
+	Payload    string    `gorm:"type:jsonb;not null"`
+	OccurredAt time.Time `gorm:"not null"`
+	Status     string    `gorm:"type:varchar(20);not null;default:'pending';index:idx_outbox_status_id,priority:1"`
+	Attempts   int       `gorm:"not null;default:0"`
+	LastError  string    `gorm:"type:text"`
+	SentAt     *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName specifies the table name for OutboxMessage
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+)
+
+// ProcessedEvent records that a handler has handled an event
+type ProcessedEvent struct {
+	EventID     string    `gorm:"type:varchar(36);primaryKey"`
+	Handler     string    `gorm:"type:varchar(255);primaryKey"`
+	ProcessedAt time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for ProcessedEvent
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+// RefreshToken renews the access tokens of a session in token mode
+type RefreshToken struct {
+	ID        int `gorm:"primaryKey"`
+	SessionID int `gorm:"not null;index"`
+	UserID    int `gorm:"not null;index"`
+	// Token is the SHA-256 hex digest of the token handed to the client
+	Token     string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for RefreshToken
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -16,6 +16,7 @@ type Review struct {
 	Content   string `gorm:"type:text;not null"`
 	Category  string `gorm:"type:varchar(50);not null"`
 	IsPublic  bool   `gorm:"not null;default:true"`
+	LikeCount int    `gorm:"not null;default:0"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
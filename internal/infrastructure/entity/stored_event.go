@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+)
+
+// StoredEvent represents a published event in the append-only event store
+type StoredEvent struct {
+	ID         int64                  `gorm:"primaryKey"`
+	EventID    string                 `gorm:"type:varchar(36);uniqueIndex;not null"`
+	EventType  string                 `gorm:"type:varchar(100);not null;index"`
+	Version    int                    `gorm:"not null;default:1"`
+	Payload    string                 `gorm:"type:jsonb;not null"`
+	OccurredAt time.Time              `gorm:"not null;index"`
+	StoredAt   time.Time              `gorm:"not null"`
+	Aggregates []StoredEventAggregate `gorm:"foreignKey:StoredEventID"`
+}
+
+// TableName specifies the table name for StoredEvent
+func (StoredEvent) TableName() string {
+	return "stored_events"
+}
+
+// StoredEventAggregate links a stored event to an entity it is about
+type StoredEventAggregate struct {
+	StoredEventID int64  `gorm:"primaryKey;autoIncrement:false"`
+	AggregateType string `gorm:"type:varchar(20);primaryKey;index:idx_stored_event_aggregates_aggregate,priority:1"`
+	AggregateID   int    `gorm:"primaryKey;autoIncrement:false;index:idx_stored_event_aggregates_aggregate,priority:2"`
+}
+
+// TableName specifies the table name for StoredEventAggregate
+func (StoredEventAggregate) TableName() string {
+	return "stored_event_aggregates"
+}
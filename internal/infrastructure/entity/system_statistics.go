@@ -15,6 +15,7 @@ type DailyStatistics struct {
 	TotalReviews       int       `gorm:"not null;default:0;comment:'Total Reviews'"`
 	TotalCourses       int       `gorm:"not null;default:0;comment:'Total Courses'"`
 	CoursesWithReviews int       `gorm:"not null;default:0;comment:'Courses with Reviews'"`
+	DailyLikes         int       `gorm:"not null;default:0;comment:'Daily Review Likes'"`
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 }
@@ -23,3 +24,15 @@ type DailyStatistics struct {
 func (DailyStatistics) TableName() string {
 	return "daily_statistics"
 }
+
+// DailyCounter is a running count of a day, kept up to date by event handlers
+type DailyCounter struct {
+	Date  time.Time `gorm:"type:date;primaryKey"`
+	Name  string    `gorm:"type:varchar(50);primaryKey"`
+	Value int       `gorm:"not null;default:0"`
+}
+
+// TableName specifies the table name for DailyCounter
+func (DailyCounter) TableName() string {
+	return "daily_counters"
+}
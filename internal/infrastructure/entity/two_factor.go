@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+)
+
+// UserTwoFactor is the TOTP second factor of a user
+type UserTwoFactor struct {
+	UserID int    `gorm:"primaryKey;autoIncrement:false"`
+	Secret string `gorm:"type:varchar(64);not null"`
+	// RecoveryCodes holds the comma separated SHA-256 hex digests of the unused recovery codes
+	RecoveryCodes string `gorm:"type:text"`
+	LastUsedStep  int64  `gorm:"not null;default:0"`
+	EnabledAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// Relations
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for UserTwoFactor
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// LoginChallenge is a login waiting for the second factor
+type LoginChallenge struct {
+	ID     int `gorm:"primaryKey"`
+	UserID int `gorm:"not null"`
+	// Token is the SHA-256 hex digest of the challenge token handed to the client
+	Token     string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	CreatedIP string    `gorm:"type:varchar(45)"`
+	UserAgent string    `gorm:"type:varchar(512)"`
+	Attempts  int       `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for LoginChallenge
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
@@ -14,9 +14,16 @@ type User struct {
 	PasswordHash string `gorm:"type:varchar(255);not null"`
 	Role         string `gorm:"type:varchar(20);not null;default:'user'"`
 	IsVerified   bool   `gorm:"not null;default:false"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	LastSeenAt   *time.Time
+	SuspendedAt  *time.Time
+	// SuspendedUntil ends a timed suspension
+	SuspendedUntil   *time.Time `gorm:"index"`
+	SuspensionReason string     `gorm:"type:varchar(255)"`
+	// DeletionScheduledAt is when the account will be anonymised
+	DeletionScheduledAt *time.Time `gorm:"index"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName specifies the table name for User
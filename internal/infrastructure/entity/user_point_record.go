@@ -13,6 +13,7 @@ type UserPointRecord struct {
 	Point       int    `gorm:"not null"`
 	Action      string `gorm:"type:varchar(50);not null"`
 	Description string `gorm:"type:text"`
+	ReviewID    *int   `gorm:"index"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
@@ -6,15 +6,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// MaxUserAgentLength is the longest user agent kept with a session
+const MaxUserAgentLength = 512
+
 // UserSession represents the user session entity in the database
 type UserSession struct {
-	ID        int    `gorm:"primaryKey"`
-	UserID    int    `gorm:"not null"`
-	Token     string `gorm:"type:varchar(255);not null"`
-	ExpiresAt time.Time
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID     int `gorm:"primaryKey"`
+	UserID int `gorm:"not null"`
+	// Token is the SHA-256 hex digest of the session token handed to the client
+	Token      string `gorm:"type:varchar(255);not null;uniqueIndex"`
+	CreatedIP  string `gorm:"type:varchar(45)"`
+	UserAgent  string `gorm:"type:varchar(512)"`
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
 
 	// Relations
 	User User `gorm:"foreignKey:UserID"`
@@ -9,8 +9,10 @@ import (
 // VerificationCode represents the verification code entity in the database
 type VerificationCode struct {
 	ID        int       `gorm:"primaryKey"`
-	Email     string    `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Email     string    `gorm:"type:varchar(100);uniqueIndex:idx_verification_codes_email_purpose;not null"`
+	Purpose   string    `gorm:"type:varchar(32);uniqueIndex:idx_verification_codes_email_purpose;not null;default:register"`
 	Code      string    `gorm:"type:varchar(10);not null"`
+	Attempts  int       `gorm:"not null;default:0"`
 	ExpiresAt time.Time `gorm:"not null"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+)
+
+// WebhookSubscription represents a webhook subscription in the database
+type WebhookSubscription struct {
+	ID        int64  `gorm:"primaryKey"`
+	EventType string `gorm:"type:varchar(100);not null;index"`
+	URL       string `gorm:"type:varchar(2048);not null"`
+	Secret    string `gorm:"type:varchar(255);not null"`
+	Enabled   bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery represents a webhook delivery and its latest attempt in the database
+type WebhookDelivery struct {
+	ID             int64     `gorm:"primaryKey"`
+	SubscriptionID int64     `gorm:"not null;index"`
+	EventID        string    `gorm:"type:varchar(36);not null"`
+	EventType      string    `gorm:"type:varchar(100);not null"`
+	Body           string    `gorm:"type:jsonb;not null"`
+	Status         string    `gorm:"type:varchar(20);not null;default:'pending';index:idx_webhook_delivery_due,priority:1"`
+	Attempts       int       `gorm:"not null;default:0"`
+	ResponseCode   int       `gorm:"not null;default:0"`
+	LastError      string    `gorm:"type:text"`
+	NextAttemptAt  time.Time `gorm:"not null;index:idx_webhook_delivery_due,priority:2"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TableName specifies the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,29 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+)
+
+// DispatchPublisher publishes events by dispatching them synchronously, so Publish only returns
+// once their handlers ran. The outbox relay uses it with the in-memory bus, whose Publish only
+// queues the events: they would be lost with the process after the outbox marked them sent.
+type DispatchPublisher struct {
+	bus event.EventBus
+}
+
+func NewDispatchPublisher(bus event.EventBus) *DispatchPublisher {
+	return &DispatchPublisher{bus: bus}
+}
+
+func (p *DispatchPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	// Handlers run in their own transactions rather than in the caller's
+	if err := p.bus.Dispatch(database.WithoutTx(ctx), events...); err != nil {
+		// The bus dead-letters the failed handlers, so the events still count as delivered
+		log.Printf("Handlers of dispatched events failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"jcourse_go/internal/config"
+	"jcourse_go/internal/domain/event"
+)
+
+const (
+	DefaultWorkers      = 4
+	DefaultQueueSize    = 1024
+	DefaultMaxRetries   = 3
+	DefaultRetryBackoff = 500 * time.Millisecond
+)
+
+var (
+	ErrBusClosed       = errors.New("event bus is closed")
+	ErrQueueFull       = errors.New("event queue is full")
+	ErrHandlerNotFound = errors.New("event handler not found")
+)
+
+// InMemoryEventBus is an in-process event bus backed by a bounded queue and a worker pool.
+// Published events are handled asynchronously; dispatched events are handled synchronously.
+type InMemoryEventBus struct {
+	mu       sync.RWMutex
+	handlers map[event.Type][]event.Handler
+	queue    chan event.Event
+
+	workers      int
+	maxRetries   int
+	retryBackoff time.Duration
+	deadLetters  event.DeadLetterSink
+
+	wg      sync.WaitGroup
+	started bool
+	closed  bool
+}
+
+func NewInMemoryEventBus(conf config.EventConfig) *InMemoryEventBus {
+	workers := conf.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	queueSize := conf.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	maxRetries := conf.MaxRetries
+	switch {
+	case maxRetries == 0:
+		maxRetries = DefaultMaxRetries
+	case maxRetries < 0:
+		maxRetries = 0
+	}
+	retryBackoff := conf.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = DefaultRetryBackoff
+	}
+
+	return &InMemoryEventBus{
+		handlers:     make(map[event.Type][]event.Handler),
+		queue:        make(chan event.Event, queueSize),
+		workers:      workers,
+		maxRetries:   maxRetries,
+		retryBackoff: retryBackoff,
+	}
+}
+
+func (b *InMemoryEventBus) Register(eventType event.Type, handler event.Handler) error {
+	if handler == nil {
+		return fmt.Errorf("cannot register nil handler for event type %s", eventType)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	return nil
+}
+
+func (b *InMemoryEventBus) SetDeadLetterSink(sink event.DeadLetterSink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.deadLetters = sink
+}
+
+// Dispatch delivers the events to their handlers synchronously, retrying each handler on failure
+func (b *InMemoryEventBus) Dispatch(ctx context.Context, events ...event.Event) error {
+	var errs []error
+	for _, e := range events {
+		if err := b.handle(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Publish enqueues the events for asynchronous handling; it never blocks on a full queue
+func (b *InMemoryEventBus) Publish(ctx context.Context, events ...event.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	for _, e := range events {
+		select {
+		case b.queue <- e:
+		default:
+			return fmt.Errorf("failed to publish event %s: %w", e.ID(), ErrQueueFull)
+		}
+	}
+	return nil
+}
+
+// Redeliver calls the named handler once, without retries or dead-lettering, so the caller sees the outcome
+func (b *InMemoryEventBus) Redeliver(ctx context.Context, e event.Event, handlerName string) error {
+	for _, h := range b.handlersFor(e.Type()) {
+		if event.HandlerName(h) == handlerName {
+			return safeHandle(ctx, h, e)
+		}
+	}
+	return fmt.Errorf("%w: %s for event type %s", ErrHandlerNotFound, handlerName, e.Type())
+}
+
+func (b *InMemoryEventBus) Start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+	if b.started {
+		return nil
+	}
+
+	b.startWorkers()
+	log.Printf("In-memory event bus started with %d workers", b.workers)
+	return nil
+}
+
+// Shutdown stops accepting new events and waits until every queued event has been handled
+func (b *InMemoryEventBus) Shutdown() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.queue)
+	// Drain events that were published before the bus was ever started
+	if !b.started {
+		b.startWorkers()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	log.Println("In-memory event bus stopped")
+	return nil
+}
+
+func (b *InMemoryEventBus) startWorkers() {
+	b.started = true
+	for i := 0; i < b.workers; i++ {
+		b.wg.Add(1)
+		go b.work()
+	}
+}
+
+func (b *InMemoryEventBus) work() {
+	defer b.wg.Done()
+
+	for e := range b.queue {
+		// Handlers run detached from the publishing request
+		if err := b.handle(context.Background(), e); err != nil {
+			log.Printf("Event %s (type %s) failed: %v", e.ID(), e.Type(), err)
+		}
+	}
+}
+
+func (b *InMemoryEventBus) handlersFor(eventType event.Type) []event.Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	handlers := make([]event.Handler, len(b.handlers[eventType]))
+	copy(handlers, b.handlers[eventType])
+	return handlers
+}
+
+func (b *InMemoryEventBus) handle(ctx context.Context, e event.Event) error {
+	var errs []error
+	for _, h := range b.handlersFor(e.Type()) {
+		name := event.HandlerName(h)
+		attempts, err := b.deliver(ctx, h, e)
+		if err != nil {
+			b.deadLetter(ctx, e, name, attempts, err)
+			errs = append(errs, fmt.Errorf("handler %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver calls the handler until it succeeds or the retries are exhausted, backing off exponentially.
+// It returns the number of attempts made.
+func (b *InMemoryEventBus) deliver(ctx context.Context, h event.Handler, e event.Event) (int, error) {
+	var err error
+	for attempt := 0; attempt <= b.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return attempt, ctx.Err()
+			case <-time.After(b.backoff(attempt)):
+			}
+		}
+
+		if err = safeHandle(ctx, h, e); err == nil {
+			return attempt + 1, nil
+		}
+		log.Printf("Handler %s failed on event %s (attempt %d/%d): %v", event.HandlerName(h), e.ID(), attempt+1, b.maxRetries+1, err)
+	}
+	return b.maxRetries + 1, err
+}
+
+func (b *InMemoryEventBus) deadLetter(ctx context.Context, e event.Event, handlerName string, attempts int, lastErr error) {
+	b.mu.RLock()
+	sink := b.deadLetters
+	b.mu.RUnlock()
+
+	if sink == nil {
+		return
+	}
+	// Store even when ctx was cancelled, otherwise the failure would be lost
+	if err := sink.Store(context.WithoutCancel(ctx), e, handlerName, attempts, lastErr); err != nil {
+		log.Printf("Failed to store dead letter for event %s and handler %s: %v", e.ID(), handlerName, err)
+	}
+}
+
+func (b *InMemoryEventBus) backoff(attempt int) time.Duration {
+	return b.retryBackoff * time.Duration(1<<(attempt-1))
+}
+
+func safeHandle(ctx context.Context, h event.Handler, e event.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return h.Handle(ctx, e)
+}
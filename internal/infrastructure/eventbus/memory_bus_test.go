@@ -0,0 +1,193 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/config"
+	"jcourse_go/internal/domain/event"
+)
+
+// countingHandler fails the first failures calls and records every event it handles
+type countingHandler struct {
+	mu       sync.Mutex
+	calls    int32
+	failures int32
+	handled  []string
+}
+
+func (h *countingHandler) Handle(ctx context.Context, e event.Event) error {
+	if atomic.AddInt32(&h.calls, 1) <= h.failures {
+		return errors.New("temporary failure")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled = append(h.handled, e.ID())
+	return nil
+}
+
+func (h *countingHandler) handledIDs() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.handled...)
+}
+
+func newTestEvent() event.Event {
+	return event.NewBaseEvent(event.TypeReviewCreated, &event.ReviewPayload{ReviewID: 1, UserID: 2, Action: "created"})
+}
+
+func newTestBus(queueSize int) *InMemoryEventBus {
+	return NewInMemoryEventBus(config.EventConfig{
+		Workers:      2,
+		QueueSize:    queueSize,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+}
+
+func TestInMemoryEventBus_PublishDeliversToHandlers(t *testing.T) {
+	bus := newTestBus(10)
+	handler := &countingHandler{}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+	assert.NoError(t, bus.Start())
+
+	e := newTestEvent()
+	assert.NoError(t, bus.Publish(context.Background(), e))
+	assert.NoError(t, bus.Shutdown())
+
+	assert.Equal(t, []string{e.ID()}, handler.handledIDs())
+}
+
+func TestInMemoryEventBus_RetriesFailedHandler(t *testing.T) {
+	bus := newTestBus(10)
+	handler := &countingHandler{failures: 2}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+
+	err := bus.Dispatch(context.Background(), newTestEvent())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&handler.calls))
+	assert.Len(t, handler.handledIDs(), 1)
+}
+
+func TestInMemoryEventBus_GivesUpAfterMaxRetries(t *testing.T) {
+	bus := newTestBus(10)
+	handler := &countingHandler{failures: 10}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+
+	err := bus.Dispatch(context.Background(), newTestEvent())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&handler.calls))
+}
+
+func TestInMemoryEventBus_NegativeMaxRetriesDisablesRetries(t *testing.T) {
+	bus := NewInMemoryEventBus(config.EventConfig{MaxRetries: -1})
+	handler := &countingHandler{failures: 10}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+
+	err := bus.Dispatch(context.Background(), newTestEvent())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handler.calls))
+}
+
+func TestInMemoryEventBus_ShutdownDrainsQueue(t *testing.T) {
+	bus := newTestBus(10)
+	handler := &countingHandler{}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+
+	// Events published before Start are still handled on Shutdown
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, bus.Publish(context.Background(), newTestEvent()))
+	}
+	assert.NoError(t, bus.Shutdown())
+
+	assert.Len(t, handler.handledIDs(), 5)
+}
+
+func TestInMemoryEventBus_QueueFull(t *testing.T) {
+	bus := newTestBus(1)
+
+	assert.NoError(t, bus.Publish(context.Background(), newTestEvent()))
+	err := bus.Publish(context.Background(), newTestEvent())
+
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestInMemoryEventBus_PublishAfterShutdown(t *testing.T) {
+	bus := newTestBus(1)
+	assert.NoError(t, bus.Shutdown())
+
+	err := bus.Publish(context.Background(), newTestEvent())
+
+	assert.ErrorIs(t, err, ErrBusClosed)
+}
+
+// recordingSink records the deliveries handed to it as dead letters
+type recordingSink struct {
+	mu       sync.Mutex
+	handlers []string
+	attempts []int
+}
+
+func (s *recordingSink) Store(ctx context.Context, e event.Event, handler string, attempts int, lastErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+	s.attempts = append(s.attempts, attempts)
+	return nil
+}
+
+func TestInMemoryEventBus_StoresDeadLetterAfterRetries(t *testing.T) {
+	bus := newTestBus(10)
+	sink := &recordingSink{}
+	bus.SetDeadLetterSink(sink)
+	failing := &countingHandler{failures: 10}
+	healthy := &countingHandler{}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, failing))
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, healthy))
+
+	assert.Error(t, bus.Dispatch(context.Background(), newTestEvent()))
+
+	assert.Equal(t, []string{"*eventbus.countingHandler"}, sink.handlers)
+	assert.Equal(t, []int{3}, sink.attempts)
+	assert.Len(t, healthy.handledIDs(), 1)
+}
+
+func TestInMemoryEventBus_Redeliver(t *testing.T) {
+	bus := newTestBus(10)
+	handler := &countingHandler{}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+
+	e := newTestEvent()
+	assert.NoError(t, bus.Redeliver(context.Background(), e, "*eventbus.countingHandler"))
+	assert.Equal(t, []string{e.ID()}, handler.handledIDs())
+
+	err := bus.Redeliver(context.Background(), e, "unknown")
+	assert.ErrorIs(t, err, ErrHandlerNotFound)
+}
+
+func TestDispatchPublisher_ReturnsOnceHandled(t *testing.T) {
+	bus := newTestBus(10)
+	handler := &countingHandler{}
+	failing := &countingHandler{failures: 10}
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, handler))
+	assert.NoError(t, bus.Register(event.TypeReviewCreated, failing))
+	sink := &recordingSink{}
+	bus.SetDeadLetterSink(sink)
+
+	e := newTestEvent()
+	err := NewDispatchPublisher(bus).Publish(context.Background(), e)
+
+	// The bus was never started, so only a synchronous dispatch handles the event
+	assert.NoError(t, err)
+	assert.Equal(t, []string{e.ID()}, handler.handledIDs())
+	assert.Len(t, sink.handlers, 1)
+}
@@ -0,0 +1,183 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+// mockBusStore is an in-memory busStore shared by the buses of a test
+type mockBusStore struct {
+	mu      sync.Mutex
+	events  []entity.BusEvent
+	claims  map[int64]map[string]*entity.BusEventClaim
+	offsets map[string]int64
+}
+
+func newMockBusStore() *mockBusStore {
+	return &mockBusStore{
+		claims:  make(map[int64]map[string]*entity.BusEventClaim),
+		offsets: make(map[string]int64),
+	}
+}
+
+// add stores the event as Publish would and returns its bus event ID
+func (s *mockBusStore) add(e event.Event) int64 {
+	return s.addAt(e, time.Now())
+}
+
+// addAt is add for an event stored at createdAt
+func (s *mockBusStore) addAt(e event.Event, createdAt time.Time) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload, _ := json.Marshal(e.Payload())
+	row := entity.BusEvent{
+		ID:         int64(len(s.events) + 1),
+		EventID:    e.ID(),
+		EventType:  string(e.Type()),
+		Version:    event.PayloadVersion(e.Type()),
+		Payload:    string(payload),
+		OccurredAt: e.Timestamp(),
+		CreatedAt:  createdAt,
+	}
+	s.events = append(s.events, row)
+	return row.ID
+}
+
+func (s *mockBusStore) LatestID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.latestID(), nil
+}
+
+func (s *mockBusStore) latestID() int64 {
+	var id int64
+	for _, row := range s.events {
+		if row.ID > id {
+			id = row.ID
+		}
+	}
+	return id
+}
+
+func (s *mockBusStore) After(ctx context.Context, id int64, limit int) ([]entity.BusEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.after(id, limit), nil
+}
+
+func (s *mockBusStore) after(id int64, limit int) []entity.BusEvent {
+	var rows []entity.BusEvent
+	for _, row := range s.events {
+		if row.ID > id {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+func (s *mockBusStore) Get(ctx context.Context, id int64) (*entity.BusEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.events {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *mockBusStore) Offset(ctx context.Context, consumerGroup string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.offsets[consumerGroup]; !ok {
+		s.offsets[consumerGroup] = s.latestID()
+	}
+	return s.offsets[consumerGroup], nil
+}
+
+func (s *mockBusStore) Unhandled(ctx context.Context, consumerGroup string, id int64, limit int) ([]entity.BusEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var unhandled []entity.BusEvent
+	for _, row := range s.after(id, len(s.events)) {
+		if !s.handled(row.ID, consumerGroup) {
+			unhandled = append(unhandled, row)
+		}
+	}
+	if len(unhandled) > limit {
+		unhandled = unhandled[:limit]
+	}
+	return unhandled, nil
+}
+
+func (s *mockBusStore) handled(id int64, consumerGroup string) bool {
+	claim := s.claims[id][consumerGroup]
+	return claim != nil && claim.HandledAt != nil
+}
+
+func (s *mockBusStore) Claim(ctx context.Context, id int64, consumerGroup string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.claims[id] == nil {
+		s.claims[id] = make(map[string]*entity.BusEventClaim)
+	}
+	if claim := s.claims[id][consumerGroup]; claim != nil && (claim.HandledAt != nil || !claim.ClaimedAt.Before(now.Add(-claimLease))) {
+		return false, nil
+	}
+	s.claims[id][consumerGroup] = &entity.BusEventClaim{BusEventID: id, ConsumerGroup: consumerGroup, ClaimedAt: now}
+	return true, nil
+}
+
+func (s *mockBusStore) Complete(ctx context.Context, id int64, consumerGroup string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if claim := s.claims[id][consumerGroup]; claim != nil {
+		claim.HandledAt = &now
+	}
+	return nil
+}
+
+func (s *mockBusStore) Release(ctx context.Context, id int64, consumerGroup string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if claim := s.claims[id][consumerGroup]; claim != nil && claim.HandledAt == nil {
+		delete(s.claims[id], consumerGroup)
+	}
+	return nil
+}
+
+func (s *mockBusStore) Advance(ctx context.Context, consumerGroup string, settled time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.after(s.offsets[consumerGroup], len(s.events)) {
+		if !row.CreatedAt.Before(settled) || !s.handled(row.ID, consumerGroup) {
+			break
+		}
+		s.offsets[consumerGroup] = row.ID
+	}
+	return s.offsets[consumerGroup], nil
+}
+
+func (s *mockBusStore) Purge(ctx context.Context, before time.Time) error {
+	return nil
+}
@@ -0,0 +1,29 @@
+package eventbus
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/event"
+)
+
+// OutboxPublisher publishes events by writing them to the outbox.
+// Called inside a transaction, the events are committed or rolled back together with the aggregate.
+type OutboxPublisher struct {
+	outboxRepo event.OutboxRepository
+}
+
+func NewOutboxPublisher(outboxRepo event.OutboxRepository) *OutboxPublisher {
+	return &OutboxPublisher{outboxRepo: outboxRepo}
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	messages := make([]event.OutboxMessage, 0, len(events))
+	for _, e := range events {
+		message, err := event.NewOutboxMessage(e)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+	return p.outboxRepo.Save(ctx, messages...)
+}
@@ -0,0 +1,413 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/config"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+const (
+	DefaultChannel   = "jcourse_events"
+	DefaultRetention = 24 * time.Hour
+
+	reconnectBackoff = 5 * time.Second
+	purgeInterval    = time.Hour
+	sweepInterval    = time.Minute
+	catchUpBatchSize = 500
+	// claimLease is how long a replica may take to handle a claimed event before another replica takes it over
+	claimLease = 5 * time.Minute
+	// commitLookback is how long catching up keeps looking for events whose transaction committed
+	// after that of an event with a higher ID
+	commitLookback = time.Minute
+)
+
+// PostgresEventBus shares events between replicas through a PostgreSQL table and LISTEN/NOTIFY.
+// Every replica listens on the channel and hands received events to a local in-memory bus;
+// with a consumer group configured, exactly one replica of the group handles each event,
+// and the group resumes from a persisted offset after every replica was stopped.
+type PostgresEventBus struct {
+	db            *gorm.DB
+	store         busStore
+	dsn           string
+	channel       string
+	consumerGroup string
+	retention     time.Duration
+	local         *InMemoryEventBus
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+
+	// deliverMu serialises delivering between the listener and the sweeper
+	deliverMu sync.Mutex
+	// lastID is the bus event up to which every event was delivered: the offset of the consumer group,
+	// or without one, the latest event when the bus started, moved forward by catching up
+	lastID int64
+	// delivered holds the events above lastID delivered without a consumer group
+	delivered map[int64]bool
+}
+
+func NewPostgresEventBus(db *gorm.DB, dsn string, conf config.EventConfig) *PostgresEventBus {
+	channel := conf.Postgres.Channel
+	if channel == "" {
+		channel = DefaultChannel
+	}
+	retention := conf.Postgres.Retention
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	return &PostgresEventBus{
+		db:            db,
+		store:         &gormBusStore{db: db},
+		dsn:           dsn,
+		channel:       channel,
+		consumerGroup: conf.Postgres.ConsumerGroup,
+		retention:     retention,
+		local:         NewInMemoryEventBus(conf),
+		delivered:     make(map[int64]bool),
+	}
+}
+
+func (b *PostgresEventBus) Register(eventType event.Type, handler event.Handler) error {
+	return b.local.Register(eventType, handler)
+}
+
+// Dispatch handles the events synchronously on this replica only
+func (b *PostgresEventBus) Dispatch(ctx context.Context, events ...event.Event) error {
+	return b.local.Dispatch(ctx, events...)
+}
+
+// Publish stores the events and notifies the listeners. When ctx carries a transaction,
+// the notifications are only delivered once it commits.
+func (b *PostgresEventBus) Publish(ctx context.Context, events ...event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return database.Conn(ctx, b.db).Transaction(func(tx *gorm.DB) error {
+		for _, e := range events {
+			payload, err := json.Marshal(e.Payload())
+			if err != nil {
+				return fmt.Errorf("failed to marshal payload of event %s: %w", e.ID(), err)
+			}
+
+			row := entity.BusEvent{
+				EventID:    e.ID(),
+				EventType:  string(e.Type()),
+				Version:    event.PayloadVersion(e.Type()),
+				Payload:    string(payload),
+				OccurredAt: e.Timestamp(),
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return fmt.Errorf("failed to store event %s: %w", e.ID(), err)
+			}
+			if err := tx.Exec("SELECT pg_notify(?, ?)", b.channel, strconv.FormatInt(row.ID, 10)).Error; err != nil {
+				return fmt.Errorf("failed to notify event %s: %w", e.ID(), err)
+			}
+		}
+		return nil
+	})
+}
+
+func (b *PostgresEventBus) Redeliver(ctx context.Context, e event.Event, handlerName string) error {
+	return b.local.Redeliver(ctx, e, handlerName)
+}
+
+func (b *PostgresEventBus) SetDeadLetterSink(sink event.DeadLetterSink) {
+	b.local.SetDeadLetterSink(sink)
+}
+
+func (b *PostgresEventBus) Start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.started {
+		return nil
+	}
+
+	// A consumer group resumes from its offset; otherwise only events published from now on are handled
+	var lastID int64
+	var err error
+	if b.consumerGroup != "" {
+		lastID, err = b.store.Offset(context.Background(), b.consumerGroup)
+	} else {
+		lastID, err = b.store.LatestID(context.Background())
+	}
+	if err != nil {
+		return err
+	}
+	b.lastID = lastID
+	if err := b.local.Start(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.started = true
+
+	b.wg.Add(3)
+	go b.listen(ctx)
+	go b.sweep(ctx)
+	go b.purge(ctx)
+
+	if b.consumerGroup != "" {
+		log.Printf("PostgreSQL event bus listening on %q as consumer group %q", b.channel, b.consumerGroup)
+	} else {
+		log.Printf("PostgreSQL event bus listening on %q", b.channel)
+	}
+	return nil
+}
+
+// Shutdown stops listening and waits until the received events have been handled
+func (b *PostgresEventBus) Shutdown() error {
+	b.mu.Lock()
+	if b.started {
+		b.cancel()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return b.local.Shutdown()
+}
+
+// listen keeps a dedicated connection listening on the channel, reconnecting when it drops
+func (b *PostgresEventBus) listen(ctx context.Context) {
+	defer b.wg.Done()
+
+	for {
+		err := b.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("PostgreSQL event bus listener disconnected, retrying in %s: %v", reconnectBackoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectBackoff):
+		}
+	}
+}
+
+func (b *PostgresEventBus) listenOnce(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", b.channel, err)
+	}
+
+	// Pick up events published while no connection was listening
+	if err := b.catchUp(ctx); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			log.Printf("Ignoring malformed bus notification %q", notification.Payload)
+			continue
+		}
+		if err := b.receive(ctx, id); err != nil {
+			log.Printf("Failed to receive bus event %d: %v", id, err)
+		}
+	}
+}
+
+// catchUp delivers the events after lastID that were not delivered yet, including those committed
+// after events with a higher ID, then moves lastID past the events too old to be still committing
+func (b *PostgresEventBus) catchUp(ctx context.Context) error {
+	b.deliverMu.Lock()
+	defer b.deliverMu.Unlock()
+
+	settled := time.Now().Add(-commitLookback)
+	if b.consumerGroup != "" {
+		return b.catchUpGroup(ctx, settled)
+	}
+
+	cursor, lastID, contiguous := b.lastID, b.lastID, true
+	for {
+		rows, err := b.store.After(ctx, cursor, catchUpBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if !b.delivered[row.ID] {
+				b.deliver(ctx, row)
+			}
+			cursor = row.ID
+			if contiguous && row.CreatedAt.Before(settled) {
+				lastID = row.ID
+			} else {
+				contiguous = false
+			}
+		}
+		if len(rows) < catchUpBatchSize {
+			break
+		}
+	}
+
+	b.lastID = lastID
+	for id := range b.delivered {
+		if id <= lastID {
+			delete(b.delivered, id)
+		}
+	}
+	return nil
+}
+
+func (b *PostgresEventBus) catchUpGroup(ctx context.Context, settled time.Time) error {
+	cursor := b.lastID
+	for {
+		rows, err := b.store.Unhandled(ctx, b.consumerGroup, cursor, catchUpBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			b.deliver(ctx, row)
+			cursor = row.ID
+		}
+		if len(rows) < catchUpBatchSize {
+			break
+		}
+	}
+
+	lastID, err := b.store.Advance(ctx, b.consumerGroup, settled)
+	if err != nil {
+		return err
+	}
+	b.lastID = lastID
+	return nil
+}
+
+func (b *PostgresEventBus) receive(ctx context.Context, id int64) error {
+	b.deliverMu.Lock()
+	defer b.deliverMu.Unlock()
+
+	if b.consumerGroup == "" && b.delivered[id] {
+		return nil
+	}
+	row, err := b.store.Get(ctx, id)
+	if err != nil || row == nil {
+		return err
+	}
+	b.deliver(ctx, *row)
+	return nil
+}
+
+func (b *PostgresEventBus) deliver(ctx context.Context, row entity.BusEvent) {
+	if b.consumerGroup == "" {
+		b.delivered[row.ID] = true
+		e, err := decodeBusEvent(row)
+		if err != nil {
+			log.Printf("Failed to decode bus event %s: %v", row.EventID, err)
+			return
+		}
+
+		err = b.local.Publish(ctx, e)
+		if errors.Is(err, ErrQueueFull) {
+			// Apply backpressure to the listener rather than dropping the event
+			err = b.local.Dispatch(ctx, e)
+		}
+		if err != nil {
+			log.Printf("Event %s (type %s) failed: %v", e.ID(), e.Type(), err)
+		}
+		return
+	}
+
+	claimed, err := b.store.Claim(ctx, row.ID, b.consumerGroup, time.Now())
+	if err != nil {
+		log.Printf("Failed to claim bus event %s: %v", row.EventID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// The handlers run synchronously so the claim is only completed once they finished;
+	// the local bus dead-letters the handlers that keep failing
+	e, err := decodeBusEvent(row)
+	if err != nil {
+		log.Printf("Failed to decode bus event %s: %v", row.EventID, err)
+	} else if err := b.local.Dispatch(ctx, e); err != nil {
+		log.Printf("Event %s (type %s) failed: %v", e.ID(), e.Type(), err)
+		if ctx.Err() != nil {
+			// Stopped before the handlers succeeded, so another replica handles the event
+			if err := b.store.Release(context.WithoutCancel(ctx), row.ID, b.consumerGroup); err != nil {
+				log.Printf("Failed to release bus event %s: %v", row.EventID, err)
+			}
+			return
+		}
+	}
+	if err := b.store.Complete(ctx, row.ID, b.consumerGroup, time.Now()); err != nil {
+		log.Printf("Failed to complete bus event %s: %v", row.EventID, err)
+	}
+}
+
+func decodeBusEvent(row entity.BusEvent) (event.Event, error) {
+	return event.UnmarshalEvent(row.EventID, event.Type(row.EventType), row.Version, []byte(row.Payload), row.OccurredAt)
+}
+
+// sweep periodically catches up, delivering the events whose claim was left by a stopped replica
+// and moving lastID forward
+func (b *PostgresEventBus) sweep(ctx context.Context) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.catchUp(ctx); err != nil {
+				log.Printf("Failed to catch up on bus events: %v", err)
+			}
+		}
+	}
+}
+
+// purge periodically removes events older than the retention period
+func (b *PostgresEventBus) purge(ctx context.Context) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.store.Purge(ctx, time.Now().Add(-b.retention)); err != nil {
+				log.Printf("Failed to purge bus events: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/event"
+)
+
+func newTestPostgresBus(store busStore, consumerGroup string) (*PostgresEventBus, *countingHandler) {
+	bus := &PostgresEventBus{
+		store:         store,
+		consumerGroup: consumerGroup,
+		local:         newTestBus(10),
+		delivered:     make(map[int64]bool),
+	}
+	handler := &countingHandler{}
+	_ = bus.Register(event.TypeReviewCreated, handler)
+	_ = bus.local.Start()
+	return bus, handler
+}
+
+func TestPostgresEventBus_ConsumerGroupHandlesEventOnce(t *testing.T) {
+	store := newMockBusStore()
+	first, firstHandler := newTestPostgresBus(store, "workers")
+	second, secondHandler := newTestPostgresBus(store, "workers")
+	other, otherHandler := newTestPostgresBus(store, "audit")
+
+	e := newTestEvent()
+	id := store.add(e)
+	for _, bus := range []*PostgresEventBus{first, second, other} {
+		assert.NoError(t, bus.receive(context.Background(), id))
+		assert.NoError(t, bus.local.Shutdown())
+	}
+
+	assert.Len(t, append(firstHandler.handledIDs(), secondHandler.handledIDs()...), 1)
+	assert.Equal(t, []string{e.ID()}, otherHandler.handledIDs())
+}
+
+func TestPostgresEventBus_WithoutConsumerGroupEveryReplicaHandles(t *testing.T) {
+	store := newMockBusStore()
+	first, firstHandler := newTestPostgresBus(store, "")
+	second, secondHandler := newTestPostgresBus(store, "")
+
+	e := newTestEvent()
+	id := store.add(e)
+	for _, bus := range []*PostgresEventBus{first, second} {
+		assert.NoError(t, bus.receive(context.Background(), id))
+		assert.NoError(t, bus.local.Shutdown())
+	}
+
+	assert.Equal(t, []string{e.ID()}, firstHandler.handledIDs())
+	assert.Equal(t, []string{e.ID()}, secondHandler.handledIDs())
+}
+
+func TestPostgresEventBus_CatchUpDeliversMissedEvents(t *testing.T) {
+	store := newMockBusStore()
+	store.add(newTestEvent())
+	bus, handler := newTestPostgresBus(store, "workers")
+	lastID, err := store.Offset(context.Background(), "workers")
+	assert.NoError(t, err)
+	bus.lastID = lastID
+
+	// More events than one batch are published while the listener is disconnected
+	var missed []string
+	for i := 0; i < catchUpBatchSize+1; i++ {
+		e := newTestEvent()
+		store.addAt(e, time.Now().Add(-time.Hour))
+		missed = append(missed, e.ID())
+	}
+
+	assert.NoError(t, bus.catchUp(context.Background()))
+	assert.NoError(t, bus.local.Shutdown())
+
+	assert.ElementsMatch(t, missed, handler.handledIDs())
+	assert.Equal(t, int64(catchUpBatchSize+2), bus.lastID)
+	// The offset is stored, so the group resumes from it after a restart
+	offset, err := store.Offset(context.Background(), "workers")
+	assert.NoError(t, err)
+	assert.Equal(t, bus.lastID, offset)
+}
+
+func TestPostgresEventBus_CatchUpDeliversEventsCommittedOutOfOrder(t *testing.T) {
+	for _, consumerGroup := range []string{"", "workers"} {
+		store := newMockBusStore()
+		bus, handler := newTestPostgresBus(store, consumerGroup)
+
+		// The event with the lower ID commits after the other one was received
+		late := newTestEvent()
+		store.add(late)
+		received := newTestEvent()
+		assert.NoError(t, bus.receive(context.Background(), store.add(received)))
+
+		assert.NoError(t, bus.catchUp(context.Background()))
+		assert.NoError(t, bus.catchUp(context.Background()))
+		assert.NoError(t, bus.local.Shutdown())
+
+		assert.ElementsMatch(t, []string{received.ID(), late.ID()}, handler.handledIDs(), consumerGroup)
+		// Both events may still be followed by events committing out of order
+		assert.Equal(t, int64(0), bus.lastID, consumerGroup)
+	}
+}
+
+func TestPostgresEventBus_ConsumerGroupTakesOverUnfinishedClaim(t *testing.T) {
+	store := newMockBusStore()
+	bus, handler := newTestPostgresBus(store, "workers")
+
+	// A replica claimed the events and stopped before handling them
+	stale, fresh := newTestEvent(), newTestEvent()
+	staleID, freshID := store.add(stale), store.add(fresh)
+	_, err := store.Claim(context.Background(), staleID, "workers", time.Now().Add(-claimLease-time.Second))
+	assert.NoError(t, err)
+	_, err = store.Claim(context.Background(), freshID, "workers", time.Now())
+	assert.NoError(t, err)
+
+	assert.NoError(t, bus.catchUp(context.Background()))
+	assert.NoError(t, bus.local.Shutdown())
+
+	assert.Equal(t, []string{stale.ID()}, handler.handledIDs())
+}
+
+func TestPostgresEventBus_ConsumerGroupReleasesClaimWhenStopped(t *testing.T) {
+	store := newMockBusStore()
+	bus := &PostgresEventBus{store: store, consumerGroup: "workers", local: newTestBus(10)}
+	failing := &countingHandler{failures: 1}
+	_ = bus.Register(event.TypeReviewCreated, failing)
+	e := newTestEvent()
+	id := store.add(e)
+
+	// The replica stops while waiting to retry the handler
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, bus.receive(ctx, id))
+	assert.NoError(t, bus.local.Shutdown())
+
+	// Another replica of the group handles the event at once
+	other, otherHandler := newTestPostgresBus(store, "workers")
+	assert.NoError(t, other.receive(context.Background(), id))
+	assert.NoError(t, other.local.Shutdown())
+
+	assert.Empty(t, failing.handledIDs())
+	assert.Equal(t, []string{e.ID()}, otherHandler.handledIDs())
+}
+
+func TestPostgresEventBus_CatchUpSkipsReceivedEvents(t *testing.T) {
+	store := newMockBusStore()
+	bus, handler := newTestPostgresBus(store, "")
+
+	received := newTestEvent()
+	assert.NoError(t, bus.receive(context.Background(), store.add(received)))
+	missed := newTestEvent()
+	store.add(missed)
+
+	assert.NoError(t, bus.catchUp(context.Background()))
+	assert.NoError(t, bus.local.Shutdown())
+
+	assert.ElementsMatch(t, []string{received.ID(), missed.ID()}, handler.handledIDs())
+}
+
+func TestPostgresEventBus_ReceiveIgnoresPurgedEvent(t *testing.T) {
+	bus, handler := newTestPostgresBus(newMockBusStore(), "")
+
+	assert.NoError(t, bus.receive(context.Background(), 42))
+	assert.NoError(t, bus.local.Shutdown())
+
+	assert.Empty(t, handler.handledIDs())
+	assert.Equal(t, int64(0), bus.lastID)
+}
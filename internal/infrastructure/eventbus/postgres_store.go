@@ -0,0 +1,165 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+// busStore is the table the PostgreSQL event bus reads received events and claims from
+type busStore interface {
+	// LatestID returns the highest stored bus event ID, or 0 when there is none
+	LatestID(ctx context.Context) (int64, error)
+	// After returns up to limit events with an ID above id, oldest first
+	After(ctx context.Context, id int64, limit int) ([]entity.BusEvent, error)
+	// Get returns nil when the event has already been purged
+	Get(ctx context.Context, id int64) (*entity.BusEvent, error)
+	// Offset returns the offset of the consumer group, starting a new group at the latest event
+	Offset(ctx context.Context, consumerGroup string) (int64, error)
+	// Unhandled returns up to limit events with an ID above id the consumer group has not handled, oldest first
+	Unhandled(ctx context.Context, consumerGroup string, id int64, limit int) ([]entity.BusEvent, error)
+	// Claim reports whether the consumer group takes the event: it is unhandled and any earlier claim
+	// is older than claimLease, so the replica that made it is taken to have stopped
+	Claim(ctx context.Context, id int64, consumerGroup string, now time.Time) (bool, error)
+	// Complete records that the handlers of the claimed event finished
+	Complete(ctx context.Context, id int64, consumerGroup string, now time.Time) error
+	// Release gives up an unhandled claim so that another replica can take the event at once
+	Release(ctx context.Context, id int64, consumerGroup string) error
+	// Advance moves the offset of the consumer group past the handled events created before settled,
+	// and returns the new offset
+	Advance(ctx context.Context, consumerGroup string, settled time.Time) (int64, error)
+	// Purge removes events and claims older than before
+	Purge(ctx context.Context, before time.Time) error
+}
+
+type gormBusStore struct {
+	db *gorm.DB
+}
+
+func (s *gormBusStore) LatestID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.db.WithContext(ctx).Model(&entity.BusEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to read latest bus event: %w", err)
+	}
+	return id, nil
+}
+
+func (s *gormBusStore) After(ctx context.Context, id int64, limit int) ([]entity.BusEvent, error) {
+	var rows []entity.BusEvent
+	err := s.db.WithContext(ctx).
+		Where("id > ?", id).
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch missed bus events: %w", err)
+	}
+	return rows, nil
+}
+
+func (s *gormBusStore) Get(ctx context.Context, id int64) (*entity.BusEvent, error) {
+	var row entity.BusEvent
+	if err := s.db.WithContext(ctx).First(&row, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bus event: %w", err)
+	}
+	return &row, nil
+}
+
+func (s *gormBusStore) Offset(ctx context.Context, consumerGroup string) (int64, error) {
+	var offset int64
+	// The no-op update makes RETURNING yield the offset of an existing group too
+	err := s.db.WithContext(ctx).Raw(`INSERT INTO bus_consumer_offsets (consumer_group, last_id, updated_at)
+		SELECT ?, COALESCE(MAX(id), 0), ? FROM bus_events
+		ON CONFLICT (consumer_group) DO UPDATE SET consumer_group = EXCLUDED.consumer_group
+		RETURNING last_id`, consumerGroup, time.Now()).Scan(&offset).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to read bus consumer offset: %w", err)
+	}
+	return offset, nil
+}
+
+func (s *gormBusStore) Unhandled(ctx context.Context, consumerGroup string, id int64, limit int) ([]entity.BusEvent, error) {
+	handled := s.db.Model(&entity.BusEventClaim{}).Select("1").
+		Where("bus_event_id = bus_events.id AND consumer_group = ? AND handled_at IS NOT NULL", consumerGroup)
+
+	var rows []entity.BusEvent
+	err := s.db.WithContext(ctx).
+		Where("id > ?", id).
+		Where("NOT EXISTS (?)", handled).
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unhandled bus events: %w", err)
+	}
+	return rows, nil
+}
+
+func (s *gormBusStore) Claim(ctx context.Context, id int64, consumerGroup string, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).Exec(`INSERT INTO bus_event_claims (bus_event_id, consumer_group, claimed_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (bus_event_id, consumer_group) DO UPDATE SET claimed_at = EXCLUDED.claimed_at
+		WHERE bus_event_claims.handled_at IS NULL AND bus_event_claims.claimed_at < ?`,
+		id, consumerGroup, now, now.Add(-claimLease))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim bus event: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *gormBusStore) Complete(ctx context.Context, id int64, consumerGroup string, now time.Time) error {
+	err := s.db.WithContext(ctx).Model(&entity.BusEventClaim{}).
+		Where("bus_event_id = ? AND consumer_group = ?", id, consumerGroup).
+		UpdateColumn("handled_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete bus event claim: %w", err)
+	}
+	return nil
+}
+
+func (s *gormBusStore) Release(ctx context.Context, id int64, consumerGroup string) error {
+	err := s.db.WithContext(ctx).
+		Where("bus_event_id = ? AND consumer_group = ? AND handled_at IS NULL", id, consumerGroup).
+		Delete(&entity.BusEventClaim{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release bus event claim: %w", err)
+	}
+	return nil
+}
+
+func (s *gormBusStore) Advance(ctx context.Context, consumerGroup string, settled time.Time) (int64, error) {
+	var offset int64
+	// The offset stops before the first event that is unhandled or recent enough for an event
+	// with a lower ID to still be committing
+	err := s.db.WithContext(ctx).Raw(`UPDATE bus_consumer_offsets AS o SET last_id = COALESCE(
+			(SELECT MIN(e.id) - 1 FROM bus_events e
+				WHERE e.id > o.last_id AND (e.created_at >= ? OR NOT EXISTS (
+					SELECT 1 FROM bus_event_claims c
+					WHERE c.bus_event_id = e.id AND c.consumer_group = o.consumer_group AND c.handled_at IS NOT NULL))),
+			(SELECT GREATEST(o.last_id, COALESCE(MAX(id), 0)) FROM bus_events)
+		), updated_at = ?
+		WHERE o.consumer_group = ?
+		RETURNING o.last_id`, settled, time.Now(), consumerGroup).Scan(&offset).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance bus consumer offset: %w", err)
+	}
+	return offset, nil
+}
+
+func (s *gormBusStore) Purge(ctx context.Context, before time.Time) error {
+	if err := s.db.WithContext(ctx).Where("claimed_at < ?", before).Delete(&entity.BusEventClaim{}).Error; err != nil {
+		return fmt.Errorf("failed to purge bus event claims: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Where("created_at < ?", before).Delete(&entity.BusEvent{}).Error; err != nil {
+		return fmt.Errorf("failed to purge bus events: %w", err)
+	}
+	return nil
+}
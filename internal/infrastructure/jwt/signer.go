@@ -0,0 +1,221 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// minHMACSecretLength is the shortest HS256 secret accepted: 256 bits
+const minHMACSecretLength = 32
+
+var ErrInvalidToken = errors.New("invalid access token")
+
+// Key is a key access tokens are signed with. Its ID is sent as the kid header,
+// so tokens are verified with the key they were signed with after a rotation.
+type Key struct {
+	ID string
+	// Algorithm is HS256 or EdDSA
+	Algorithm string
+	// Secret is the HMAC secret for HS256, or the base64 encoded Ed25519 seed or private key for EdDSA
+	Secret string
+}
+
+type signingKey struct {
+	id         string
+	algorithm  string
+	secret     []byte
+	privateKey ed25519.PrivateKey
+}
+
+func newSigningKey(key Key) (*signingKey, error) {
+	if key.ID == "" {
+		return nil, errors.New("signing key id is required")
+	}
+
+	switch key.Algorithm {
+	case AlgorithmHS256:
+		if len(key.Secret) < minHMACSecretLength {
+			return nil, fmt.Errorf("signing key %q: HS256 secret must be at least %d bytes", key.ID, minHMACSecretLength)
+		}
+		return &signingKey{id: key.ID, algorithm: key.Algorithm, secret: []byte(key.Secret)}, nil
+	case AlgorithmEdDSA:
+		raw, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: malformed Ed25519 key: %w", key.ID, err)
+		}
+		switch len(raw) {
+		case ed25519.SeedSize:
+			return &signingKey{id: key.ID, algorithm: key.Algorithm, privateKey: ed25519.NewKeyFromSeed(raw)}, nil
+		case ed25519.PrivateKeySize:
+			return &signingKey{id: key.ID, algorithm: key.Algorithm, privateKey: ed25519.PrivateKey(raw)}, nil
+		default:
+			return nil, fmt.Errorf("signing key %q: Ed25519 key must be a %d byte seed or %d byte private key",
+				key.ID, ed25519.SeedSize, ed25519.PrivateKeySize)
+		}
+	default:
+		return nil, fmt.Errorf("signing key %q: unsupported algorithm %q", key.ID, key.Algorithm)
+	}
+}
+
+func (k *signingKey) sign(signed []byte) []byte {
+	if k.algorithm == AlgorithmEdDSA {
+		return ed25519.Sign(k.privateKey, signed)
+	}
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(signed)
+	return mac.Sum(nil)
+}
+
+func (k *signingKey) verify(signed, signature []byte) bool {
+	if k.algorithm == AlgorithmEdDSA {
+		return ed25519.Verify(k.privateKey.Public().(ed25519.PublicKey), signed, signature)
+	}
+	return hmac.Equal(k.sign(signed), signature)
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+type claims struct {
+	Subject          string `json:"sub"`
+	Role             string `json:"role"`
+	SessionID        int    `json:"sid"`
+	TwoFactorPending bool   `json:"tfp,omitempty"`
+	IssuedAt         int64  `json:"iat"`
+	Expiry           int64  `json:"exp"`
+}
+
+type signer struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// NewSigner signs access tokens with the first key and accepts tokens signed with any of the keys,
+// so a key is rotated by putting its successor first and dropping it once its tokens have expired
+func NewSigner(keys []Key) (auth.AccessTokenSigner, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+
+	s := &signer{keys: make(map[string]*signingKey, len(keys))}
+	for _, key := range keys {
+		signingKey, err := newSigningKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := s.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+		s.keys[key.ID] = signingKey
+		if s.active == nil {
+			s.active = signingKey
+		}
+	}
+	return s, nil
+}
+
+func (s *signer) Sign(accessClaims auth.AccessClaims) (string, error) {
+	headerSegment, err := encodeSegment(header{Algorithm: s.active.algorithm, Type: "JWT", KeyID: s.active.id})
+	if err != nil {
+		return "", err
+	}
+	claimsSegment, err := encodeSegment(claims{
+		Subject:          strconv.Itoa(accessClaims.UserID),
+		Role:             string(accessClaims.Role),
+		SessionID:        accessClaims.SessionID,
+		TwoFactorPending: accessClaims.TwoFactorPending,
+		IssuedAt:         accessClaims.IssuedAt.Unix(),
+		Expiry:           accessClaims.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := headerSegment + "." + claimsSegment
+	return signed + "." + base64.RawURLEncoding.EncodeToString(s.active.sign([]byte(signed))), nil
+}
+
+// Verify only accepts the algorithm of the key named by the kid header, so a token cannot pick a weaker one
+func (s *signer) Verify(token string, now time.Time) (*auth.AccessClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	key, ok := s.keys[h.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, h.KeyID)
+	}
+	if h.Algorithm != key.algorithm {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, h.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	expiresAt := time.Unix(c.Expiry, 0)
+	if !now.Before(expiresAt) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	userID, err := strconv.Atoi(c.Subject)
+	if err != nil || userID <= 0 {
+		return nil, fmt.Errorf("%w: malformed subject", ErrInvalidToken)
+	}
+
+	return &auth.AccessClaims{
+		UserID:           userID,
+		Role:             common.Role(c.Role),
+		SessionID:        c.SessionID,
+		TwoFactorPending: c.TwoFactorPending,
+		IssuedAt:         time.Unix(c.IssuedAt, 0),
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("malformed segment: %w", err)
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+)
+
+var (
+	hmacKey = Key{ID: "k1", Algorithm: AlgorithmHS256, Secret: strings.Repeat("s", minHMACSecretLength)}
+	edKey   = Key{ID: "k2", Algorithm: AlgorithmEdDSA, Secret: base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize))}
+)
+
+func testClaims(now time.Time) auth.AccessClaims {
+	return auth.NewAccessClaims(&common.User{UserID: 7, Role: common.RoleAdmin, TwoFactorPending: true}, 3, now)
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	for _, key := range []Key{hmacKey, edKey} {
+		signer, err := NewSigner([]Key{key})
+		assert.NoError(t, err)
+
+		token, err := signer.Sign(testClaims(now))
+		assert.NoError(t, err)
+
+		claims, err := signer.Verify(token, now)
+		assert.NoError(t, err, key.Algorithm)
+		assert.Equal(t, testClaims(now), *claims, key.Algorithm)
+	}
+}
+
+func TestSigner_RotatedKeyStillVerifies(t *testing.T) {
+	now := time.Now()
+	old, _ := NewSigner([]Key{hmacKey})
+	token, _ := old.Sign(testClaims(now))
+
+	rotated, err := NewSigner([]Key{edKey, hmacKey})
+	assert.NoError(t, err)
+
+	_, err = rotated.Verify(token, now)
+	assert.NoError(t, err)
+
+	// Once the old key is dropped its tokens are refused
+	dropped, _ := NewSigner([]Key{edKey})
+	_, err = dropped.Verify(token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestSigner_RejectsInvalidTokens(t *testing.T) {
+	now := time.Now()
+	signer, _ := NewSigner([]Key{hmacKey, edKey})
+	token, _ := signer.Sign(testClaims(now))
+	parts := strings.Split(token, ".")
+
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","role":"admin","exp":9999999999}`)) + "." + parts[2]
+	// The HS256 key named with another algorithm must not be accepted
+	confused := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"k1"}`)) + "." + parts[1] + "."
+
+	for name, raw := range map[string]string{
+		"tampered":  tampered,
+		"confused":  confused,
+		"malformed": "not-a-token",
+	} {
+		_, err := signer.Verify(raw, now)
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	_, err := signer.Verify(token, now.Add(auth.AccessTokenTTL))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewSigner_ValidatesKeys(t *testing.T) {
+	for name, keys := range map[string][]Key{
+		"none":        nil,
+		"short":       {{ID: "k", Algorithm: AlgorithmHS256, Secret: "short"}},
+		"unsupported": {{ID: "k", Algorithm: "RS256", Secret: hmacKey.Secret}},
+		"no id":       {{Algorithm: AlgorithmHS256, Secret: hmacKey.Secret}},
+		"duplicate":   {hmacKey, hmacKey},
+	} {
+		_, err := NewSigner(keys)
+		assert.Error(t, err, name)
+	}
+}
@@ -1,6 +1,8 @@
 package migrations
 
 import (
+	"fmt"
+
 	"gorm.io/gorm"
 
 	"jcourse_go/internal/infrastructure/entity"
@@ -28,6 +30,121 @@ func Migrate(db *gorm.DB) error {
 			description: "Create initial database schema for all entities",
 			migrate:     migrateInitialSchema,
 		},
+		{
+			name:        "002_outbox_messages",
+			description: "Create transactional outbox for domain events",
+			migrate:     migrateOutbox,
+		},
+		{
+			name:        "003_bus_events",
+			description: "Create event tables for the PostgreSQL event bus",
+			migrate:     migrateBusEvents,
+		},
+		{
+			name:        "004_dead_letters",
+			description: "Create dead-letter store for failed event deliveries",
+			migrate:     migrateDeadLetters,
+		},
+		{
+			name:        "005_event_type_names",
+			description: "Store event types by stable name with payload schema versions",
+			migrate:     migrateEventTypeNames,
+		},
+		{
+			name:        "006_webhooks",
+			description: "Create webhook subscriptions and delivery log",
+			migrate:     migrateWebhooks,
+		},
+		{
+			name:        "007_processed_events",
+			description: "Create ledger of events handled by each event handler",
+			migrate:     migrateProcessedEvents,
+		},
+		{
+			name:        "008_daily_counters",
+			description: "Create event-driven daily counters and track daily likes",
+			migrate:     migrateDailyCounters,
+		},
+		{
+			name:        "009_event_store",
+			description: "Create append-only event store indexed by aggregate",
+			migrate:     migrateEventStore,
+		},
+		{
+			name:        "010_hashed_session_tokens",
+			description: "Store session tokens as hashes with client metadata, expiring guessable legacy sessions",
+			migrate:     migrateHashedSessionTokens,
+		},
+		{
+			name:        "011_session_activity",
+			description: "Track when sessions and users were last active",
+			migrate:     migrateSessionActivity,
+		},
+		{
+			name:        "012_verification_code_purpose",
+			description: "Scope verification codes to the flow they were sent for",
+			migrate:     migrateVerificationCodePurpose,
+		},
+		{
+			name:        "013_external_identities",
+			description: "Link users to identity provider accounts and track pending external logins",
+			migrate:     migrateExternalIdentities,
+		},
+		{
+			name:        "014_two_factor",
+			description: "Create TOTP second factors and pending two-factor login challenges",
+			migrate:     migrateTwoFactor,
+		},
+		{
+			name:        "015_api_tokens",
+			description: "Create scoped personal API tokens",
+			migrate:     migrateAPITokens,
+		},
+		{
+			name:        "016_brute_force_protection",
+			description: "Count failed logins per email and IP, and wrong guesses per verification code",
+			migrate:     migrateBruteForceProtection,
+		},
+		{
+			name:        "017_invite_codes",
+			description: "Create invite codes for restricted registration",
+			migrate:     migrateInviteCodes,
+		},
+		{
+			name:        "018_account_deletion",
+			description: "Schedule account deletion after a grace period",
+			migrate:     migrateAccountDeletion,
+		},
+		{
+			name:        "019_email_changes",
+			description: "Record email changes so the previous address can revert them",
+			migrate:     migrateEmailChanges,
+		},
+		{
+			name:        "020_user_suspension",
+			description: "Store the reason and end of user suspensions",
+			migrate:     migrateUserSuspension,
+		},
+		{
+			name:        "021_refresh_tokens",
+			description: "Create the refresh tokens that renew access tokens in token mode",
+			migrate:     migrateRefreshTokens,
+		},
+		{
+			name:        "022_review_sorting",
+			description: "Count the likes of reviews and index the review sort orders",
+			migrate:     migrateReviewSorting,
+		},
+		{
+			name:        "023_review_point_records",
+			description: "Link the points awarded and revoked for reviews to the review",
+			migrate:     migrateReviewPointRecords,
+		},
+		{
+			name:        "024_bus_consumer_offsets",
+			description: "Store the offset of each event bus consumer group and when its claims were handled",
+			migrate:     migrateBusConsumerOffsets,
+		},
 	}
 
 	for _, migration := range migrations {
@@ -75,3 +192,180 @@ func migrateInitialSchema(db *gorm.DB) error {
 
 	return nil
 }
+
+func migrateOutbox(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.OutboxMessage{})
+}
+
+func migrateBusEvents(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.BusEvent{}, &entity.BusEventClaim{})
+}
+
+func migrateDeadLetters(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.DeadLetter{})
+}
+
+// legacyEventTypeNames maps the former numeric event types to their stable names
+const legacyEventTypeNames = `CASE event_type
+	WHEN 0 THEN 'user.created'
+	WHEN 1 THEN 'review.created'
+	WHEN 2 THEN 'review.modified'
+	ELSE event_type::text END`
+
+func migrateEventTypeNames(db *gorm.DB) error {
+	tables := map[string]interface{}{
+		"outbox_messages": &entity.OutboxMessage{},
+		"bus_events":      &entity.BusEvent{},
+		"dead_letters":    &entity.DeadLetter{},
+	}
+
+	for table, model := range tables {
+		var dataType string
+		err := db.Raw(
+			"SELECT data_type FROM information_schema.columns WHERE table_name = ? AND column_name = 'event_type'",
+			table,
+		).Scan(&dataType).Error
+		if err != nil {
+			return err
+		}
+
+		// Tables created after the switch to names already store strings
+		if dataType == "integer" || dataType == "bigint" {
+			sql := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN event_type TYPE varchar(100) USING (%s)", table, legacyEventTypeNames)
+			if err := db.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := db.AutoMigrate(model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrateWebhooks(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.WebhookSubscription{}, &entity.WebhookDelivery{})
+}
+
+func migrateProcessedEvents(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.ProcessedEvent{})
+}
+
+func migrateDailyCounters(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.DailyStatistics{}, &entity.DailyCounter{})
+}
+
+func migrateEventStore(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.StoredEvent{}, &entity.StoredEventAggregate{})
+}
+
+func migrateHashedSessionTokens(db *gorm.DB) error {
+	// Legacy tokens were guessable. The rows are kept for the activity statistics, but expired
+	// and given unique placeholder tokens that no hash can match.
+	err := db.Exec(
+		"UPDATE user_sessions SET expires_at = LEAST(expires_at, NOW()), token = 'expired:' || id WHERE token LIKE 'session:%'",
+	).Error
+	if err != nil {
+		return err
+	}
+	return db.AutoMigrate(&entity.UserSession{})
+}
+
+func migrateSessionActivity(db *gorm.DB) error {
+	if err := db.AutoMigrate(&entity.UserSession{}, &entity.User{}); err != nil {
+		return err
+	}
+	return db.Exec("UPDATE user_sessions SET last_seen_at = created_at WHERE last_seen_at IS NULL").Error
+}
+
+func migrateVerificationCodePurpose(db *gorm.DB) error {
+	// Codes used to be unique per email; they are now unique per email and purpose
+	migrator := db.Migrator()
+	if migrator.HasIndex(&entity.VerificationCode{}, "idx_verification_codes_email") {
+		if err := migrator.DropIndex(&entity.VerificationCode{}, "idx_verification_codes_email"); err != nil {
+			return err
+		}
+	}
+	return db.AutoMigrate(&entity.VerificationCode{})
+}
+
+func migrateExternalIdentities(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.ExternalIdentity{}, &entity.OAuthState{})
+}
+
+func migrateTwoFactor(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.UserTwoFactor{}, &entity.LoginChallenge{})
+}
+
+func migrateAPITokens(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.APIToken{})
+}
+
+func migrateBruteForceProtection(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.AuthFailure{}, &entity.VerificationCode{})
+}
+
+func migrateInviteCodes(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.InviteCode{})
+}
+
+func migrateAccountDeletion(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.User{})
+}
+
+func migrateEmailChanges(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.EmailChange{})
+}
+
+func migrateUserSuspension(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.User{})
+}
+
+func migrateRefreshTokens(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.RefreshToken{})
+}
+
+func migrateReviewSorting(db *gorm.DB) error {
+	if err := db.AutoMigrate(&entity.Review{}); err != nil {
+		return err
+	}
+	err := db.Exec(`UPDATE reviews SET like_count = (
+		SELECT COUNT(*) FROM review_actions
+		WHERE review_actions.review_id = reviews.id AND review_actions.action = 'like' AND review_actions.deleted_at IS NULL
+	)`).Error
+	if err != nil {
+		return err
+	}
+
+	// One index per sort order, each ending with the ID the orders break ties with
+	for _, sql := range []string{
+		"CREATE INDEX IF NOT EXISTS idx_reviews_created_at_id ON reviews (created_at, id)",
+		"CREATE INDEX IF NOT EXISTS idx_reviews_rating_id ON reviews (rating, id)",
+		"CREATE INDEX IF NOT EXISTS idx_reviews_like_count_id ON reviews (like_count, id)",
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateReviewPointRecords(db *gorm.DB) error {
+	if err := db.AutoMigrate(&entity.UserPointRecord{}); err != nil {
+		return err
+	}
+	// Earlier review records only named the review in their description
+	return db.Exec(`UPDATE user_point_records
+		SET review_id = substring(description from 'review #([0-9]+)$')::int
+		WHERE review_id IS NULL AND description ~ '^Points (awarded for creating|revoked for deleting) review #[0-9]+$'`).Error
+}
+
+func migrateBusConsumerOffsets(db *gorm.DB) error {
+	if err := db.AutoMigrate(&entity.BusEventClaim{}, &entity.BusConsumerOffset{}); err != nil {
+		return err
+	}
+	// Earlier claims were only taken right before handling the event
+	return db.Exec("UPDATE bus_event_claims SET handled_at = claimed_at WHERE handled_at IS NULL").Error
+}
@@ -0,0 +1,165 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"jcourse_go/internal/config"
+	"jcourse_go/internal/domain/auth"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+	// maxResponseBody bounds how much of a token endpoint response is read
+	maxResponseBody = 1 << 20
+)
+
+// ProviderJAccount is the SJTU campus single sign-on
+const ProviderJAccount = "jaccount"
+
+// knownProviders holds the public endpoints of well-known providers
+var knownProviders = map[string]config.OIDCProviderConfig{
+	ProviderJAccount: {
+		AuthURL:     "https://jaccount.sjtu.edu.cn/oauth2/authorize",
+		TokenURL:    "https://jaccount.sjtu.edu.cn/oauth2/token",
+		Scopes:      []string{"openid", "essential"},
+		EmailDomain: "sjtu.edu.cn",
+	},
+}
+
+// Provider implements the authorization code flow with PKCE against an OAuth2/OIDC server
+type Provider struct {
+	conf   config.OIDCProviderConfig
+	client *http.Client
+	keys   *keySet
+}
+
+func NewProvider(conf config.OIDCProviderConfig, client *http.Client) *Provider {
+	conf = withDefaults(conf)
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Provider{
+		conf:   conf,
+		client: client,
+		keys:   newKeySet(conf.JWKSURL, client),
+	}
+}
+
+// withDefaults fills the endpoints left empty with those of a known provider of the same name
+func withDefaults(conf config.OIDCProviderConfig) config.OIDCProviderConfig {
+	known, ok := knownProviders[conf.Name]
+	if !ok {
+		known = config.OIDCProviderConfig{Scopes: []string{"openid", "email", "profile"}}
+	}
+	if conf.Issuer == "" {
+		conf.Issuer = known.Issuer
+	}
+	if conf.AuthURL == "" {
+		conf.AuthURL = known.AuthURL
+	}
+	if conf.TokenURL == "" {
+		conf.TokenURL = known.TokenURL
+	}
+	if conf.JWKSURL == "" {
+		conf.JWKSURL = known.JWKSURL
+	}
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = known.Scopes
+	}
+	if conf.EmailDomain == "" {
+		conf.EmailDomain = known.EmailDomain
+	}
+	return conf
+}
+
+func (p *Provider) Name() string {
+	return p.conf.Name
+}
+
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.conf.ClientID},
+		"redirect_uri":          {p.conf.RedirectURL},
+		"scope":                 {strings.Join(p.conf.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.conf.AuthURL, "?") {
+		separator = "&"
+	}
+	return p.conf.AuthURL + separator + query.Encode()
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*auth.IdentityClaims, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.conf.RedirectURL},
+		"client_id":     {p.conf.ClientID},
+		"client_secret": {p.conf.ClientSecret},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.conf.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBody)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("token request failed with status %d: %s %s", resp.StatusCode, token.Error, token.Description)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, token.IDToken, nonce, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return p.identityClaims(claims), nil
+}
+
+func (p *Provider) identityClaims(claims *idTokenClaims) *auth.IdentityClaims {
+	identity := &auth.IdentityClaims{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}
+	if identity.Email == "" && p.conf.EmailDomain != "" {
+		identity.Email = strings.ToLower(claims.Subject) + "@" + p.conf.EmailDomain
+		identity.EmailVerified = true
+	}
+	return identity
+}
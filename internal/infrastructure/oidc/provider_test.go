@@ -0,0 +1,186 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/config"
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/oidc/oidctest"
+)
+
+const testRedirectURL = "http://localhost:8080/api/v1/auth/oauth/test/callback"
+
+// authorize follows the authorization URL and returns the code and state of the redirect back to us
+func authorize(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorization request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization request returned status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect: %v", err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func newTestServer(t *testing.T) *oidctest.Server {
+	server := oidctest.New("jcourse", "secret", oidctest.Identity{
+		Subject:       "alice",
+		Email:         "Alice@Example.com",
+		EmailVerified: true,
+		Name:          "Alice",
+	}).Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	server := newTestServer(t)
+	provider := NewProvider(server.ProviderConfig("test", testRedirectURL), nil)
+	state := auth.NewOAuthState("test", time.Now())
+
+	code, returnedState := authorize(t, provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+	assert.Equal(t, state.State, returnedState)
+
+	claims, err := provider.Exchange(context.Background(), code, state.CodeVerifier, state.Nonce)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "alice", claims.Subject)
+		assert.Equal(t, "alice@example.com", claims.Email)
+		assert.True(t, claims.EmailVerified)
+		assert.Equal(t, "Alice", claims.Name)
+	}
+}
+
+func TestProvider_ExchangeRejectsWrongVerifier(t *testing.T) {
+	server := newTestServer(t)
+	provider := NewProvider(server.ProviderConfig("test", testRedirectURL), nil)
+	state := auth.NewOAuthState("test", time.Now())
+
+	code, _ := authorize(t, provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+	_, err := provider.Exchange(context.Background(), code, "not-the-verifier", state.Nonce)
+
+	assert.Error(t, err)
+}
+
+func TestProvider_ExchangeRejectsWrongNonce(t *testing.T) {
+	server := newTestServer(t)
+	provider := NewProvider(server.ProviderConfig("test", testRedirectURL), nil)
+	state := auth.NewOAuthState("test", time.Now())
+
+	code, _ := authorize(t, provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+	_, err := provider.Exchange(context.Background(), code, state.CodeVerifier, "another-nonce")
+
+	assert.True(t, errors.Is(err, ErrInvalidIDToken))
+}
+
+func TestProvider_ExchangeCodeIsSingleUse(t *testing.T) {
+	server := newTestServer(t)
+	provider := NewProvider(server.ProviderConfig("test", testRedirectURL), nil)
+	state := auth.NewOAuthState("test", time.Now())
+
+	code, _ := authorize(t, provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+	_, err := provider.Exchange(context.Background(), code, state.CodeVerifier, state.Nonce)
+	assert.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, state.CodeVerifier, state.Nonce)
+	assert.Error(t, err)
+}
+
+func TestProvider_ExchangeRejectsWrongClient(t *testing.T) {
+	server := newTestServer(t)
+	conf := server.ProviderConfig("test", testRedirectURL)
+	conf.ClientSecret = "guess"
+	provider := NewProvider(conf, nil)
+	state := auth.NewOAuthState("test", time.Now())
+
+	code, _ := authorize(t, provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+	_, err := provider.Exchange(context.Background(), code, state.CodeVerifier, state.Nonce)
+
+	assert.Error(t, err)
+}
+
+// hs256Token signs the claims with the secret, as providers without a key set do
+func hs256Token(alg string, secret string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	provider := NewProvider(config.OIDCProviderConfig{Name: ProviderJAccount, ClientID: "jcourse", ClientSecret: "secret"}, nil)
+	now := time.Now()
+	valid := map[string]interface{}{
+		"sub":   "student",
+		"aud":   []string{"jcourse"},
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": "nonce",
+	}
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := make(map[string]interface{}, len(valid))
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", hs256Token("HS256", "secret", valid), true},
+		{"wrong secret", hs256Token("HS256", "guess", valid), false},
+		{"unsigned", hs256Token("none", "secret", valid), false},
+		{"other audience", hs256Token("HS256", "secret", with("aud", "someone-else")), false},
+		{"expired", hs256Token("HS256", "secret", with("exp", now.Add(-time.Hour).Unix())), false},
+		{"wrong nonce", hs256Token("HS256", "secret", with("nonce", "replayed")), false},
+		{"malformed", "not-a-token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := provider.verifyIDToken(context.Background(), tt.token, "nonce", now)
+			if tt.valid {
+				if assert.NoError(t, err) {
+					assert.Equal(t, "student", claims.Subject)
+				}
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidIDToken), err)
+			}
+		})
+	}
+}
+
+func TestProvider_JAccountDerivesEmail(t *testing.T) {
+	provider := NewProvider(config.OIDCProviderConfig{Name: ProviderJAccount, ClientID: "jcourse"}, nil)
+
+	claims := provider.identityClaims(&idTokenClaims{Subject: "Student", Name: "Student"})
+
+	assert.Equal(t, "student@sjtu.edu.cn", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Contains(t, provider.AuthCodeURL("s", "n", "c"), "https://jaccount.sjtu.edu.cn/oauth2/authorize?")
+}
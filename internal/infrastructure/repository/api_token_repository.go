@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) auth.APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *auth.APIToken) error {
+	row := r.toORMAPIToken(token)
+	if err := database.Conn(ctx, r.db).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+	token.ID = row.ID
+	return nil
+}
+
+func (r *apiTokenRepository) GetByToken(ctx context.Context, secret string) (*auth.APIToken, error) {
+	tokenHash := auth.HashSessionToken(secret)
+
+	var row entity.APIToken
+	err := database.Conn(ctx, r.db).Where("token = ?", tokenHash).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(row.Token), []byte(tokenHash)) != 1 {
+		return nil, nil
+	}
+
+	return r.toDomainAPIToken(&row), nil
+}
+
+func (r *apiTokenRepository) ListByUser(ctx context.Context, userID int) ([]auth.APIToken, error) {
+	var rows []entity.APIToken
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+
+	tokens := make([]auth.APIToken, 0, len(rows))
+	for i := range rows {
+		tokens = append(tokens, *r.toDomainAPIToken(&rows[i]))
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) CountByUser(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	if err := database.Conn(ctx, r.db).Model(&entity.APIToken{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count api tokens: %w", err)
+	}
+	return count, nil
+}
+
+func (r *apiTokenRepository) Delete(ctx context.Context, userID int, id int) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).Delete(&entity.APIToken{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete api token: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *apiTokenRepository) DeleteByUser(ctx context.Context, userID int) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&entity.APIToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete api tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, id int, at time.Time) error {
+	err := database.Conn(ctx, r.db).Model(&entity.APIToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to touch api token: %w", err)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) toORMAPIToken(token *auth.APIToken) entity.APIToken {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return entity.APIToken{
+		ID:         token.ID,
+		UserID:     token.UserID,
+		Name:       token.Name,
+		Token:      token.TokenHash,
+		Scopes:     strings.Join(scopes, ","),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func (r *apiTokenRepository) toDomainAPIToken(row *entity.APIToken) *auth.APIToken {
+	var scopes []common.Scope
+	if row.Scopes != "" {
+		for _, scope := range strings.Split(row.Scopes, ",") {
+			scopes = append(scopes, common.Scope(scope))
+		}
+	}
+	return &auth.APIToken{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		TokenHash:  row.Token,
+		Scopes:     scopes,
+		ExpiresAt:  row.ExpiresAt,
+		LastUsedAt: row.LastUsedAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
@@ -20,9 +20,9 @@ func NewCodeRepository(db *gorm.DB) auth.CodeRepository {
 	return &codeRepository{db: db}
 }
 
-func (r *codeRepository) Get(ctx context.Context, email string) (*auth.VerificationCode, error) {
+func (r *codeRepository) Get(ctx context.Context, email string, purpose auth.CodePurpose) (*auth.VerificationCode, error) {
 	var codeEntity entity.VerificationCode
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&codeEntity)
+	result := r.db.WithContext(ctx).Where("email = ? AND purpose = ?", email, purpose).First(&codeEntity)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -34,7 +34,7 @@ func (r *codeRepository) Get(ctx context.Context, email string) (*auth.Verificat
 
 func (r *codeRepository) Save(ctx context.Context, code *auth.VerificationCode) error {
 	codeEntity := r.toORMCode(code)
-	result := r.db.WithContext(ctx).Where("email = ?", code.Email).First(&entity.VerificationCode{})
+	result := r.db.WithContext(ctx).Where("email = ? AND purpose = ?", code.Email, code.Purpose).First(&entity.VerificationCode{})
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			result = r.db.WithContext(ctx).Create(codeEntity)
@@ -48,18 +48,35 @@ func (r *codeRepository) Save(ctx context.Context, code *auth.VerificationCode)
 
 	updateData := map[string]interface{}{
 		"code":       code.Code,
+		"attempts":   0,
 		"expires_at": code.ExpiresAt,
 		"created_at": time.Now(),
 	}
-	result = r.db.WithContext(ctx).Model(&entity.VerificationCode{}).Where("email = ?", code.Email).Updates(updateData)
+	result = r.db.WithContext(ctx).Model(&entity.VerificationCode{}).Where("email = ? AND purpose = ?", code.Email, code.Purpose).Updates(updateData)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update verification code: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *codeRepository) IncrementAttempts(ctx context.Context, code *auth.VerificationCode) (int, error) {
+	var attempts []int
+	result := r.db.WithContext(ctx).
+		Raw("UPDATE verification_codes SET attempts = attempts + 1 WHERE email = ? AND purpose = ? AND deleted_at IS NULL RETURNING attempts",
+			code.Email, code.Purpose).
+		Scan(&attempts)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to update verification code attempts: %w", result.Error)
+	}
+	if len(attempts) == 0 {
+		return 0, nil
+	}
+	return attempts[0], nil
+}
+
+// Delete removes the row for good, as a soft-deleted row would still occupy the unique (email, purpose) index
 func (r *codeRepository) Delete(ctx context.Context, code *auth.VerificationCode) error {
-	result := r.db.WithContext(ctx).Where("email = ?", code.Email).Delete(&entity.VerificationCode{})
+	result := r.db.WithContext(ctx).Unscoped().Where("email = ? AND purpose = ?", code.Email, code.Purpose).Delete(&entity.VerificationCode{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete verification code: %w", result.Error)
 	}
@@ -70,7 +87,9 @@ func (r *codeRepository) Delete(ctx context.Context, code *auth.VerificationCode
 func (r *codeRepository) toDomainCode(codeEntity *entity.VerificationCode) *auth.VerificationCode {
 	return &auth.VerificationCode{
 		Email:     codeEntity.Email,
+		Purpose:   auth.CodePurpose(codeEntity.Purpose),
 		Code:      codeEntity.Code,
+		Attempts:  codeEntity.Attempts,
 		ExpiresAt: codeEntity.ExpiresAt,
 		CreatedAt: codeEntity.CreatedAt,
 	}
@@ -79,7 +98,9 @@ func (r *codeRepository) toDomainCode(codeEntity *entity.VerificationCode) *auth
 func (r *codeRepository) toORMCode(code *auth.VerificationCode) *entity.VerificationCode {
 	return &entity.VerificationCode{
 		Email:     code.Email,
+		Purpose:   string(code.Purpose),
 		Code:      code.Code,
+		Attempts:  code.Attempts,
 		ExpiresAt: code.ExpiresAt,
 		CreatedAt: code.CreatedAt,
 	}
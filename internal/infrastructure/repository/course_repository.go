@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
 	"jcourse_go/internal/domain/review"
+	"jcourse_go/internal/infrastructure/database"
 	"jcourse_go/internal/infrastructure/entity"
 )
 
@@ -21,7 +23,7 @@ func NewCourseRepository(db *gorm.DB) review.CourseRepository {
 
 func (r *courseRepository) Get(ctx context.Context, id int) (*review.Course, error) {
 	var courseEntity entity.Course
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Preload("MainTeacher").
 		First(&courseEntity, id)
 	if result.Error != nil {
@@ -35,7 +37,7 @@ func (r *courseRepository) Get(ctx context.Context, id int) (*review.Course, err
 
 func (r *courseRepository) FindBy(ctx context.Context, filter review.CourseFilter) ([]review.Course, error) {
 	var courseEntities []entity.Course
-	query := r.db.WithContext(ctx).Preload("MainTeacher")
+	query := database.Conn(ctx, r.db).Preload("MainTeacher")
 
 	if filter.MainTeacherID != nil {
 		query = query.Where("main_teacher_id = ?", *filter.MainTeacherID)
@@ -70,7 +72,7 @@ func (r *courseRepository) FindBy(ctx context.Context, filter review.CourseFilte
 
 func (r *courseRepository) Save(ctx context.Context, course *review.Course) error {
 	courseEntity := r.toORMCourse(course)
-	result := r.db.WithContext(ctx).Save(courseEntity)
+	result := database.Conn(ctx, r.db).Save(courseEntity)
 	if result.Error != nil {
 		return fmt.Errorf("failed to save course: %w", result.Error)
 	}
@@ -78,7 +80,7 @@ func (r *courseRepository) Save(ctx context.Context, course *review.Course) erro
 }
 
 func (r *courseRepository) Delete(ctx context.Context, filter review.CourseFilter) error {
-	query := r.db.WithContext(ctx)
+	query := database.Conn(ctx, r.db)
 	if filter.MainTeacherID != nil {
 		query = query.Where("main_teacher_id = ?", *filter.MainTeacherID)
 	}
@@ -96,7 +98,7 @@ func (r *courseRepository) Delete(ctx context.Context, filter review.CourseFilte
 
 func (r *courseRepository) FindOfferedCourse(ctx context.Context, courseID int, semester review.Semester) (*review.OfferedCourse, error) {
 	var offeredCourse review.OfferedCourse
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Where("course_id = ? AND semester = ?", courseID, semester).
 		First(&offeredCourse)
 	if result.Error != nil {
@@ -110,7 +112,7 @@ func (r *courseRepository) FindOfferedCourse(ctx context.Context, courseID int,
 
 func (r *courseRepository) GetDepartments(ctx context.Context) ([]string, error) {
 	var departments []string
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Model(&entity.User{}).
 		Distinct("department").
 		Find(&departments)
@@ -122,7 +124,7 @@ func (r *courseRepository) GetDepartments(ctx context.Context) ([]string, error)
 
 func (r *courseRepository) GetCategories(ctx context.Context) ([]string, error) {
 	var categories []string
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Table("offered_courses").
 		Distinct("unnest(categories)").
 		Find(&categories)
@@ -134,7 +136,7 @@ func (r *courseRepository) GetCategories(ctx context.Context) ([]string, error)
 
 func (r *courseRepository) GetUserEnrolledCourses(ctx context.Context, userID int) ([]int, error) {
 	var courseIDs []int
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Table("user_enrolled_courses").
 		Where("user_id = ?", userID).
 		Pluck("course_id", &courseIDs)
@@ -145,7 +147,7 @@ func (r *courseRepository) GetUserEnrolledCourses(ctx context.Context, userID in
 }
 
 func (r *courseRepository) AddUserEnrolledCourse(ctx context.Context, userID int, courseID int) error {
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Create(&entity.UserEnrolledCourse{
 			UserID:   userID,
 			CourseID: courseID,
@@ -157,25 +159,26 @@ func (r *courseRepository) AddUserEnrolledCourse(ctx context.Context, userID int
 	return nil
 }
 
-func (r *courseRepository) WatchCourse(ctx context.Context, userID int, courseID int, watch bool) error {
+func (r *courseRepository) WatchCourse(ctx context.Context, userID int, courseID int, watch bool) (bool, error) {
 	if watch {
-		result := r.db.WithContext(ctx).
-			Create(&entity.CourseWatch{
-				UserID:   userID,
-				CourseID: courseID,
-			})
+		now := time.Now()
+		result := database.Conn(ctx, r.db).Exec(`INSERT INTO course_watches (user_id, course_id, created_at, updated_at)
+			SELECT ?, ?, ?, ? WHERE NOT EXISTS (
+				SELECT 1 FROM course_watches WHERE user_id = ? AND course_id = ? AND deleted_at IS NULL)`,
+			userID, courseID, now, now, userID, courseID)
 		if result.Error != nil {
-			return fmt.Errorf("failed to watch course: %w", result.Error)
-		}
-	} else {
-		result := r.db.WithContext(ctx).
-			Where("user_id = ? AND course_id = ?", userID, courseID).
-			Delete(&entity.CourseWatch{})
-		if result.Error != nil {
-			return fmt.Errorf("failed to unwatch course: %w", result.Error)
+			return false, fmt.Errorf("failed to watch course: %w", result.Error)
 		}
+		return result.RowsAffected > 0, nil
 	}
-	return nil
+
+	result := database.Conn(ctx, r.db).
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Delete(&entity.CourseWatch{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to unwatch course: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
 }
 
 // Helper methods to convert between domain and ORM models
@@ -0,0 +1,33 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+func TestCourseRepository_WatchCourseReportsChanges(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewCourseRepository(db)
+	ctx := context.Background()
+	userID, courseID := int(time.Now().UnixNano()%1_000_000_000), 1
+
+	for _, step := range []struct {
+		watch   bool
+		changed bool
+	}{{true, true}, {true, false}, {false, true}, {false, false}, {true, true}} {
+		changed, err := repo.WatchCourse(ctx, userID, courseID, step.watch)
+		assert.NoError(t, err)
+		assert.Equal(t, step.changed, changed, "watch=%v", step.watch)
+	}
+
+	var count int64
+	assert.NoError(t, db.Model(&entity.CourseWatch{}).Where("user_id = ? AND course_id = ?", userID, courseID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
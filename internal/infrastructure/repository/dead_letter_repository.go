@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type deadLetterRepository struct {
+	db *gorm.DB
+}
+
+func NewDeadLetterRepository(db *gorm.DB) event.DeadLetterRepository {
+	return &deadLetterRepository{db: db}
+}
+
+func (r *deadLetterRepository) Save(ctx context.Context, deadLetter *event.DeadLetter) error {
+	deadLetterEntity := r.toORMDeadLetter(deadLetter)
+
+	result := database.Conn(ctx, r.db).Save(deadLetterEntity)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save dead letter: %w", result.Error)
+	}
+
+	deadLetter.ID = deadLetterEntity.ID
+	return nil
+}
+
+func (r *deadLetterRepository) Get(ctx context.Context, id int64) (*event.DeadLetter, error) {
+	var deadLetterEntity entity.DeadLetter
+	result := database.Conn(ctx, r.db).First(&deadLetterEntity, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get dead letter: %w", result.Error)
+	}
+	return r.toDomainDeadLetter(&deadLetterEntity), nil
+}
+
+func (r *deadLetterRepository) List(ctx context.Context, filter event.DeadLetterFilter) ([]event.DeadLetter, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&entity.DeadLetter{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.EventType != nil {
+		query = query.Where("event_type = ?", string(*filter.EventType))
+	}
+	if filter.Handler != "" {
+		query = query.Where("handler = ?", filter.Handler)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	var deadLetterEntities []entity.DeadLetter
+	result := query.
+		Order("id DESC").
+		Offset(filter.Pagination.Offset()).
+		Limit(filter.Pagination.Size).
+		Find(&deadLetterEntities)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to list dead letters: %w", result.Error)
+	}
+
+	deadLetters := make([]event.DeadLetter, len(deadLetterEntities))
+	for i, deadLetterEntity := range deadLetterEntities {
+		deadLetters[i] = *r.toDomainDeadLetter(&deadLetterEntity)
+	}
+	return deadLetters, total, nil
+}
+
+// Helper methods to convert between domain and ORM models
+func (r *deadLetterRepository) toDomainDeadLetter(deadLetterEntity *entity.DeadLetter) *event.DeadLetter {
+	return &event.DeadLetter{
+		ID:         deadLetterEntity.ID,
+		EventID:    deadLetterEntity.EventID,
+		EventType:  event.Type(deadLetterEntity.EventType),
+		Version:    deadLetterEntity.Version,
+		Handler:    deadLetterEntity.Handler,
+		Payload:    []byte(deadLetterEntity.Payload),
+		OccurredAt: deadLetterEntity.OccurredAt,
+		Status:     event.DeadLetterStatus(deadLetterEntity.Status),
+		Attempts:   deadLetterEntity.Attempts,
+		LastError:  deadLetterEntity.LastError,
+		CreatedAt:  deadLetterEntity.CreatedAt,
+		UpdatedAt:  deadLetterEntity.UpdatedAt,
+	}
+}
+
+func (r *deadLetterRepository) toORMDeadLetter(deadLetter *event.DeadLetter) *entity.DeadLetter {
+	return &entity.DeadLetter{
+		ID:         deadLetter.ID,
+		EventID:    deadLetter.EventID,
+		EventType:  string(deadLetter.EventType),
+		Version:    deadLetter.Version,
+		Handler:    deadLetter.Handler,
+		Payload:    string(deadLetter.Payload),
+		OccurredAt: deadLetter.OccurredAt,
+		Status:     string(deadLetter.Status),
+		Attempts:   deadLetter.Attempts,
+		LastError:  deadLetter.LastError,
+		CreatedAt:  deadLetter.CreatedAt,
+		UpdatedAt:  deadLetter.UpdatedAt,
+	}
+}
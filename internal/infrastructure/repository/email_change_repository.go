@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) auth.EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+func (r *emailChangeRepository) Create(ctx context.Context, change *auth.EmailChange) error {
+	row := r.toORMEmailChange(change)
+	if err := database.Conn(ctx, r.db).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to create email change: %w", err)
+	}
+	change.ID = row.ID
+	return nil
+}
+
+func (r *emailChangeRepository) GetByRevertToken(ctx context.Context, token string) (*auth.EmailChange, error) {
+	var row entity.EmailChange
+	err := database.Conn(ctx, r.db).Where("revert_token = ?", auth.HashSessionToken(token)).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email change: %w", err)
+	}
+	return r.toDomainEmailChange(&row), nil
+}
+
+func (r *emailChangeRepository) Update(ctx context.Context, change *auth.EmailChange) error {
+	if err := database.Conn(ctx, r.db).Save(r.toORMEmailChange(change)).Error; err != nil {
+		return fmt.Errorf("failed to update email change: %w", err)
+	}
+	return nil
+}
+
+func (r *emailChangeRepository) toDomainEmailChange(row *entity.EmailChange) *auth.EmailChange {
+	return &auth.EmailChange{
+		ID:              row.ID,
+		UserID:          row.UserID,
+		OldEmail:        row.OldEmail,
+		NewEmail:        row.NewEmail,
+		RevertTokenHash: row.RevertToken,
+		ExpiresAt:       row.ExpiresAt,
+		RevertedAt:      row.RevertedAt,
+		CreatedAt:       row.CreatedAt,
+	}
+}
+
+func (r *emailChangeRepository) toORMEmailChange(change *auth.EmailChange) *entity.EmailChange {
+	return &entity.EmailChange{
+		ID:          change.ID,
+		UserID:      change.UserID,
+		OldEmail:    change.OldEmail,
+		NewEmail:    change.NewEmail,
+		RevertToken: change.RevertTokenHash,
+		ExpiresAt:   change.ExpiresAt,
+		RevertedAt:  change.RevertedAt,
+		CreatedAt:   change.CreatedAt,
+	}
+}
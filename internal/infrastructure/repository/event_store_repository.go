@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type eventStoreRepository struct {
+	db *gorm.DB
+}
+
+func NewEventStoreRepository(db *gorm.DB) event.EventStoreRepository {
+	return &eventStoreRepository{db: db}
+}
+
+func (r *eventStoreRepository) Append(ctx context.Context, storedEvent *event.StoredEvent) error {
+	storedEventEntity := r.toORMStoredEvent(storedEvent)
+
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit("Aggregates").
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_id"}}, DoNothing: true}).
+			Create(storedEventEntity)
+		if result.Error != nil {
+			return fmt.Errorf("failed to append event %s: %w", storedEvent.EventID, result.Error)
+		}
+		// The event is already in the store
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		storedEvent.ID = storedEventEntity.ID
+		if len(storedEventEntity.Aggregates) == 0 {
+			return nil
+		}
+		for i := range storedEventEntity.Aggregates {
+			storedEventEntity.Aggregates[i].StoredEventID = storedEventEntity.ID
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&storedEventEntity.Aggregates).Error; err != nil {
+			return fmt.Errorf("failed to append aggregates of event %s: %w", storedEvent.EventID, err)
+		}
+		return nil
+	})
+}
+
+func (r *eventStoreRepository) List(ctx context.Context, filter event.EventStoreFilter) ([]event.StoredEvent, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&entity.StoredEvent{})
+	if filter.Aggregate != nil {
+		query = query.Where(
+			"id IN (SELECT stored_event_id FROM stored_event_aggregates WHERE aggregate_type = ? AND aggregate_id = ?)",
+			string(filter.Aggregate.Type), filter.Aggregate.ID,
+		)
+	}
+	if filter.EventType != nil {
+		query = query.Where("event_type = ?", string(*filter.EventType))
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count stored events: %w", err)
+	}
+
+	var storedEventEntities []entity.StoredEvent
+	result := query.
+		Preload("Aggregates").
+		Order("occurred_at ASC, id ASC").
+		Offset(filter.Pagination.Offset()).
+		Limit(filter.Pagination.Size).
+		Find(&storedEventEntities)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to list stored events: %w", result.Error)
+	}
+
+	storedEvents := make([]event.StoredEvent, len(storedEventEntities))
+	for i, storedEventEntity := range storedEventEntities {
+		storedEvents[i] = *r.toDomainStoredEvent(&storedEventEntity)
+	}
+	return storedEvents, total, nil
+}
+
+// Helper methods to convert between domain and ORM models
+func (r *eventStoreRepository) toDomainStoredEvent(storedEventEntity *entity.StoredEvent) *event.StoredEvent {
+	aggregates := make([]event.AggregateRef, len(storedEventEntity.Aggregates))
+	for i, aggregate := range storedEventEntity.Aggregates {
+		aggregates[i] = event.AggregateRef{
+			Type: event.AggregateType(aggregate.AggregateType),
+			ID:   aggregate.AggregateID,
+		}
+	}
+
+	return &event.StoredEvent{
+		ID:         storedEventEntity.ID,
+		EventID:    storedEventEntity.EventID,
+		EventType:  event.Type(storedEventEntity.EventType),
+		Version:    storedEventEntity.Version,
+		Payload:    []byte(storedEventEntity.Payload),
+		Aggregates: aggregates,
+		OccurredAt: storedEventEntity.OccurredAt,
+		StoredAt:   storedEventEntity.StoredAt,
+	}
+}
+
+func (r *eventStoreRepository) toORMStoredEvent(storedEvent *event.StoredEvent) *entity.StoredEvent {
+	aggregates := make([]entity.StoredEventAggregate, len(storedEvent.Aggregates))
+	for i, aggregate := range storedEvent.Aggregates {
+		aggregates[i] = entity.StoredEventAggregate{
+			AggregateType: string(aggregate.Type),
+			AggregateID:   aggregate.ID,
+		}
+	}
+
+	return &entity.StoredEvent{
+		ID:         storedEvent.ID,
+		EventID:    storedEvent.EventID,
+		EventType:  string(storedEvent.EventType),
+		Version:    storedEvent.Version,
+		Payload:    string(storedEvent.Payload),
+		OccurredAt: storedEvent.OccurredAt,
+		StoredAt:   storedEvent.StoredAt,
+		Aggregates: aggregates,
+	}
+}
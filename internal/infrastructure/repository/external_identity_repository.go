@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type externalIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalIdentityRepository(db *gorm.DB) auth.ExternalIdentityRepository {
+	return &externalIdentityRepository{db: db}
+}
+
+func (r *externalIdentityRepository) Get(ctx context.Context, provider string, subject string) (*auth.ExternalIdentity, error) {
+	var identity entity.ExternalIdentity
+	err := database.Conn(ctx, r.db).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get external identity: %w", err)
+	}
+
+	return &auth.ExternalIdentity{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}, nil
+}
+
+func (r *externalIdentityRepository) Save(ctx context.Context, identity *auth.ExternalIdentity) error {
+	row := entity.ExternalIdentity{
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+	if err := database.Conn(ctx, r.db).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to save external identity: %w", err)
+	}
+	identity.ID = row.ID
+	return nil
+}
+
+type oauthStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthStateRepository(db *gorm.DB) auth.OAuthStateRepository {
+	return &oauthStateRepository{db: db}
+}
+
+// Save also clears out the abandoned requests, which are never taken
+func (r *oauthStateRepository) Save(ctx context.Context, state *auth.OAuthState) error {
+	conn := database.Conn(ctx, r.db)
+	if err := conn.Where("expires_at < ?", time.Now()).Delete(&entity.OAuthState{}).Error; err != nil {
+		return fmt.Errorf("failed to purge expired oauth states: %w", err)
+	}
+
+	row := entity.OAuthState{
+		State:        state.State,
+		Provider:     state.Provider,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		ExpiresAt:    state.ExpiresAt,
+		CreatedAt:    state.CreatedAt,
+	}
+	if err := conn.Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to save oauth state: %w", err)
+	}
+	return nil
+}
+
+// Take deletes the state and returns the deleted row, so concurrent callbacks cannot both use it
+func (r *oauthStateRepository) Take(ctx context.Context, state string) (*auth.OAuthState, error) {
+	var rows []entity.OAuthState
+	result := database.Conn(ctx, r.db).
+		Clauses(clause.Returning{}).
+		Where("state = ?", state).
+		Delete(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to take oauth state: %w", result.Error)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	row := rows[0]
+	return &auth.OAuthState{
+		State:        row.State,
+		Provider:     row.Provider,
+		Nonce:        row.Nonce,
+		CodeVerifier: row.CodeVerifier,
+		CreatedAt:    row.CreatedAt,
+		ExpiresAt:    row.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type failureCounterRepository struct {
+	db *gorm.DB
+}
+
+func NewFailureCounterRepository(db *gorm.DB) auth.FailureCounterRepository {
+	return &failureCounterRepository{db: db}
+}
+
+func (r *failureCounterRepository) Get(ctx context.Context, key auth.ThrottleKey) (*auth.FailureCounter, error) {
+	var row entity.AuthFailure
+	err := database.Conn(ctx, r.db).Where("throttle_key = ?", string(key)).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get auth failures: %w", err)
+	}
+
+	return &auth.FailureCounter{
+		Key:           auth.ThrottleKey(row.Key),
+		Failures:      row.Failures,
+		LockedUntil:   row.LockedUntil,
+		LastFailureAt: row.LastFailureAt,
+	}, nil
+}
+
+func (r *failureCounterRepository) RecordFailure(ctx context.Context, key auth.ThrottleKey, policy auth.ThrottlePolicy, now time.Time) (*auth.FailureCounter, error) {
+	// The count is raised in place, so every concurrent failure is counted; it restarts after a quiet window
+	// unless the key is still locked out
+	var row entity.AuthFailure
+	err := database.Conn(ctx, r.db).Raw(`INSERT INTO auth_failures (throttle_key, failures, last_failure_at) VALUES (?, 1, ?)
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failures = CASE
+				WHEN auth_failures.last_failure_at < ? AND (auth_failures.locked_until IS NULL OR auth_failures.locked_until <= ?) THEN 1
+				ELSE auth_failures.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`, string(key), now, now.Add(-policy.Window), now).Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to record auth failure: %w", err)
+	}
+
+	counter := &auth.FailureCounter{
+		Key:           auth.ThrottleKey(row.Key),
+		Failures:      row.Failures,
+		LockedUntil:   row.LockedUntil,
+		LastFailureAt: row.LastFailureAt,
+	}
+	lockout := policy.Lockout(counter.Failures)
+	if lockout == 0 {
+		return counter, nil
+	}
+
+	// Concurrent failures may finish in any order, so a lockout only ever grows
+	lockedUntil := now.Add(lockout)
+	err = database.Conn(ctx, r.db).Model(&entity.AuthFailure{}).
+		Where("throttle_key = ? AND (locked_until IS NULL OR locked_until < ?)", string(key), lockedUntil).
+		Update("locked_until", lockedUntil).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock out %s: %w", key, err)
+	}
+	if counter.LockedUntil == nil || lockedUntil.After(*counter.LockedUntil) {
+		counter.LockedUntil = &lockedUntil
+	}
+	return counter, nil
+}
+
+func (r *failureCounterRepository) Delete(ctx context.Context, key auth.ThrottleKey) error {
+	if err := database.Conn(ctx, r.db).Where("throttle_key = ?", string(key)).Delete(&entity.AuthFailure{}).Error; err != nil {
+		return fmt.Errorf("failed to delete auth failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build integration
+
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"jcourse_go/internal/infrastructure/migrations"
+)
+
+// openTestDB connects to the throwaway Postgres database in TEST_DATABASE_DSN and migrates it:
+//
+//	TEST_DATABASE_DSN=postgres://... go test -tags integration ./internal/infrastructure/...
+func openTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, migrations.Migrate(db)) {
+		t.FailNow()
+	}
+	return db
+}
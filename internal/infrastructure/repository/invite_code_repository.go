@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type inviteCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewInviteCodeRepository(db *gorm.DB) auth.InviteCodeRepository {
+	return &inviteCodeRepository{db: db}
+}
+
+func (r *inviteCodeRepository) Create(ctx context.Context, invite *auth.InviteCode) error {
+	row := entity.InviteCode{
+		Code:      invite.Code,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedBy: invite.CreatedBy,
+		CreatedAt: invite.CreatedAt,
+	}
+	if err := database.Conn(ctx, r.db).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to create invite code: %w", err)
+	}
+	invite.ID = row.ID
+	return nil
+}
+
+func (r *inviteCodeRepository) Get(ctx context.Context, code string) (*auth.InviteCode, error) {
+	var row entity.InviteCode
+	err := database.Conn(ctx, r.db).Where("code = ?", auth.NormalizeInviteCode(code)).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get invite code: %w", err)
+	}
+	return r.toDomainInviteCode(&row), nil
+}
+
+func (r *inviteCodeRepository) List(ctx context.Context, pagination common.Pagination) ([]auth.InviteCode, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&entity.InviteCode{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count invite codes: %w", err)
+	}
+
+	var rows []entity.InviteCode
+	err := query.Order("created_at DESC, id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Size).
+		Find(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list invite codes: %w", err)
+	}
+
+	invites := make([]auth.InviteCode, 0, len(rows))
+	for i := range rows {
+		invites = append(invites, *r.toDomainInviteCode(&rows[i]))
+	}
+	return invites, total, nil
+}
+
+// Redeem counts the use in a single conditional update, so concurrent registrations cannot exceed the limit
+func (r *inviteCodeRepository) Redeem(ctx context.Context, id int) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&entity.InviteCode{}).
+		Where("id = ? AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)", id, time.Now()).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to redeem invite code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *inviteCodeRepository) Delete(ctx context.Context, id int) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&entity.InviteCode{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete invite code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *inviteCodeRepository) toDomainInviteCode(row *entity.InviteCode) *auth.InviteCode {
+	return &auth.InviteCode{
+		ID:        row.ID,
+		Code:      row.Code,
+		MaxUses:   row.MaxUses,
+		Uses:      row.Uses,
+		ExpiresAt: row.ExpiresAt,
+		CreatedBy: row.CreatedBy,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) event.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Save(ctx context.Context, messages ...event.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	messageEntities := make([]entity.OutboxMessage, len(messages))
+	for i := range messages {
+		messageEntities[i] = *r.toORMMessage(&messages[i])
+	}
+
+	result := database.Conn(ctx, r.db).Create(&messageEntities)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save outbox messages: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *outboxRepository) FetchPending(ctx context.Context, limit int) ([]event.OutboxMessage, error) {
+	var messageEntities []entity.OutboxMessage
+	// SKIP LOCKED lets several relays drain the outbox without handing out the same row twice
+	result := database.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", string(event.OutboxStatusPending)).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messageEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to fetch pending outbox messages: %w", result.Error)
+	}
+
+	messages := make([]event.OutboxMessage, len(messageEntities))
+	for i, messageEntity := range messageEntities {
+		messages[i] = *r.toDomainMessage(&messageEntity)
+	}
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64) error {
+	now := time.Now()
+	result := database.Conn(ctx, r.db).
+		Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":   string(event.OutboxStatusSent),
+			"attempts": gorm.Expr("attempts + 1"),
+			"sent_at":  now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark outbox message as sent: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, giveUp bool) error {
+	status := event.OutboxStatusPending
+	if giveUp {
+		status = event.OutboxStatusFailed
+	}
+	result := database.Conn(ctx, r.db).
+		Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     string(status),
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark outbox message as failed: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).
+		Where("status = ? AND sent_at < ?", string(event.OutboxStatusSent), before).
+		Delete(&entity.OutboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox messages: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Helper methods to convert between domain and ORM models
+func (r *outboxRepository) toDomainMessage(messageEntity *entity.OutboxMessage) *event.OutboxMessage {
+	return &event.OutboxMessage{
+		ID:         messageEntity.ID,
+		EventID:    messageEntity.EventID,
+		EventType:  event.Type(messageEntity.EventType),
+		Version:    messageEntity.Version,
+		Payload:    []byte(messageEntity.Payload),
+		OccurredAt: messageEntity.OccurredAt,
+		Status:     event.OutboxStatus(messageEntity.Status),
+		Attempts:   messageEntity.Attempts,
+		LastError:  messageEntity.LastError,
+		SentAt:     messageEntity.SentAt,
+		CreatedAt:  messageEntity.CreatedAt,
+	}
+}
+
+func (r *outboxRepository) toORMMessage(message *event.OutboxMessage) *entity.OutboxMessage {
+	return &entity.OutboxMessage{
+		ID:         message.ID,
+		EventID:    message.EventID,
+		EventType:  string(message.EventType),
+		Version:    message.Version,
+		Payload:    string(message.Payload),
+		OccurredAt: message.OccurredAt,
+		Status:     string(message.Status),
+		Attempts:   message.Attempts,
+		LastError:  message.LastError,
+		SentAt:     message.SentAt,
+		CreatedAt:  message.CreatedAt,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"jcourse_go/internal/domain/point"
+	"jcourse_go/internal/infrastructure/database"
 	"jcourse_go/internal/infrastructure/entity"
 )
 
@@ -21,7 +22,7 @@ func NewUserPointRepository(db *gorm.DB) point.UserPointRepository {
 
 func (r *userPointRepository) GetUserAllPoints(ctx context.Context, userID int) (*point.UserPoint, error) {
 	var recordEntities []entity.UserPointRecord
-	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&recordEntities)
+	result := database.Conn(ctx, r.db).Where("user_id = ?", userID).Find(&recordEntities)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get user point records: %w", result.Error)
 	}
@@ -41,7 +42,7 @@ func (r *userPointRepository) GetUserAllPoints(ctx context.Context, userID int)
 
 func (r *userPointRepository) GetPointRecord(ctx context.Context, itemID int) (*point.UserPointRecord, error) {
 	var recordEntity entity.UserPointRecord
-	result := r.db.WithContext(ctx).First(&recordEntity, itemID)
+	result := database.Conn(ctx, r.db).First(&recordEntity, itemID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -53,26 +54,42 @@ func (r *userPointRepository) GetPointRecord(ctx context.Context, itemID int) (*
 
 func (r *userPointRepository) Save(ctx context.Context, point *point.UserPointRecord) error {
 	pointEntity := r.toORMPointRecord(point)
-	result := r.db.WithContext(ctx).Create(pointEntity)
+	result := database.Conn(ctx, r.db).Create(pointEntity)
 	if result.Error != nil {
 		return fmt.Errorf("failed to save point record: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *userPointRepository) SumReviewPoints(ctx context.Context, userID int, reviewID int) (int, error) {
+	var total int
+	result := database.Conn(ctx, r.db).Model(&entity.UserPointRecord{}).
+		Where("user_id = ? AND review_id = ?", userID, reviewID).
+		Select("COALESCE(SUM(point), 0)").
+		Scan(&total)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to sum review points: %w", result.Error)
+	}
+	return total, nil
+}
+
 // Helper methods to convert between domain and ORM models
 func (r *userPointRepository) toDomainPointRecord(recordEntity *entity.UserPointRecord) *point.UserPointRecord {
-	return &point.UserPointRecord{
+	record := &point.UserPointRecord{
 		ItemID:      recordEntity.ID,
 		UserID:      recordEntity.UserID,
 		Point:       recordEntity.Point,
 		Description: recordEntity.Description,
 		CreatedAt:   recordEntity.CreatedAt,
 	}
+	if recordEntity.ReviewID != nil {
+		record.ReviewID = *recordEntity.ReviewID
+	}
+	return record
 }
 
 func (r *userPointRepository) toORMPointRecord(point *point.UserPointRecord) *entity.UserPointRecord {
-	return &entity.UserPointRecord{
+	recordEntity := &entity.UserPointRecord{
 		ID:          point.ItemID,
 		UserID:      point.UserID,
 		Point:       point.Point,
@@ -80,4 +97,8 @@ func (r *userPointRepository) toORMPointRecord(point *point.UserPointRecord) *en
 		Description: point.Description,
 		CreatedAt:   point.CreatedAt,
 	}
+	if point.ReviewID != 0 {
+		recordEntity.ReviewID = &point.ReviewID
+	}
+	return recordEntity
 }
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type processedEventRepository struct {
+	db *gorm.DB
+}
+
+func NewProcessedEventRepository(db *gorm.DB) event.ProcessedEventRepository {
+	return &processedEventRepository{db: db}
+}
+
+func (r *processedEventRepository) MarkProcessed(ctx context.Context, eventID string, handler string) (bool, error) {
+	result := database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.ProcessedEvent{
+			EventID:     eventID,
+			Handler:     handler,
+			ProcessedAt: time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark event as processed: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *processedEventRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("processed_at < ?", before).Delete(&entity.ProcessedEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete processed events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) auth.RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *auth.RefreshToken) error {
+	row := r.toORMRefreshToken(token)
+	if err := database.Conn(ctx, r.db).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	token.ID = row.ID
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByToken(ctx context.Context, secret string) (*auth.RefreshToken, error) {
+	tokenHash := auth.HashSessionToken(secret)
+
+	var row entity.RefreshToken
+	err := database.Conn(ctx, r.db).Where("token = ?", tokenHash).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(row.Token), []byte(tokenHash)) != 1 {
+		return nil, nil
+	}
+
+	return r.toDomainRefreshToken(&row), nil
+}
+
+// MarkUsed only updates an unused token, so of two concurrent exchanges of a token only one succeeds
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id int, at time.Time) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&entity.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark refresh token used: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("expires_at < ?", before).Delete(&entity.RefreshToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *refreshTokenRepository) toORMRefreshToken(token *auth.RefreshToken) entity.RefreshToken {
+	return entity.RefreshToken{
+		ID:        token.ID,
+		SessionID: token.SessionID,
+		UserID:    token.UserID,
+		Token:     token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+		CreatedAt: token.CreatedAt,
+	}
+}
+
+func (r *refreshTokenRepository) toDomainRefreshToken(row *entity.RefreshToken) *auth.RefreshToken {
+	return &auth.RefreshToken{
+		ID:        row.ID,
+		SessionID: row.SessionID,
+		UserID:    row.UserID,
+		TokenHash: row.Token,
+		ExpiresAt: row.ExpiresAt,
+		UsedAt:    row.UsedAt,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -7,7 +7,9 @@ import (
 
 	"gorm.io/gorm"
 
+	"jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/domain/review"
+	"jcourse_go/internal/infrastructure/database"
 	"jcourse_go/internal/infrastructure/entity"
 )
 
@@ -21,7 +23,7 @@ func NewReviewRepository(db *gorm.DB) review.ReviewRepository {
 
 func (r *reviewRepository) Get(ctx context.Context, id int) (*review.Review, error) {
 	var reviewEntity entity.Review
-	result := r.db.WithContext(ctx).
+	result := database.Conn(ctx, r.db).
 		Preload("Course").
 		Preload("User").
 		First(&reviewEntity, id)
@@ -36,43 +38,92 @@ func (r *reviewRepository) Get(ctx context.Context, id int) (*review.Review, err
 
 func (r *reviewRepository) FindBy(ctx context.Context, filter review.ReviewFilter) ([]review.Review, error) {
 	var reviewEntitys []entity.Review
-	query := r.db.WithContext(ctx).Preload("Course").Preload("User")
+	query := r.filterQuery(database.Conn(ctx, r.db).Preload("Course").Preload("User"), filter)
+	query = r.sortQuery(query, filter)
 
+	result := query.Find(&reviewEntitys)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find reviews: %w", result.Error)
+	}
+
+	reviews := make([]review.Review, len(reviewEntitys))
+	for i, reviewEntity := range reviewEntitys {
+		reviews[i] = *r.toDomainReview(&reviewEntity)
+	}
+
+	return reviews, nil
+}
+
+func (r *reviewRepository) CountBy(ctx context.Context, filter review.ReviewFilter) (int64, error) {
+	var count int64
+	query := r.filterQuery(database.Conn(ctx, r.db).Model(&entity.Review{}), filter)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+	return count, nil
+}
+
+func (r *reviewRepository) filterQuery(query *gorm.DB, filter review.ReviewFilter) *gorm.DB {
 	if filter.ReviewID != nil {
-		query = query.Where("id = ?", *filter.ReviewID)
+		query = query.Where("reviews.id = ?", *filter.ReviewID)
 	}
 	if filter.UserID != nil {
-		query = query.Where("user_id = ?", *filter.UserID)
+		query = query.Where("reviews.user_id = ?", *filter.UserID)
 	}
 	if filter.CourseID != nil {
-		query = query.Where("course_id = ?", *filter.CourseID)
+		query = query.Where("reviews.course_id = ?", *filter.CourseID)
 	}
 	if filter.MainTeacherID != nil {
 		query = query.Joins("JOIN courses ON reviews.course_id = courses.id").
 			Where("courses.main_teacher_id = ?", *filter.MainTeacherID)
 	}
 	if filter.Semester != nil {
-		query = query.Where("semester = ?", *filter.Semester)
+		query = query.Where("reviews.semester = ?", *filter.Semester)
 	}
 	if filter.Rating != nil {
-		query = query.Where("rating = ?", *filter.Rating)
+		query = query.Where("reviews.rating = ?", *filter.Rating)
 	}
+	return query
+}
 
-	result := query.Find(&reviewEntitys)
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to find reviews: %w", result.Error)
+// sortQuery orders the reviews and selects the page: the rows after the cursor when there is one,
+// otherwise the page offset. The cursor compares the sort keys as a row, which the indexes serve.
+func (r *reviewRepository) sortQuery(query *gorm.DB, filter review.ReviewFilter) *gorm.DB {
+	after := filter.After
+	switch filter.Sort {
+	case review.ReviewSortOldest:
+		if after != nil {
+			query = query.Where("(reviews.created_at, reviews.id) > (?, ?)", after.CreatedAt, after.ID)
+		}
+		query = query.Order("reviews.created_at ASC, reviews.id ASC")
+	case review.ReviewSortRating:
+		if after != nil {
+			query = query.Where("(reviews.rating, reviews.id) < (?, ?)", after.Rating, after.ID)
+		}
+		query = query.Order("reviews.rating DESC, reviews.id DESC")
+	case review.ReviewSortMostLiked:
+		if after != nil {
+			query = query.Where("(reviews.like_count, reviews.id) < (?, ?)", after.LikeCount, after.ID)
+		}
+		query = query.Order("reviews.like_count DESC, reviews.id DESC")
+	default:
+		if after != nil {
+			query = query.Where("(reviews.created_at, reviews.id) < (?, ?)", after.CreatedAt, after.ID)
+		}
+		query = query.Order("reviews.created_at DESC, reviews.id DESC")
 	}
 
-	reviews := make([]review.Review, len(reviewEntitys))
-	for i, reviewEntity := range reviewEntitys {
-		reviews[i] = *r.toDomainReview(&reviewEntity)
+	if filter.Pagination != nil {
+		query = query.Limit(filter.Pagination.Size)
+		if after == nil {
+			query = query.Offset(filter.Pagination.Offset())
+		}
 	}
-
-	return reviews, nil
+	return query
 }
 
 func (r *reviewRepository) Save(ctx context.Context, review *review.Review, revision *review.ReviewRevision) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		reviewEntity := r.toORMReview(review)
 
 		if review.ID == 0 {
@@ -81,7 +132,8 @@ func (r *reviewRepository) Save(ctx context.Context, review *review.Review, revi
 			}
 			review.ID = reviewEntity.ID
 		} else {
-			if err := tx.Save(reviewEntity).Error; err != nil {
+			// The creation time and like count are not part of the review's content
+			if err := tx.Omit("created_at", "like_count").Save(reviewEntity).Error; err != nil {
 				return fmt.Errorf("failed to update review: %w", err)
 			}
 		}
@@ -99,7 +151,7 @@ func (r *reviewRepository) Save(ctx context.Context, review *review.Review, revi
 }
 
 func (r *reviewRepository) Delete(ctx context.Context, filter review.ReviewFilter) error {
-	query := r.db.WithContext(ctx)
+	query := database.Conn(ctx, r.db)
 	if filter.ReviewID != nil {
 		query = query.Where("id = ?", *filter.ReviewID)
 	}
@@ -119,25 +171,52 @@ func (r *reviewRepository) Delete(ctx context.Context, filter review.ReviewFilte
 }
 
 func (r *reviewRepository) SaveReviewAction(ctx context.Context, action *review.ReviewAction) error {
-	actionEntity := r.toORMReviewAction(action)
-	result := r.db.WithContext(ctx).Create(actionEntity)
-	if result.Error != nil {
-		return fmt.Errorf("failed to save review action: %w", result.Error)
-	}
-	return nil
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		actionEntity := r.toORMReviewAction(action)
+		if err := tx.Create(actionEntity).Error; err != nil {
+			return fmt.Errorf("failed to save review action: %w", err)
+		}
+		action.ID = actionEntity.ID
+
+		if action.ActionType == review.ActionTypeLike {
+			return r.addLikes(tx, action.ReviewID, 1)
+		}
+		return nil
+	})
 }
 
 func (r *reviewRepository) DeleteReviewAction(ctx context.Context, actionID int) error {
-	result := r.db.WithContext(ctx).Delete(&entity.ReviewAction{}, actionID)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete review action: %w", result.Error)
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var actionEntity entity.ReviewAction
+		if err := tx.First(&actionEntity, actionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to get review action: %w", err)
+		}
+		if err := tx.Delete(&entity.ReviewAction{}, actionID).Error; err != nil {
+			return fmt.Errorf("failed to delete review action: %w", err)
+		}
+
+		if actionEntity.Action == review.ActionTypeLike {
+			return r.addLikes(tx, actionEntity.ReviewID, -1)
+		}
+		return nil
+	})
+}
+
+func (r *reviewRepository) addLikes(tx *gorm.DB, reviewID int, delta int) error {
+	err := tx.Model(&entity.Review{}).Where("id = ?", reviewID).
+		UpdateColumn("like_count", gorm.Expr("GREATEST(like_count + ?, 0)", delta)).Error
+	if err != nil {
+		return fmt.Errorf("failed to update review like count: %w", err)
 	}
 	return nil
 }
 
 func (r *reviewRepository) GetReviewAction(ctx context.Context, actionID int) (*review.ReviewAction, error) {
 	var actionEntity entity.ReviewAction
-	result := r.db.WithContext(ctx).First(&actionEntity, actionID)
+	result := database.Conn(ctx, r.db).First(&actionEntity, actionID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -147,9 +226,23 @@ func (r *reviewRepository) GetReviewAction(ctx context.Context, actionID int) (*
 	return r.toDomainReviewAction(&actionEntity), nil
 }
 
+func (r *reviewRepository) GetUserReviewActions(ctx context.Context, userID int) ([]review.ReviewAction, error) {
+	var actionEntities []entity.ReviewAction
+	result := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("id ASC").Find(&actionEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get user review actions: %w", result.Error)
+	}
+
+	actions := make([]review.ReviewAction, len(actionEntities))
+	for i, actionEntity := range actionEntities {
+		actions[i] = *r.toDomainReviewAction(&actionEntity)
+	}
+	return actions, nil
+}
+
 func (r *reviewRepository) GetReviewRevisions(ctx context.Context, reviewID int) ([]review.ReviewRevision, error) {
 	var revisionEntitys []entity.ReviewRevision
-	result := r.db.WithContext(ctx).Where("review_id = ?", reviewID).Find(&revisionEntitys)
+	result := database.Conn(ctx, r.db).Where("review_id = ?", reviewID).Find(&revisionEntitys)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get review revisions: %w", result.Error)
 	}
@@ -163,14 +256,35 @@ func (r *reviewRepository) GetReviewRevisions(ctx context.Context, reviewID int)
 
 // Helper methods to convert between domain and ORM models
 func (r *reviewRepository) toDomainReview(reviewEntity *entity.Review) *review.Review {
-	return &review.Review{
-		ID:       reviewEntity.ID,
-		UserID:   reviewEntity.UserID,
-		CourseID: reviewEntity.CourseID,
-		Rating:   review.NewRating(reviewEntity.Rating),
-		Semester: review.NewSemester(reviewEntity.Semester),
-		Comment:  reviewEntity.Content,
+	domainReview := &review.Review{
+		ID:        reviewEntity.ID,
+		UserID:    reviewEntity.UserID,
+		CourseID:  reviewEntity.CourseID,
+		Rating:    review.NewRating(reviewEntity.Rating),
+		Semester:  review.NewSemester(reviewEntity.Semester),
+		Comment:   reviewEntity.Content,
+		LikeCount: reviewEntity.LikeCount,
+		CreatedAt: reviewEntity.CreatedAt,
+		UpdatedAt: reviewEntity.UpdatedAt,
+	}
+	// Course is only set when it was preloaded
+	if reviewEntity.Course.ID != 0 {
+		domainReview.Course = &review.Course{
+			ID:            reviewEntity.Course.ID,
+			Code:          reviewEntity.Course.Code,
+			Name:          reviewEntity.Course.Name,
+			Credit:        float32(reviewEntity.Course.Credits),
+			MainTeacherID: reviewEntity.Course.MainTeacherID,
+		}
+	}
+	// User is only set when it was preloaded, which skips deleted accounts
+	if reviewEntity.User.ID != 0 {
+		domainReview.User = &auth.User{
+			ID:       reviewEntity.User.ID,
+			Username: reviewEntity.User.Username,
+		}
 	}
+	return domainReview
 }
 
 func (r *reviewRepository) toORMReview(review *review.Review) *entity.Review {
@@ -211,6 +325,7 @@ func (r *reviewRepository) toDomainReviewAction(actionEntity *entity.ReviewActio
 		ReviewID:   actionEntity.ReviewID,
 		UserID:     actionEntity.UserID,
 		ActionType: actionEntity.Action,
+		CreatedAt:  actionEntity.CreatedAt,
 	}
 }
 
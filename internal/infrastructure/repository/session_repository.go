@@ -2,19 +2,19 @@ package repository
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 
 	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/database"
 	"jcourse_go/internal/infrastructure/entity"
 )
 
-const (
-	SessionExpiration = 24 * time.Hour
-)
-
 type sessionRepository struct {
 	db *gorm.DB
 }
@@ -23,40 +23,147 @@ func NewSessionRepository(db *gorm.DB) auth.SessionRepository {
 	return &sessionRepository{db: db}
 }
 
-func (r *sessionRepository) Store(ctx context.Context, userID int) (string, error) {
-	sessionID := fmt.Sprintf("session:%d:%d", userID, time.Now().Unix())
-	expiresAt := time.Now().Add(SessionExpiration)
+func (r *sessionRepository) Store(ctx context.Context, userID int, metadata auth.SessionMetadata) (string, error) {
+	token := auth.NewSessionToken()
+	session := r.toORMSession(auth.NewSession(userID, auth.HashSessionToken(token), metadata, time.Now()))
 
-	session := entity.UserSession{
-		UserID:    userID,
-		Token:     sessionID,
-		ExpiresAt: expiresAt,
+	if err := database.Conn(ctx, r.db).Create(&session).Error; err != nil {
+		return "", fmt.Errorf("failed to store session: %w", err)
 	}
 
-	if err := r.db.Create(&session).Error; err != nil {
-		return "", fmt.Errorf("failed to store session: %w", err)
+	return token, nil
+}
+
+// Get looks the session up by the hash of the token, so the lookup time reveals nothing about valid tokens
+func (r *sessionRepository) Get(ctx context.Context, token string) (*auth.Session, error) {
+	tokenHash := auth.HashSessionToken(token)
+
+	var session entity.UserSession
+	err := database.Conn(ctx, r.db).Where("token = ? AND expires_at > ?", tokenHash, time.Now()).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(session.Token), []byte(tokenHash)) != 1 {
+		return nil, nil
 	}
 
-	return sessionID, nil
+	return r.toDomainSession(&session), nil
 }
 
-func (r *sessionRepository) Get(ctx context.Context, sessionID string) (int, error) {
+func (r *sessionRepository) GetByID(ctx context.Context, sessionID int) (*auth.Session, error) {
 	var session entity.UserSession
-	err := r.db.Where("token = ? AND expires_at > ?", sessionID, time.Now()).First(&session).Error
+	err := database.Conn(ctx, r.db).Where("id = ? AND expires_at > ?", sessionID, time.Now()).First(&session).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return 0, fmt.Errorf("session not found or expired")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
-		return 0, fmt.Errorf("failed to get session: %w", err)
+		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
-	return session.UserID, nil
+	return r.toDomainSession(&session), nil
 }
 
-func (r *sessionRepository) Delete(ctx context.Context, sessionID string) error {
-	err := r.db.Where("token = ?", sessionID).Delete(&entity.UserSession{}).Error
+func (r *sessionRepository) Delete(ctx context.Context, token string) error {
+	err := database.Conn(ctx, r.db).Where("token = ?", auth.HashSessionToken(token)).Delete(&entity.UserSession{}).Error
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
 	return nil
 }
+
+func (r *sessionRepository) ListByUser(ctx context.Context, userID int) ([]auth.Session, error) {
+	var sessionEntities []entity.UserSession
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessionEntities).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]auth.Session, 0, len(sessionEntities))
+	for i := range sessionEntities {
+		sessions = append(sessions, *r.toDomainSession(&sessionEntities[i]))
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) Update(ctx context.Context, session *auth.Session) error {
+	err := database.Conn(ctx, r.db).Model(&entity.UserSession{}).
+		Where("id = ?", session.ID).
+		Updates(map[string]interface{}{
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteByID(ctx context.Context, userID int, sessionID int) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("id = ? AND user_id = ?", sessionID, userID).Delete(&entity.UserSession{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) DeleteOthers(ctx context.Context, userID int, keepID int) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ? AND id <> ?", userID, keepID).Delete(&entity.UserSession{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *sessionRepository) DeleteByUser(ctx context.Context, userID int) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&entity.UserSession{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Helper methods to convert between domain and ORM models
+func (r *sessionRepository) toDomainSession(sessionEntity *entity.UserSession) *auth.Session {
+	return &auth.Session{
+		ID:        sessionEntity.ID,
+		UserID:    sessionEntity.UserID,
+		TokenHash: sessionEntity.Token,
+		Metadata: auth.SessionMetadata{
+			IP:        sessionEntity.CreatedIP,
+			UserAgent: sessionEntity.UserAgent,
+		},
+		CreatedAt:  sessionEntity.CreatedAt,
+		LastSeenAt: sessionEntity.LastSeenAt,
+		ExpiresAt:  sessionEntity.ExpiresAt,
+	}
+}
+
+func (r *sessionRepository) toORMSession(session auth.Session) entity.UserSession {
+	return entity.UserSession{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		Token:      session.TokenHash,
+		CreatedIP:  session.Metadata.IP,
+		UserAgent:  truncate(session.Metadata.UserAgent, entity.MaxUserAgentLength),
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		CreatedAt:  session.CreatedAt,
+	}
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
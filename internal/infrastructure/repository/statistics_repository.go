@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"jcourse_go/internal/domain/statistics"
+	"jcourse_go/internal/infrastructure/database"
 	"jcourse_go/internal/infrastructure/entity"
 )
 
@@ -36,6 +37,7 @@ func (r *statisticsRepository) GetCurrentStatistics(ctx context.Context) (*stati
 			TotalReviews:       dailyStats.TotalReviews,
 			TotalCourses:       dailyStats.TotalCourses,
 			CoursesWithReviews: dailyStats.CoursesWithReviews,
+			DailyLikes:         dailyStats.DailyLikes,
 		}, nil
 	}
 
@@ -112,6 +114,15 @@ func (r *statisticsRepository) GetCurrentStatistics(ctx context.Context) (*stati
 		return nil, fmt.Errorf("failed to get courses with reviews: %w", err)
 	}
 
+	// Get Daily Likes
+	err = r.db.Model(&entity.DailyCounter{}).
+		Where("date = CURRENT_DATE AND name = ?", statistics.CounterLikes).
+		Select("COALESCE(SUM(value), 0)").
+		Scan(&stats.DailyLikes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily likes: %w", err)
+	}
+
 	return stats, nil
 }
 
@@ -131,6 +142,7 @@ func (r *statisticsRepository) GetDailyStatistics(ctx context.Context, date time
 		TotalReviews:       dailyStats.TotalReviews,
 		TotalCourses:       dailyStats.TotalCourses,
 		CoursesWithReviews: dailyStats.CoursesWithReviews,
+		DailyLikes:         dailyStats.DailyLikes,
 	}, nil
 }
 
@@ -154,6 +166,7 @@ func (r *statisticsRepository) GetDailyStatisticsRange(ctx context.Context, star
 			TotalReviews:       stats.TotalReviews,
 			TotalCourses:       stats.TotalCourses,
 			CoursesWithReviews: stats.CoursesWithReviews,
+			DailyLikes:         stats.DailyLikes,
 		}
 	}
 
@@ -170,6 +183,7 @@ func (r *statisticsRepository) SaveDailyStatistics(ctx context.Context, stats *s
 		TotalReviews:       stats.TotalReviews,
 		TotalCourses:       stats.TotalCourses,
 		CoursesWithReviews: stats.CoursesWithReviews,
+		DailyLikes:         stats.DailyLikes,
 	}
 
 	// Use upsert to handle duplicate dates
@@ -199,5 +213,15 @@ func (r *statisticsRepository) GetLatestDailyStatistics(ctx context.Context) (*s
 		TotalReviews:       dailyStats.TotalReviews,
 		TotalCourses:       dailyStats.TotalCourses,
 		CoursesWithReviews: dailyStats.CoursesWithReviews,
+		DailyLikes:         dailyStats.DailyLikes,
 	}, nil
 }
+
+func (r *statisticsRepository) IncrementDailyCounter(ctx context.Context, name string, delta int) error {
+	err := database.Conn(ctx, r.db).Exec(`INSERT INTO daily_counters (date, name, value) VALUES (CURRENT_DATE, ?, ?)
+		ON CONFLICT (date, name) DO UPDATE SET value = daily_counters.value + EXCLUDED.value`, name, delta).Error
+	if err != nil {
+		return fmt.Errorf("failed to increment daily counter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+func TestStatisticsRepository_IncrementDailyCounterCountsToday(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewStatisticsRepository(db)
+	ctx := context.Background()
+	name := "test-" + time.Now().Format("150405.000000000")
+
+	assert.NoError(t, repo.IncrementDailyCounter(ctx, name, 2))
+	assert.NoError(t, repo.IncrementDailyCounter(ctx, name, 1))
+
+	// The counter is read back with the same clock the statistics use
+	var value int
+	assert.NoError(t, db.Model(&entity.DailyCounter{}).Where("date = CURRENT_DATE AND name = ?", name).Select("value").Scan(&value).Error)
+	assert.Equal(t, 3, value)
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) auth.TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userID int) (*auth.TwoFactor, error) {
+	return r.get(database.Conn(ctx, r.db), userID)
+}
+
+func (r *twoFactorRepository) GetForUpdate(ctx context.Context, userID int) (*auth.TwoFactor, error) {
+	return r.get(database.Conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), userID)
+}
+
+func (r *twoFactorRepository) get(conn *gorm.DB, userID int) (*auth.TwoFactor, error) {
+	var row entity.UserTwoFactor
+	err := conn.Where("user_id = ?", userID).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+
+	var recoveryCodes []string
+	if row.RecoveryCodes != "" {
+		recoveryCodes = strings.Split(row.RecoveryCodes, ",")
+	}
+	return &auth.TwoFactor{
+		UserID:        row.UserID,
+		Secret:        row.Secret,
+		RecoveryCodes: recoveryCodes,
+		LastUsedStep:  row.LastUsedStep,
+		EnabledAt:     row.EnabledAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}, nil
+}
+
+func (r *twoFactorRepository) Save(ctx context.Context, twoFactor *auth.TwoFactor) error {
+	row := entity.UserTwoFactor{
+		UserID:        twoFactor.UserID,
+		Secret:        twoFactor.Secret,
+		RecoveryCodes: strings.Join(twoFactor.RecoveryCodes, ","),
+		LastUsedStep:  twoFactor.LastUsedStep,
+		EnabledAt:     twoFactor.EnabledAt,
+		CreatedAt:     twoFactor.CreatedAt,
+		UpdatedAt:     twoFactor.UpdatedAt,
+	}
+	if err := database.Conn(ctx, r.db).Save(&row).Error; err != nil {
+		return fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Delete(ctx context.Context, userID int) error {
+	if err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{}).Error; err != nil {
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+	return nil
+}
+
+type loginChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginChallengeRepository(db *gorm.DB) auth.LoginChallengeRepository {
+	return &loginChallengeRepository{db: db}
+}
+
+// Create also clears out the expired challenges of abandoned logins
+func (r *loginChallengeRepository) Create(ctx context.Context, challenge *auth.LoginChallenge) error {
+	conn := database.Conn(ctx, r.db)
+	if err := conn.Where("expires_at < ?", time.Now()).Delete(&entity.LoginChallenge{}).Error; err != nil {
+		return fmt.Errorf("failed to purge expired login challenges: %w", err)
+	}
+
+	row := entity.LoginChallenge{
+		UserID:    challenge.UserID,
+		Token:     challenge.TokenHash,
+		CreatedIP: challenge.Session.IP,
+		UserAgent: truncate(challenge.Session.UserAgent, entity.MaxUserAgentLength),
+		ExpiresAt: challenge.ExpiresAt,
+		CreatedAt: challenge.CreatedAt,
+	}
+	if err := conn.Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+	challenge.ID = row.ID
+	return nil
+}
+
+func (r *loginChallengeRepository) Get(ctx context.Context, token string) (*auth.LoginChallenge, error) {
+	tokenHash := auth.HashSessionToken(token)
+
+	var row entity.LoginChallenge
+	err := database.Conn(ctx, r.db).Where("token = ? AND expires_at > ?", tokenHash, time.Now()).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(row.Token), []byte(tokenHash)) != 1 {
+		return nil, nil
+	}
+
+	return &auth.LoginChallenge{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		TokenHash: row.Token,
+		Session: auth.SessionMetadata{
+			IP:        row.CreatedIP,
+			UserAgent: row.UserAgent,
+		},
+		Attempts:  row.Attempts,
+		CreatedAt: row.CreatedAt,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
+
+func (r *loginChallengeRepository) IncrementAttempts(ctx context.Context, id int) (int, error) {
+	var attempts []int
+	err := database.Conn(ctx, r.db).
+		Raw("UPDATE login_challenges SET attempts = attempts + 1 WHERE id = ? RETURNING attempts", id).
+		Scan(&attempts).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to update login challenge: %w", err)
+	}
+	if len(attempts) == 0 {
+		return 0, nil
+	}
+	return attempts[0], nil
+}
+
+func (r *loginChallengeRepository) Delete(ctx context.Context, id int) error {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&entity.LoginChallenge{}).Error; err != nil {
+		return fmt.Errorf("failed to delete login challenge: %w", err)
+	}
+	return nil
+}
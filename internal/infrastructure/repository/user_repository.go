@@ -4,14 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 
 	"jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/infrastructure/database"
 	"jcourse_go/internal/infrastructure/entity"
 )
 
+// uniqueViolation is the PostgreSQL error code of a duplicate key
+const uniqueViolation = "23505"
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -22,7 +29,7 @@ func NewUserRepository(db *gorm.DB) auth.UserRepository {
 
 func (r *userRepository) Get(ctx context.Context, email string) (*auth.User, error) {
 	var userEntity entity.User
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&userEntity)
+	result := database.Conn(ctx, r.db).Where("email = ?", email).First(&userEntity)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -34,7 +41,7 @@ func (r *userRepository) Get(ctx context.Context, email string) (*auth.User, err
 
 func (r *userRepository) GetByID(ctx context.Context, userID int) (*auth.User, error) {
 	var userEntity entity.User
-	result := r.db.WithContext(ctx).First(&userEntity, userID)
+	result := database.Conn(ctx, r.db).First(&userEntity, userID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -46,7 +53,7 @@ func (r *userRepository) GetByID(ctx context.Context, userID int) (*auth.User, e
 
 func (r *userRepository) FindBy(ctx context.Context, filter auth.UserFilter) ([]auth.User, error) {
 	var userEntitys []entity.User
-	query := r.db.WithContext(ctx)
+	query := database.Conn(ctx, r.db)
 
 	if len(filter.UserIDs) > 0 {
 		query = query.Where("id IN ?", filter.UserIDs)
@@ -65,9 +72,37 @@ func (r *userRepository) FindBy(ctx context.Context, filter auth.UserFilter) ([]
 	return users, nil
 }
 
+func (r *userRepository) Search(ctx context.Context, filter auth.UserSearchFilter) ([]auth.User, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&entity.User{})
+	if filter.Query != "" {
+		pattern := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("email ILIKE ? OR username ILIKE ?", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var userEntities []entity.User
+	result := query.Order("id ASC").
+		Offset(filter.Pagination.Offset()).
+		Limit(filter.Pagination.Size).
+		Find(&userEntities)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", result.Error)
+	}
+
+	users := make([]auth.User, len(userEntities))
+	for i, userEntity := range userEntities {
+		users[i] = *r.toDomainUser(&userEntity)
+	}
+	return users, total, nil
+}
+
 func (r *userRepository) Save(ctx context.Context, user *auth.User) (int, error) {
 	userEntity := r.toORMUser(user)
-	result := r.db.WithContext(ctx).Create(userEntity)
+	result := database.Conn(ctx, r.db).Create(userEntity)
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to save user: %w", result.Error)
 	}
@@ -76,13 +111,110 @@ func (r *userRepository) Save(ctx context.Context, user *auth.User) (int, error)
 
 func (r *userRepository) Update(ctx context.Context, user *auth.User) error {
 	userEntity := r.toORMUser(user)
-	result := r.db.WithContext(ctx).Save(userEntity)
+	result := database.Conn(ctx, r.db).Save(userEntity)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update user: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *userRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string, at time.Time) error {
+	result := database.Conn(ctx, r.db).Model(&entity.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]any{"password_hash": passwordHash, "updated_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update password: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *userRepository) ChangeEmail(ctx context.Context, userID int, email string) (bool, error) {
+	// Deleted accounts keep their row, so they are checked too, as is the unique index
+	taken := r.db.Unscoped().Model(&entity.User{}).Select("1").Where("email = ?", email)
+	result := database.Conn(ctx, r.db).Model(&entity.User{}).
+		Where("id = ?", userID).
+		Where("NOT EXISTS (?)", taken).
+		Updates(map[string]any{"email": email, "updated_at": time.Now()})
+	if result.Error != nil {
+		// A concurrent change to the same address only loses at the unique index
+		var pgErr *pgconn.PgError
+		if errors.As(result.Error, &pgErr) && pgErr.Code == uniqueViolation {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to change email: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) TouchLastSeen(ctx context.Context, userID int, at time.Time) error {
+	result := database.Conn(ctx, r.db).Model(&entity.User{}).Where("id = ?", userID).UpdateColumn("last_seen_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update last seen: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *userRepository) LiftExpiredSuspensions(ctx context.Context, now time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Model(&entity.User{}).
+		Where("suspended_until <= ?", now).
+		Updates(map[string]any{"suspended_at": nil, "suspended_until": nil, "suspension_reason": "", "updated_at": now})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to lift expired suspensions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *userRepository) FindDeletionDue(ctx context.Context, before time.Time) ([]auth.User, error) {
+	var userEntities []entity.User
+	result := database.Conn(ctx, r.db).Where("deletion_scheduled_at <= ?", before).Find(&userEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find users due for deletion: %w", result.Error)
+	}
+
+	users := make([]auth.User, len(userEntities))
+	for i, userEntity := range userEntities {
+		users[i] = *r.toDomainUser(&userEntity)
+	}
+	return users, nil
+}
+
+func (r *userRepository) Anonymize(ctx context.Context, user *auth.User) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Codes and login failures are keyed by email, so the address has to be read before it is replaced
+		var current entity.User
+		if err := tx.Unscoped().Select("email").First(&current, user.ID).Error; err != nil {
+			return fmt.Errorf("failed to get user to anonymise: %w", err)
+		}
+
+		if err := tx.Unscoped().Where("email = ?", current.Email).Delete(&entity.VerificationCode{}).Error; err != nil {
+			return fmt.Errorf("failed to purge verification codes: %w", err)
+		}
+		failureKey := auth.EmailThrottleKey(strings.ToLower(current.Email))
+		if err := tx.Where("throttle_key = ?", string(failureKey)).Delete(&entity.AuthFailure{}).Error; err != nil {
+			return fmt.Errorf("failed to purge login failures: %w", err)
+		}
+		owned := []any{&entity.UserSession{}, &entity.RefreshToken{}, &entity.APIToken{}, &entity.UserTwoFactor{}, &entity.LoginChallenge{}, &entity.ExternalIdentity{}, &entity.EmailChange{}}
+		for _, model := range owned {
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to purge personal data: %w", err)
+			}
+		}
+
+		result := tx.Model(&entity.User{}).Where("id = ?", user.ID).Updates(map[string]any{
+			"username":              user.Username,
+			"email":                 user.Email,
+			"password_hash":         user.Password,
+			"deletion_scheduled_at": nil,
+			"updated_at":            user.UpdatedAt,
+			"deleted_at":            user.DeletedAt,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to anonymise user: %w", result.Error)
+		}
+		return nil
+	})
+}
+
 // Helper methods to convert between domain and ORM models
 func (r *userRepository) toDomainUser(userEntity *entity.User) *auth.User {
 	return &auth.User{
@@ -91,16 +223,50 @@ func (r *userRepository) toDomainUser(userEntity *entity.User) *auth.User {
 		Password: userEntity.PasswordHash,
 		Email:    userEntity.Email,
 		Role:     common.Role(userEntity.Role),
+
+		LastSeenAt:          timeOrZero(userEntity.LastSeenAt),
+		SuspendedAt:         userEntity.SuspendedAt,
+		SuspendedUntil:      userEntity.SuspendedUntil,
+		SuspensionReason:    userEntity.SuspensionReason,
+		DeletionScheduledAt: userEntity.DeletionScheduledAt,
+		CreatedAt:           userEntity.CreatedAt,
+		UpdatedAt:           userEntity.UpdatedAt,
 	}
 }
 
 func (r *userRepository) toORMUser(user *auth.User) *entity.User {
 	return &entity.User{
-		ID:           user.ID,
-		Username:     user.Username,
-		Email:        user.Email,
-		PasswordHash: user.Password,
-		Role:         string(user.Role),
-		IsVerified:   true, // Default to true since domain doesn't have this field
+		ID:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		PasswordHash:        user.Password,
+		Role:                string(user.Role),
+		IsVerified:          true, // Default to true since domain doesn't have this field
+		LastSeenAt:          timeOrNil(user.LastSeenAt),
+		SuspendedAt:         user.SuspendedAt,
+		SuspendedUntil:      user.SuspendedUntil,
+		SuspensionReason:    user.SuspensionReason,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+	}
+}
+
+// escapeLike makes wildcards in user input match literally in a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
+	return &t
 }
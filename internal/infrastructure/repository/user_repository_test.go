@@ -0,0 +1,68 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+func TestUserRepository_AnonymizePurgesPersonalData(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	email := fmt.Sprintf("anonymize-%d@example.com", now.UnixNano())
+	user := &auth.User{Username: email, Email: email, Password: "hash", Role: common.RoleUser, CreatedAt: now, UpdatedAt: now}
+	userID, err := repo.Save(ctx, user)
+	assert.NoError(t, err)
+	user.ID = userID
+
+	assert.NoError(t, db.Create(&entity.EmailChange{
+		UserID: userID, OldEmail: "old-" + email, NewEmail: email,
+		RevertToken: fmt.Sprintf("revert-%d", now.UnixNano()), ExpiresAt: now.Add(time.Hour), CreatedAt: now,
+	}).Error)
+	failureKey := string(auth.EmailThrottleKey(email))
+	assert.NoError(t, db.Create(&entity.AuthFailure{Key: failureKey, Failures: 1, LastFailureAt: now}).Error)
+
+	user.Anonymize(now)
+	assert.NoError(t, repo.Anonymize(ctx, user))
+
+	var count int64
+	assert.NoError(t, db.Model(&entity.EmailChange{}).Where("user_id = ?", userID).Count(&count).Error)
+	assert.Zero(t, count)
+	assert.NoError(t, db.Model(&entity.AuthFailure{}).Where("throttle_key = ?", failureKey).Count(&count).Error)
+	assert.Zero(t, count)
+	assert.NoError(t, db.Unscoped().Model(&entity.User{}).Where("email = ?", email).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUserRepository_UpdatePasswordKeepsOtherChanges(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	email := fmt.Sprintf("rehash-%d@example.com", now.UnixNano())
+	user := &auth.User{Username: email, Email: email, Password: "legacy", Role: common.RoleUser, CreatedAt: now, UpdatedAt: now}
+	userID, err := repo.Save(ctx, user)
+	assert.NoError(t, err)
+
+	// The user is renamed after the login read it, and before its password is rehashed
+	assert.NoError(t, db.Model(&entity.User{}).Where("id = ?", userID).Update("username", "renamed").Error)
+	assert.NoError(t, repo.UpdatePassword(ctx, userID, "rehashed", now))
+
+	updated, err := repo.GetByID(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "rehashed", updated.Password)
+	assert.Equal(t, "renamed", updated.Username)
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/webhook"
+	"jcourse_go/internal/infrastructure/database"
+	"jcourse_go/internal/infrastructure/entity"
+)
+
+type webhookSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookSubscriptionRepository(db *gorm.DB) webhook.SubscriptionRepository {
+	return &webhookSubscriptionRepository{db: db}
+}
+
+func (r *webhookSubscriptionRepository) Get(ctx context.Context, id int64) (*webhook.Subscription, error) {
+	var subscriptionEntity entity.WebhookSubscription
+	result := database.Conn(ctx, r.db).First(&subscriptionEntity, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", result.Error)
+	}
+	return r.toDomainSubscription(&subscriptionEntity), nil
+}
+
+func (r *webhookSubscriptionRepository) FindAll(ctx context.Context) ([]webhook.Subscription, error) {
+	var subscriptionEntities []entity.WebhookSubscription
+	result := database.Conn(ctx, r.db).Order("id ASC").Find(&subscriptionEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find webhook subscriptions: %w", result.Error)
+	}
+	return r.toDomainSubscriptions(subscriptionEntities), nil
+}
+
+func (r *webhookSubscriptionRepository) FindEnabledByEventType(ctx context.Context, eventType event.Type) ([]webhook.Subscription, error) {
+	var subscriptionEntities []entity.WebhookSubscription
+	result := database.Conn(ctx, r.db).
+		Where("event_type = ? AND enabled = ?", string(eventType), true).
+		Find(&subscriptionEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find webhook subscriptions: %w", result.Error)
+	}
+	return r.toDomainSubscriptions(subscriptionEntities), nil
+}
+
+func (r *webhookSubscriptionRepository) Save(ctx context.Context, subscription *webhook.Subscription) error {
+	subscriptionEntity := r.toORMSubscription(subscription)
+	result := database.Conn(ctx, r.db).Save(subscriptionEntity)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", result.Error)
+	}
+	subscription.ID = subscriptionEntity.ID
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, id int64) error {
+	result := database.Conn(ctx, r.db).Delete(&entity.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+	}
+	return nil
+}
+
+// Helper methods to convert between domain and ORM models
+func (r *webhookSubscriptionRepository) toDomainSubscriptions(subscriptionEntities []entity.WebhookSubscription) []webhook.Subscription {
+	subscriptions := make([]webhook.Subscription, len(subscriptionEntities))
+	for i, subscriptionEntity := range subscriptionEntities {
+		subscriptions[i] = *r.toDomainSubscription(&subscriptionEntity)
+	}
+	return subscriptions
+}
+
+func (r *webhookSubscriptionRepository) toDomainSubscription(subscriptionEntity *entity.WebhookSubscription) *webhook.Subscription {
+	return &webhook.Subscription{
+		ID:        subscriptionEntity.ID,
+		EventType: event.Type(subscriptionEntity.EventType),
+		URL:       subscriptionEntity.URL,
+		Secret:    subscriptionEntity.Secret,
+		Enabled:   subscriptionEntity.Enabled,
+		CreatedAt: subscriptionEntity.CreatedAt,
+		UpdatedAt: subscriptionEntity.UpdatedAt,
+	}
+}
+
+func (r *webhookSubscriptionRepository) toORMSubscription(subscription *webhook.Subscription) *entity.WebhookSubscription {
+	return &entity.WebhookSubscription{
+		ID:        subscription.ID,
+		EventType: string(subscription.EventType),
+		URL:       subscription.URL,
+		Secret:    subscription.Secret,
+		Enabled:   subscription.Enabled,
+		CreatedAt: subscription.CreatedAt,
+		UpdatedAt: subscription.UpdatedAt,
+	}
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) webhook.DeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Save(ctx context.Context, deliveries ...webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	deliveryEntities := make([]entity.WebhookDelivery, len(deliveries))
+	for i := range deliveries {
+		deliveryEntities[i] = *r.toORMDelivery(&deliveries[i])
+	}
+
+	result := database.Conn(ctx, r.db).Create(&deliveryEntities)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save webhook deliveries: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]webhook.Delivery, error) {
+	var deliveryEntities []entity.WebhookDelivery
+	// A single statement, so the row locks are released as soon as the lease is written
+	result := database.Conn(ctx, r.db).Raw(`UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, string(webhook.DeliveryStatusPending), now, limit).
+		Scan(&deliveryEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to claim due webhook deliveries: %w", result.Error)
+	}
+	return r.toDomainDeliveries(deliveryEntities), nil
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery *webhook.Delivery) error {
+	result := database.Conn(ctx, r.db).
+		Model(&entity.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          string(delivery.Status),
+			"attempts":        delivery.Attempts,
+			"response_code":   delivery.ResponseCode,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      delivery.UpdatedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID int64, pagination common.Pagination) ([]webhook.Delivery, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&entity.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	var deliveryEntities []entity.WebhookDelivery
+	result := query.
+		Order("id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Size).
+		Find(&deliveryEntities)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to find webhook deliveries: %w", result.Error)
+	}
+	return r.toDomainDeliveries(deliveryEntities), total, nil
+}
+
+func (r *webhookDeliveryRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).
+		Where("status <> ? AND updated_at < ?", string(webhook.DeliveryStatusPending), before).
+		Delete(&entity.WebhookDelivery{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Helper methods to convert between domain and ORM models
+func (r *webhookDeliveryRepository) toDomainDeliveries(deliveryEntities []entity.WebhookDelivery) []webhook.Delivery {
+	deliveries := make([]webhook.Delivery, len(deliveryEntities))
+	for i, deliveryEntity := range deliveryEntities {
+		deliveries[i] = *r.toDomainDelivery(&deliveryEntity)
+	}
+	return deliveries
+}
+
+func (r *webhookDeliveryRepository) toDomainDelivery(deliveryEntity *entity.WebhookDelivery) *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:             deliveryEntity.ID,
+		SubscriptionID: deliveryEntity.SubscriptionID,
+		EventID:        deliveryEntity.EventID,
+		EventType:      event.Type(deliveryEntity.EventType),
+		Body:           []byte(deliveryEntity.Body),
+		Status:         webhook.DeliveryStatus(deliveryEntity.Status),
+		Attempts:       deliveryEntity.Attempts,
+		ResponseCode:   deliveryEntity.ResponseCode,
+		LastError:      deliveryEntity.LastError,
+		NextAttemptAt:  deliveryEntity.NextAttemptAt,
+		DeliveredAt:    deliveryEntity.DeliveredAt,
+		CreatedAt:      deliveryEntity.CreatedAt,
+		UpdatedAt:      deliveryEntity.UpdatedAt,
+	}
+}
+
+func (r *webhookDeliveryRepository) toORMDelivery(delivery *webhook.Delivery) *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Body:           string(delivery.Body),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseCode:   delivery.ResponseCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"jcourse_go/internal/domain/webhook"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+	// maxResponseBody bounds how much of a receiver's response is read before the connection is reused
+	maxResponseBody = 64 * 1024
+)
+
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender returns a sender that refuses to connect to the addresses of webhook.IsForbiddenAddress
+// and does not follow redirects, so subscriptions cannot reach the internal network
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return newHTTPSender(timeout, webhook.IsForbiddenAddress)
+}
+
+func newHTTPSender(timeout time.Duration, forbidden func(netip.Addr) bool) *HTTPSender {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	// The address is checked once resolved, right before connecting, so a host name cannot
+	// resolve to an allowed address when validated and to a forbidden one when used
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("failed to parse webhook address %s: %w", address, err)
+			}
+			if forbidden(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not allowed", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout: timeout,
+			// No proxy, which would connect on our behalf without the check of the dialer
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				TLSHandshakeTimeout: timeout,
+			},
+			// A redirect could lead anywhere, so its status code is returned as the response
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts the signed body and returns the response status code
+func (s *HTTPSender) Send(ctx context.Context, req webhook.Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "jcourse-webhook")
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(webhook.SignatureHeader, webhook.Sign(req.Secret, timestamp, req.Body))
+	httpReq.Header.Set(webhook.EventTypeHeader, req.EventType)
+	httpReq.Header.Set(webhook.DeliveryIDHeader, strconv.FormatInt(req.DeliveryID, 10))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	return resp.StatusCode, nil
+}
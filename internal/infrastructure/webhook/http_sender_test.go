@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/domain/webhook"
+)
+
+func TestHTTPSender_SendsSignedRequest(t *testing.T) {
+	body := []byte(`{"id":"e1","type":"review.created"}`)
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	code, err := newTestSender().Send(context.Background(), webhook.Request{
+		DeliveryID: 42,
+		URL:        server.URL,
+		Secret:     "s3cret",
+		EventType:  "review.created",
+		Body:       body,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, body, receivedBody)
+	assert.Equal(t, "review.created", received.Header.Get(webhook.EventTypeHeader))
+	assert.Equal(t, "42", received.Header.Get(webhook.DeliveryIDHeader))
+	timestamp := received.Header.Get(webhook.TimestampHeader)
+	signature := received.Header.Get(webhook.SignatureHeader)
+	assert.True(t, webhook.Verify("s3cret", receivedBody, timestamp, signature, time.Now()))
+	assert.False(t, webhook.Verify("other", receivedBody, timestamp, signature, time.Now()))
+	assert.False(t, webhook.Verify("s3cret", []byte(`{}`), timestamp, signature, time.Now()))
+}
+
+func TestHTTPSender_SignatureCoversTimestamp(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	_, err := newTestSender().Send(context.Background(), webhook.Request{URL: server.URL, Secret: "s3cret", Body: []byte(`{}`)})
+	assert.NoError(t, err)
+
+	sentAt, err := strconv.ParseInt(received.Header.Get(webhook.TimestampHeader), 10, 64)
+	assert.NoError(t, err)
+	signature := received.Header.Get(webhook.SignatureHeader)
+	now := time.Unix(sentAt, 0)
+
+	// A replay with another timestamp, or a stale one, is refused
+	assert.False(t, webhook.Verify("s3cret", receivedBody, strconv.FormatInt(sentAt+1, 10), signature, now))
+	assert.False(t, webhook.Verify("s3cret", receivedBody, strconv.FormatInt(sentAt, 10), signature,
+		now.Add(webhook.SignatureTolerance+time.Second)))
+	assert.True(t, webhook.Verify("s3cret", receivedBody, strconv.FormatInt(sentAt, 10), signature, now))
+}
+
+func TestHTTPSender_RefusesForbiddenAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	code, err := NewHTTPSender(0).Send(context.Background(), webhook.Request{URL: server.URL, Body: []byte(`{}`)})
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, code)
+	assert.False(t, called)
+}
+
+func TestHTTPSender_DoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	code, err := newTestSender().Send(context.Background(), webhook.Request{URL: server.URL, Body: []byte(`{}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTemporaryRedirect, code)
+	assert.False(t, redirected)
+}
+
+func TestHTTPSender_ReturnsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	code, err := newTestSender().Send(context.Background(), webhook.Request{URL: server.URL, Body: []byte(`{}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestHTTPSender_UnreachableReceiver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	code, err := newTestSender().Send(context.Background(), webhook.Request{URL: server.URL, Body: []byte(`{}`)})
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, code)
+}
+
+// newTestSender allows every address, since the test servers listen on loopback
+func newTestSender() *HTTPSender {
+	return newHTTPSender(0, func(netip.Addr) bool { return false })
+}
@@ -3,9 +3,13 @@ package handler
 import (
 	"context"
 	"testing"
+	"time"
 
+	"jcourse_go/internal/application/viewobject"
 	"jcourse_go/internal/domain/common"
 	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/review"
+	"jcourse_go/internal/domain/statistics"
 	"jcourse_go/pkg/apperror"
 
 	"github.com/stretchr/testify/assert"
@@ -32,6 +36,41 @@ func (m *MockPointCommandService) AwardPointsForReview(commonCtx *common.CommonC
 	return args.Error(0)
 }
 
+func (m *MockPointCommandService) RevokePointsForReview(commonCtx *common.CommonContext, userID int, reviewID int) error {
+	args := m.Called(commonCtx, userID, reviewID)
+	return args.Error(0)
+}
+
+// MockDailyStatisticsService is a mock implementation of the daily statistics service
+type MockDailyStatisticsService struct {
+	mock.Mock
+}
+
+func (m *MockDailyStatisticsService) GetDailyStatistics(commonCtx *common.CommonContext, date time.Time) (*viewobject.DailyStatisticsVO, error) {
+	args := m.Called(commonCtx, date)
+	return args.Get(0).(*viewobject.DailyStatisticsVO), args.Error(1)
+}
+
+func (m *MockDailyStatisticsService) GetDailyStatisticsRange(commonCtx *common.CommonContext, startDate, endDate time.Time) ([]viewobject.DailyStatisticsVO, error) {
+	args := m.Called(commonCtx, startDate, endDate)
+	return args.Get(0).([]viewobject.DailyStatisticsVO), args.Error(1)
+}
+
+func (m *MockDailyStatisticsService) GetLatestDailyStatistics(commonCtx *common.CommonContext) (*viewobject.DailyStatisticsVO, error) {
+	args := m.Called(commonCtx)
+	return args.Get(0).(*viewobject.DailyStatisticsVO), args.Error(1)
+}
+
+func (m *MockDailyStatisticsService) CalculateAndSaveDailyStatistics(ctx context.Context, date time.Time) error {
+	args := m.Called(ctx, date)
+	return args.Error(0)
+}
+
+func (m *MockDailyStatisticsService) IncrementDailyCounter(ctx context.Context, name string, delta int) error {
+	args := m.Called(ctx, name, delta)
+	return args.Error(0)
+}
+
 func TestPointEventHandler_HandleReviewCreated(t *testing.T) {
 	// Setup
 	mockService := new(MockPointCommandService)
@@ -66,7 +105,7 @@ func TestPointEventHandler_HandleReviewCreated(t *testing.T) {
 type MockInvalidPayload struct{}
 
 func (m *MockInvalidPayload) Type() event.Type {
-	return event.Type(999) // Invalid type
+	return event.Type("invalid") // Invalid type
 }
 
 func TestPointEventHandler_HandleInvalidPayload(t *testing.T) {
@@ -141,3 +180,47 @@ func TestPointEventHandler_HandleSaveError(t *testing.T) {
 	assert.Contains(t, err.Error(), "database error")
 	mockService.AssertExpectations(t)
 }
+
+func TestPointEventHandler_HandleReviewDeleted(t *testing.T) {
+	mockService := new(MockPointCommandService)
+	handler := NewPointEventHandler(mockService)
+
+	payload := &event.ReviewPayload{
+		ReviewID: 123,
+		UserID:   456,
+		CourseID: 789,
+		Action:   "deleted",
+	}
+	mockService.On("RevokePointsForReview", mock.AnythingOfType("*common.CommonContext"), 456, 123).Return(nil)
+
+	err := handler.Handle(context.Background(), event.NewBaseEvent(event.TypeReviewDeleted, payload))
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "AwardPointsForReview", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatisticsEventHandler_CountsLikes(t *testing.T) {
+	mockService := new(MockDailyStatisticsService)
+	handler := NewStatisticsEventHandler(mockService)
+
+	like := &event.ReviewActionPayload{ActionID: 1, ReviewID: 123, UserID: 456, ActionType: review.ActionTypeLike}
+	mockService.On("IncrementDailyCounter", mock.Anything, statistics.CounterLikes, 1).Return(nil).Once()
+	mockService.On("IncrementDailyCounter", mock.Anything, statistics.CounterLikes, -1).Return(nil).Once()
+
+	assert.NoError(t, handler.Handle(context.Background(), event.NewBaseEvent(event.TypeReviewActionPosted, like)))
+	assert.NoError(t, handler.Handle(context.Background(), event.NewBaseEvent(event.TypeReviewActionDeleted, like)))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStatisticsEventHandler_IgnoresOtherActions(t *testing.T) {
+	mockService := new(MockDailyStatisticsService)
+	handler := NewStatisticsEventHandler(mockService)
+
+	report := &event.ReviewActionPayload{ActionID: 1, ReviewID: 123, UserID: 456, ActionType: "report"}
+	err := handler.Handle(context.Background(), event.NewBaseEvent(event.TypeReviewActionPosted, report))
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "IncrementDailyCounter", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+
+	"jcourse_go/internal/application/event/service"
+	"jcourse_go/internal/domain/event"
+)
+
+// EventStoreEventHandler appends every published event to the event store
+type EventStoreEventHandler struct {
+	eventStoreService service.EventStoreService
+}
+
+func NewEventStoreEventHandler(eventStoreService service.EventStoreService) *EventStoreEventHandler {
+	return &EventStoreEventHandler{
+		eventStoreService: eventStoreService,
+	}
+}
+
+func (h *EventStoreEventHandler) Handle(ctx context.Context, e event.Event) error {
+	return h.eventStoreService.Append(ctx, e)
+}
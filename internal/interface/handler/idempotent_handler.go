@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+)
+
+// IdempotentHandler skips events the wrapped handler has already handled.
+// The ledger entry is written in the same transaction as the handler's own changes,
+// so a failed attempt leaves no trace and is retried as usual.
+type IdempotentHandler struct {
+	next            event.Handler
+	name            string
+	processedEvents event.ProcessedEventRepository
+	transactor      common.Transactor
+}
+
+func NewIdempotentHandler(next event.Handler, processedEvents event.ProcessedEventRepository, transactor common.Transactor) *IdempotentHandler {
+	return &IdempotentHandler{
+		next:            next,
+		name:            event.HandlerName(next),
+		processedEvents: processedEvents,
+		transactor:      transactor,
+	}
+}
+
+// Name keeps the wrapped handler's name, so dead letters can still be replayed to it
+func (h *IdempotentHandler) Name() string {
+	return h.name
+}
+
+func (h *IdempotentHandler) Handle(ctx context.Context, e event.Event) error {
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		first, err := h.processedEvents.MarkProcessed(ctx, e.ID(), h.name)
+		if err != nil {
+			return err
+		}
+		if !first {
+			log.Printf("Skipping event %s (type %s) already handled by %s", e.ID(), e.Type(), h.name)
+			return nil
+		}
+		return h.next.Handle(ctx, e)
+	})
+}
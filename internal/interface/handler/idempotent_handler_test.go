@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"jcourse_go/internal/domain/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTransactor runs the function directly without a real transaction
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// MockProcessedEventRepository is a mock implementation of the processed-event ledger
+type MockProcessedEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockProcessedEventRepository) MarkProcessed(ctx context.Context, eventID string, handler string) (bool, error) {
+	args := m.Called(ctx, eventID, handler)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProcessedEventRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newReviewCreatedEvent() event.Event {
+	return event.NewBaseEvent(event.TypeReviewCreated, &event.ReviewPayload{
+		ReviewID: 123,
+		UserID:   456,
+		CourseID: 789,
+		Rating:   5,
+		Action:   "created",
+	})
+}
+
+func TestIdempotentHandler_SkipsDuplicates(t *testing.T) {
+	mockService := new(MockPointCommandService)
+	ledger := new(MockProcessedEventRepository)
+	handler := NewIdempotentHandler(NewPointEventHandler(mockService), ledger, &MockTransactor{})
+
+	e := newReviewCreatedEvent()
+	ledger.On("MarkProcessed", mock.Anything, e.ID(), "*handler.PointEventHandler").Return(true, nil).Once()
+	ledger.On("MarkProcessed", mock.Anything, e.ID(), "*handler.PointEventHandler").Return(false, nil).Once()
+	mockService.On("AwardPointsForReview", mock.Anything, 456, 123).Return(nil).Once()
+
+	assert.NoError(t, handler.Handle(context.Background(), e))
+	assert.NoError(t, handler.Handle(context.Background(), e))
+
+	mockService.AssertNumberOfCalls(t, "AwardPointsForReview", 1)
+	ledger.AssertExpectations(t)
+}
+
+func TestIdempotentHandler_LedgerError(t *testing.T) {
+	mockService := new(MockPointCommandService)
+	ledger := new(MockProcessedEventRepository)
+	handler := NewIdempotentHandler(NewPointEventHandler(mockService), ledger, &MockTransactor{})
+
+	e := newReviewCreatedEvent()
+	ledger.On("MarkProcessed", mock.Anything, e.ID(), mock.Anything).Return(false, errors.New("connection lost"))
+
+	err := handler.Handle(context.Background(), e)
+
+	assert.Error(t, err)
+	mockService.AssertNotCalled(t, "AwardPointsForReview")
+}
+
+func TestIdempotentHandler_KeepsHandlerName(t *testing.T) {
+	handler := NewIdempotentHandler(NewReviewEventHandler(), new(MockProcessedEventRepository), &MockTransactor{})
+	assert.Equal(t, "*handler.ReviewEventHandler", event.HandlerName(handler))
+
+	streamHandler := NewReviewStreamEventHandler(nil)
+	wrapped := NewIdempotentHandler(streamHandler, new(MockProcessedEventRepository), &MockTransactor{})
+	assert.Equal(t, streamHandler.Name(), event.HandlerName(wrapped))
+	assert.NotEqual(t, streamHandler.Name(), NewReviewStreamEventHandler(nil).Name())
+}
+
+// recordingBus keeps the handlers registered for each event type
+type recordingBus struct {
+	event.EventBusPublisher
+	handlers map[event.Type][]event.Handler
+}
+
+func (b *recordingBus) Register(eventType event.Type, handler event.Handler) error {
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	return nil
+}
+
+func TestRegisterEventHandlers_StreamSkipsLedger(t *testing.T) {
+	bus := &recordingBus{handlers: make(map[event.Type][]event.Handler)}
+
+	assert.NoError(t, RegisterEventHandlers(bus, new(MockProcessedEventRepository), &MockTransactor{}, nil, nil, nil, nil, nil))
+
+	var stream, idempotent int
+	for _, handler := range bus.handlers[event.TypeReviewCreated] {
+		switch handler.(type) {
+		case *ReviewStreamEventHandler:
+			stream++
+		case *IdempotentHandler:
+			idempotent++
+		}
+	}
+	assert.Equal(t, 1, stream)
+	assert.Equal(t, len(bus.handlers[event.TypeReviewCreated])-1, idempotent)
+}
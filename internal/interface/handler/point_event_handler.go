@@ -26,12 +26,18 @@ func (h *PointEventHandler) Handle(ctx context.Context, e event.Event) error {
 		return fmt.Errorf("invalid payload type for point event")
 	}
 
-	if e.Type() == event.TypeReviewCreated {
+	switch e.Type() {
+	case event.TypeReviewCreated:
 		log.Printf("Awarding points for review creation: UserID=%d, ReviewID=%d",
 			payload.UserID, payload.ReviewID)
 
 		// Award points using the service layer
 		return h.awardPointsForReview(ctx, payload.UserID, payload.ReviewID)
+	case event.TypeReviewDeleted:
+		log.Printf("Revoking points for review deletion: UserID=%d, ReviewID=%d",
+			payload.UserID, payload.ReviewID)
+
+		return h.revokePointsForReview(ctx, payload.UserID, payload.ReviewID)
 	}
 
 	return nil
@@ -54,3 +60,19 @@ func (h *PointEventHandler) awardPointsForReview(ctx context.Context, userID int
 	log.Printf("Successfully awarded points to user %d for review %d", userID, reviewID)
 	return nil
 }
+
+// revokePointsForReview takes back the points awarded for a review that was deleted
+func (h *PointEventHandler) revokePointsForReview(ctx context.Context, userID int, reviewID int) error {
+	systemCtx := &common.CommonContext{
+		Ctx:  ctx,
+		User: common.SystemUser,
+	}
+
+	if err := h.pointService.RevokePointsForReview(systemCtx, userID, reviewID); err != nil {
+		log.Printf("Failed to revoke points for review %d from user %d: %v", reviewID, userID, err)
+		return err
+	}
+
+	log.Printf("Successfully revoked points from user %d for review %d", userID, reviewID)
+	return nil
+}
@@ -1,27 +1,79 @@
 package handler
 
 import (
+	eventservice "jcourse_go/internal/application/event/service"
 	"jcourse_go/internal/application/point/command"
+	"jcourse_go/internal/application/review/stream"
+	statisticsservice "jcourse_go/internal/application/statistics/service"
+	webhookservice "jcourse_go/internal/application/webhook/service"
+	"jcourse_go/internal/domain/common"
 	"jcourse_go/internal/domain/event"
 )
 
-// RegisterEventHandlers registers all event handlers with the event bus
-func RegisterEventHandlers(eventBus event.EventBusPublisher, pointService command.PointCommandService) error {
-	reviewHandler := NewReviewEventHandler()
-	pointHandler := NewPointEventHandler(pointService)
-	statsHandler := NewStatisticsEventHandler()
-
-	if err := eventBus.Register(event.TypeReviewCreated, reviewHandler); err != nil {
-		return err
-	}
-	if err := eventBus.Register(event.TypeReviewModified, reviewHandler); err != nil {
-		return err
+// RegisterEventHandlers registers all event handlers with the event bus.
+// Every handler with lasting effects is made idempotent, so a redelivered event is handled only once.
+func RegisterEventHandlers(
+	eventBus event.EventBusPublisher,
+	processedEvents event.ProcessedEventRepository,
+	transactor common.Transactor,
+	eventStoreService eventservice.EventStoreService,
+	pointService command.PointCommandService,
+	statisticsService statisticsservice.DailyStatisticsService,
+	webhookService webhookservice.WebhookDeliveryService,
+	reviewStreamService stream.ReviewStreamService,
+) error {
+	subscriptions := []struct {
+		handler    event.Handler
+		eventTypes []event.Type
+		// inMemory handlers only fan out to the clients of this process, so they have nothing to keep
+		// from being done twice and stay out of the ledger
+		inMemory bool
+	}{
+		{
+			// Admins can audit every event of the catalogue
+			handler:    NewEventStoreEventHandler(eventStoreService),
+			eventTypes: event.RegisteredTypes(),
+		},
+		{
+			handler:    NewReviewEventHandler(),
+			eventTypes: []event.Type{event.TypeReviewCreated, event.TypeReviewModified, event.TypeReviewDeleted},
+		},
+		{
+			handler:    NewPointEventHandler(pointService),
+			eventTypes: []event.Type{event.TypeReviewCreated, event.TypeReviewDeleted},
+		},
+		{
+			handler: NewStatisticsEventHandler(statisticsService),
+			eventTypes: []event.Type{
+				event.TypeReviewCreated,
+				event.TypeReviewModified,
+				event.TypeReviewDeleted,
+				event.TypeReviewActionPosted,
+				event.TypeReviewActionDeleted,
+			},
+		},
+		{
+			// Webhooks can subscribe to any event of the catalogue
+			handler:    NewWebhookEventHandler(webhookService),
+			eventTypes: event.RegisteredTypes(),
+		},
+		{
+			handler:    NewReviewStreamEventHandler(reviewStreamService),
+			eventTypes: []event.Type{event.TypeReviewCreated, event.TypeReviewModified, event.TypeReviewDeleted},
+			inMemory:   true,
+		},
 	}
-	if err := eventBus.Register(event.TypeReviewCreated, pointHandler); err != nil {
-		return err
-	}
-	if err := eventBus.Register(event.TypeReviewModified, statsHandler); err != nil {
-		return err
+
+	for _, subscription := range subscriptions {
+		handler := subscription.handler
+		if !subscription.inMemory {
+			handler = NewIdempotentHandler(handler, processedEvents, transactor)
+		}
+		for _, eventType := range subscription.eventTypes {
+			if err := eventBus.Register(eventType, handler); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
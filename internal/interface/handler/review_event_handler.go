@@ -27,8 +27,11 @@ func (h *ReviewEventHandler) Handle(ctx context.Context, e event.Event) error {
 	case event.TypeReviewModified:
 		log.Printf("Review modified event: ReviewID=%d, UserID=%d, CourseID=%d, Rating=%d",
 			payload.ReviewID, payload.UserID, payload.CourseID, payload.Rating)
+	case event.TypeReviewDeleted:
+		log.Printf("Review deleted event: ReviewID=%d, UserID=%d, CourseID=%d",
+			payload.ReviewID, payload.UserID, payload.CourseID)
 	default:
-		return fmt.Errorf("unsupported review event type: %d", e.Type())
+		return fmt.Errorf("unsupported review event type: %s", e.Type())
 	}
 
 	return nil
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"jcourse_go/internal/application/review/stream"
+	"jcourse_go/internal/domain/event"
+)
+
+// ReviewStreamEventHandler pushes newly created reviews to the SSE subscribers
+// and keeps edited and deleted reviews out of the history they resume from
+type ReviewStreamEventHandler struct {
+	streamService stream.ReviewStreamService
+	name          string
+}
+
+func NewReviewStreamEventHandler(streamService stream.ReviewStreamService) *ReviewStreamEventHandler {
+	return &ReviewStreamEventHandler{
+		streamService: streamService,
+		name:          "ReviewStreamEventHandler@" + uuid.New().String(),
+	}
+}
+
+// Name is unique to this process: every replica serves its own SSE subscribers,
+// so one replica handling the event must not stop the others from broadcasting it
+func (h *ReviewStreamEventHandler) Name() string {
+	return h.name
+}
+
+func (h *ReviewStreamEventHandler) Handle(ctx context.Context, e event.Event) error {
+	payload, ok := e.Payload().(*event.ReviewPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload type for review stream event")
+	}
+
+	switch e.Type() {
+	case event.TypeReviewCreated:
+		return h.streamService.Broadcast(ctx, payload.ReviewID)
+	case event.TypeReviewModified:
+		return h.streamService.Refresh(ctx, payload.ReviewID)
+	case event.TypeReviewDeleted:
+		h.streamService.Forget(payload.ReviewID)
+	}
+	return nil
+}
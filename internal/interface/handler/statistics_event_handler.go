@@ -5,27 +5,56 @@ import (
 	"fmt"
 	"log"
 
+	"jcourse_go/internal/application/statistics/service"
 	"jcourse_go/internal/domain/event"
+	"jcourse_go/internal/domain/review"
+	"jcourse_go/internal/domain/statistics"
 )
 
-type StatisticsEventHandler struct{}
+type StatisticsEventHandler struct {
+	statisticsService service.DailyStatisticsService
+}
 
-func NewStatisticsEventHandler() *StatisticsEventHandler {
-	return &StatisticsEventHandler{}
+func NewStatisticsEventHandler(statisticsService service.DailyStatisticsService) *StatisticsEventHandler {
+	return &StatisticsEventHandler{
+		statisticsService: statisticsService,
+	}
 }
 
 func (h *StatisticsEventHandler) Handle(ctx context.Context, e event.Event) error {
-	payload, ok := e.Payload().(*event.ReviewPayload)
-	if !ok {
+	switch payload := e.Payload().(type) {
+	case *event.ReviewPayload:
+		return h.handleReview(e.Type(), payload)
+	case *event.ReviewActionPayload:
+		return h.handleReviewAction(ctx, e.Type(), payload)
+	default:
 		return fmt.Errorf("invalid payload type for statistics event")
 	}
+}
 
-	switch e.Type() {
+func (h *StatisticsEventHandler) handleReview(eventType event.Type, payload *event.ReviewPayload) error {
+	switch eventType {
 	case event.TypeReviewCreated:
 		log.Printf("Updating statistics for new review: CourseID=%d", payload.CourseID)
 	case event.TypeReviewModified:
 		log.Printf("Updating statistics for modified review: CourseID=%d", payload.CourseID)
+	case event.TypeReviewDeleted:
+		log.Printf("Updating statistics for deleted review: CourseID=%d", payload.CourseID)
+	}
+	return nil
+}
+
+// handleReviewAction counts the likes of the day, net of likes withdrawn
+func (h *StatisticsEventHandler) handleReviewAction(ctx context.Context, eventType event.Type, payload *event.ReviewActionPayload) error {
+	if payload.ActionType != review.ActionTypeLike {
+		return nil
 	}
 
+	switch eventType {
+	case event.TypeReviewActionPosted:
+		return h.statisticsService.IncrementDailyCounter(ctx, statistics.CounterLikes, 1)
+	case event.TypeReviewActionDeleted:
+		return h.statisticsService.IncrementDailyCounter(ctx, statistics.CounterLikes, -1)
+	}
 	return nil
 }
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+
+	"jcourse_go/internal/application/webhook/service"
+	"jcourse_go/internal/domain/event"
+)
+
+// WebhookEventHandler queues the event for every webhook subscribed to its type
+type WebhookEventHandler struct {
+	deliveryService service.WebhookDeliveryService
+}
+
+func NewWebhookEventHandler(deliveryService service.WebhookDeliveryService) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		deliveryService: deliveryService,
+	}
+}
+
+func (h *WebhookEventHandler) Handle(ctx context.Context, e event.Event) error {
+	return h.deliveryService.Enqueue(ctx, e)
+}
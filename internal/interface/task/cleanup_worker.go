@@ -8,6 +8,13 @@ import (
 	"jcourse_go/internal/app"
 )
 
+const (
+	OutboxRetention          = 7 * 24 * time.Hour
+	WebhookDeliveryRetention = 30 * 24 * time.Hour
+	// ProcessedEventRetention outlasts the outbox and the event bus, after which an event is not redelivered
+	ProcessedEventRetention = OutboxRetention + 24*time.Hour
+)
+
 // CleanupWorker handles cleanup tasks
 type CleanupWorker struct {
 	serviceContainer *app.ServiceContainer
@@ -35,6 +42,74 @@ func (w *CleanupWorker) Start(ctx context.Context) {
 			// - Delete expired verification codes
 			// - Clean up old logs
 			// - Archive old data
+			w.purgeOutbox(ctx)
+			w.purgeWebhookDeliveries(ctx)
+			w.anonymizeDeletedAccounts(ctx)
+			w.liftExpiredSuspensions(ctx)
+			w.purgeRefreshTokens(ctx)
+			w.purgeProcessedEvents(ctx)
 		}
 	}
 }
+
+func (w *CleanupWorker) purgeOutbox(ctx context.Context) {
+	if w.serviceContainer.OutboxRelayService == nil {
+		return
+	}
+
+	deleted, err := w.serviceContainer.OutboxRelayService.PurgeSent(ctx, time.Now().Add(-OutboxRetention))
+	if err != nil {
+		log.Printf("Failed to purge outbox messages: %v", err)
+		return
+	}
+	log.Printf("Purged %d delivered outbox messages", deleted)
+}
+
+func (w *CleanupWorker) purgeWebhookDeliveries(ctx context.Context) {
+	deleted, err := w.serviceContainer.WebhookDeliveryService.PurgeFinished(ctx, time.Now().Add(-WebhookDeliveryRetention))
+	if err != nil {
+		log.Printf("Failed to purge webhook deliveries: %v", err)
+		return
+	}
+	log.Printf("Purged %d finished webhook deliveries", deleted)
+}
+
+func (w *CleanupWorker) anonymizeDeletedAccounts(ctx context.Context) {
+	anonymized, err := w.serviceContainer.AccountCommandService.AnonymizeDue(ctx)
+	if err != nil {
+		log.Printf("Failed to anonymise deleted accounts: %v", err)
+		return
+	}
+	log.Printf("Anonymised %d deleted accounts", anonymized)
+}
+
+func (w *CleanupWorker) liftExpiredSuspensions(ctx context.Context) {
+	lifted, err := w.serviceContainer.AdminUserCommandService.LiftExpiredSuspensions(ctx)
+	if err != nil {
+		log.Printf("Failed to lift expired suspensions: %v", err)
+		return
+	}
+	log.Printf("Lifted %d expired suspensions", lifted)
+}
+
+func (w *CleanupWorker) purgeRefreshTokens(ctx context.Context) {
+	if w.serviceContainer.TokenCommandService == nil {
+		return
+	}
+
+	deleted, err := w.serviceContainer.TokenCommandService.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		log.Printf("Failed to purge refresh tokens: %v", err)
+		return
+	}
+	log.Printf("Purged %d expired refresh tokens", deleted)
+}
+
+func (w *CleanupWorker) purgeProcessedEvents(ctx context.Context) {
+	deleted, err := w.serviceContainer.ProcessedEventRepository.DeleteProcessedBefore(ctx, time.Now().Add(-ProcessedEventRetention))
+	if err != nil {
+		log.Printf("Failed to purge processed events: %v", err)
+		return
+	}
+	log.Printf("Purged %d processed event entries", deleted)
+}
@@ -0,0 +1,63 @@
+package task
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"jcourse_go/internal/app"
+	eventservice "jcourse_go/internal/application/event/service"
+)
+
+const (
+	OutboxRelayInterval = time.Second
+)
+
+// OutboxRelayWorker delivers events from the transactional outbox to the eventbus
+type OutboxRelayWorker struct {
+	serviceContainer *app.ServiceContainer
+	relayService     eventservice.OutboxRelayService
+}
+
+func NewOutboxRelayWorker(serviceContainer *app.ServiceContainer) *OutboxRelayWorker {
+	return &OutboxRelayWorker{
+		serviceContainer: serviceContainer,
+		relayService:     serviceContainer.OutboxRelayService,
+	}
+}
+
+func (w *OutboxRelayWorker) Start(ctx context.Context) {
+	if w.relayService == nil {
+		log.Println("Outbox relay worker disabled: no event publisher configured")
+		return
+	}
+
+	log.Println("Outbox relay worker started")
+
+	ticker := time.NewTicker(OutboxRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay worker stopped")
+			return
+		case <-ticker.C:
+			w.relay(ctx)
+		}
+	}
+}
+
+func (w *OutboxRelayWorker) relay(ctx context.Context) {
+	// Keep draining while batches come back full so a backlog clears quickly
+	for {
+		sent, err := w.relayService.RelayPending(ctx)
+		if err != nil {
+			log.Printf("Failed to relay outbox messages: %v", err)
+			return
+		}
+		if sent < eventservice.DefaultRelayBatchSize {
+			return
+		}
+	}
+}
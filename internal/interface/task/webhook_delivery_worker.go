@@ -0,0 +1,58 @@
+package task
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"jcourse_go/internal/app"
+	webhookservice "jcourse_go/internal/application/webhook/service"
+)
+
+const (
+	WebhookDeliveryInterval = 5 * time.Second
+)
+
+// WebhookDeliveryWorker posts queued webhook deliveries to their subscribers
+type WebhookDeliveryWorker struct {
+	serviceContainer *app.ServiceContainer
+	deliveryService  webhookservice.WebhookDeliveryService
+}
+
+func NewWebhookDeliveryWorker(serviceContainer *app.ServiceContainer) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		serviceContainer: serviceContainer,
+		deliveryService:  serviceContainer.WebhookDeliveryService,
+	}
+}
+
+func (w *WebhookDeliveryWorker) Start(ctx context.Context) {
+	log.Println("Webhook delivery worker started")
+
+	ticker := time.NewTicker(WebhookDeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+			w.deliver(ctx)
+		}
+	}
+}
+
+func (w *WebhookDeliveryWorker) deliver(ctx context.Context) {
+	// Keep going while batches come back full so a backlog clears quickly
+	for {
+		attempted, err := w.deliveryService.DeliverDue(ctx)
+		if err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+			return
+		}
+		if attempted < webhookservice.DefaultDeliveryBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	authquery "jcourse_go/internal/application/auth/query"
+)
+
+type AccountController struct {
+	accountCommandService     authcommand.AccountCommandService
+	accountExportQueryService authquery.AccountExportQueryService
+}
+
+func NewAccountController(
+	accountCommandService authcommand.AccountCommandService,
+	accountExportQueryService authquery.AccountExportQueryService,
+) *AccountController {
+	return &AccountController{
+		accountCommandService:     accountCommandService,
+		accountExportQueryService: accountExportQueryService,
+	}
+}
+
+func (c *AccountController) Export(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	export, err := c.accountExportQueryService.Export(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	filename := fmt.Sprintf("jcourse-export-%d-%s.json", commonCtx.User.UserID, time.Now().Format("20060102"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	HandleSuccess(ctx, export)
+}
+
+func (c *AccountController) RequestDeletion(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	deletion, err := c.accountCommandService.RequestDeletion(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, deletion)
+}
+
+func (c *AccountController) CancelDeletion(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.accountCommandService.CancelDeletion(commonCtx); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
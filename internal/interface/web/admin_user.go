@@ -0,0 +1,163 @@
+package web
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	authquery "jcourse_go/internal/application/auth/query"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+)
+
+type AdminUserController struct {
+	adminUserCommandService authcommand.AdminUserCommandService
+	adminUserQueryService   authquery.AdminUserQueryService
+}
+
+func NewAdminUserController(
+	adminUserCommandService authcommand.AdminUserCommandService,
+	adminUserQueryService authquery.AdminUserQueryService,
+) *AdminUserController {
+	return &AdminUserController{
+		adminUserCommandService: adminUserCommandService,
+		adminUserQueryService:   adminUserQueryService,
+	}
+}
+
+// SearchUsers matches q against email and username
+func (c *AdminUserController) SearchUsers(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	size, _ := strconv.Atoi(ctx.Query("size"))
+
+	commonCtx := GetCommonContext(ctx)
+
+	users, err := c.adminUserQueryService.SearchUsers(commonCtx, domainauth.UserSearchFilter{
+		Query:      ctx.Query("q"),
+		Pagination: common.NewPagination(page, size),
+	})
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, users)
+}
+
+func (c *AdminUserController) GetUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid user id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	user, err := c.adminUserQueryService.GetUser(commonCtx, id)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, user)
+}
+
+func (c *AdminUserController) GetUserReviews(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid user id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	reviews, err := c.adminUserQueryService.GetUserReviews(commonCtx, id, reviewListOptions(ctx))
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, reviews)
+}
+
+func (c *AdminUserController) GetUserPoints(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid user id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	points, err := c.adminUserQueryService.GetUserPoints(commonCtx, id)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, points)
+}
+
+func (c *AdminUserController) SuspendUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid user id")
+		return
+	}
+
+	var cmd domainauth.SuspendUserCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.adminUserCommandService.SuspendUser(commonCtx, id, cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *AdminUserController) UnsuspendUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid user id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.adminUserCommandService.UnsuspendUser(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *AdminUserController) ChangeRole(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid user id")
+		return
+	}
+
+	var cmd domainauth.ChangeRoleCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.adminUserCommandService.ChangeRole(commonCtx, id, cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
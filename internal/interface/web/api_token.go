@@ -0,0 +1,74 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	authquery "jcourse_go/internal/application/auth/query"
+	domainauth "jcourse_go/internal/domain/auth"
+)
+
+type APITokenController struct {
+	apiTokenCommandService authcommand.APITokenCommandService
+	apiTokenQueryService   authquery.APITokenQueryService
+}
+
+func NewAPITokenController(
+	apiTokenCommandService authcommand.APITokenCommandService,
+	apiTokenQueryService authquery.APITokenQueryService,
+) *APITokenController {
+	return &APITokenController{
+		apiTokenCommandService: apiTokenCommandService,
+		apiTokenQueryService:   apiTokenQueryService,
+	}
+}
+
+func (c *APITokenController) ListTokens(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	tokens, err := c.apiTokenQueryService.ListTokens(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, tokens)
+}
+
+func (c *APITokenController) CreateToken(ctx *gin.Context) {
+	var cmd domainauth.CreateAPITokenCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	token, err := c.apiTokenCommandService.CreateToken(commonCtx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccessWithStatus(ctx, http.StatusCreated, token)
+}
+
+func (c *APITokenController) RevokeToken(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid token id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.apiTokenCommandService.RevokeToken(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -15,45 +16,120 @@ type AuthController struct {
 	authCommandService authcommand.AuthCommandService
 	authQueryService   authquery.AuthQueryService
 	codeService        auth.VerificationCodeService
+	loginResponder     *LoginResponder
 }
 
 func NewAuthController(
 	authCommandService authcommand.AuthCommandService,
 	authQueryService authquery.AuthQueryService,
 	codeService auth.VerificationCodeService,
+	loginResponder *LoginResponder,
 ) *AuthController {
 	return &AuthController{
 		authCommandService: authCommandService,
 		authQueryService:   authQueryService,
 		codeService:        codeService,
+		loginResponder:     loginResponder,
 	}
 }
 
 type AuthResponse struct {
-	SessionID string `json:"session_id"`
+	SessionID string `json:"session_id,omitempty"`
+	// CSRFToken must be sent in the X-CSRF-Token header when the session is used through its cookie
+	CSRFToken string `json:"csrf_token,omitempty"`
+	// AccessToken and RefreshToken take the place of the session ID in token mode
+	AccessToken          string     `json:"access_token,omitempty"`
+	AccessTokenExpiresAt *time.Time `json:"access_token_expires_at,omitempty"`
+	RefreshToken         string     `json:"refresh_token,omitempty"`
+	// TwoFactorRequired asks the client to complete the login at /auth/login/2fa with the challenge token
+	TwoFactorRequired  bool       `json:"two_factor_required,omitempty"`
+	ChallengeToken     string     `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt *time.Time `json:"challenge_expires_at,omitempty"`
 }
 
 func NewAuthResponse(sessionID string) AuthResponse {
 	return AuthResponse{SessionID: sessionID}
 }
 
+func NewTokenResponse(pair *authcommand.TokenPair) AuthResponse {
+	return AuthResponse{
+		AccessToken:          pair.AccessToken,
+		AccessTokenExpiresAt: &pair.ExpiresAt,
+		RefreshToken:         pair.RefreshToken,
+	}
+}
+
+// LoginResponder answers a completed login with the session ID, or with only its CSRF token when
+// the session is set as a cookie instead, or in token mode trades the new session for an access token and a refresh token
+type LoginResponder struct {
+	tokenCommandService authcommand.TokenCommandService
+	sessionCookie       *SessionCookie
+}
+
+// NewLoginResponder takes a nil token service in session mode and a nil cookie when cookies are disabled
+func NewLoginResponder(tokenCommandService authcommand.TokenCommandService, sessionCookie *SessionCookie) *LoginResponder {
+	return &LoginResponder{
+		tokenCommandService: tokenCommandService,
+		sessionCookie:       sessionCookie,
+	}
+}
+
+func (r *LoginResponder) Respond(ctx *gin.Context, status int, sessionID string) {
+	if r.tokenCommandService == nil {
+		if r.sessionCookie == nil {
+			HandleSuccessWithStatus(ctx, status, NewAuthResponse(sessionID))
+			return
+		}
+		// The session ID stays in the HttpOnly cookie, out of reach of scripts
+		r.sessionCookie.Set(ctx, sessionID)
+		HandleSuccessWithStatus(ctx, status, AuthResponse{CSRFToken: domainauth.CSRFToken(sessionID)})
+		return
+	}
+
+	pair, err := r.tokenCommandService.IssueTokens(ctx, sessionID)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+	HandleSuccessWithStatus(ctx, status, NewTokenResponse(pair))
+}
+
+// RespondLogin answers with the challenge instead when the login awaits the second factor
+func (r *LoginResponder) RespondLogin(ctx *gin.Context, result *authcommand.LoginResult) {
+	if result.TwoFactorRequired() {
+		HandleSuccess(ctx, AuthResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     result.ChallengeToken,
+			ChallengeExpiresAt: &result.ChallengeExpiresAt,
+		})
+		return
+	}
+	r.Respond(ctx, http.StatusOK, result.SessionID)
+}
+
+// sessionMetadata describes the client opening a session
+func sessionMetadata(ctx *gin.Context) domainauth.SessionMetadata {
+	return domainauth.SessionMetadata{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
+
 func (c *AuthController) Login(ctx *gin.Context) {
 	var cmd domainauth.LoginCommand
 	if err := ctx.ShouldBindJSON(&cmd); err != nil {
 		HandleValidationError(ctx, "invalid request body")
 		return
 	}
+	cmd.Session = sessionMetadata(ctx)
 
-	err := c.authCommandService.Login(ctx, cmd)
+	result, err := c.authCommandService.Login(ctx, cmd)
 	if err != nil {
 		HandleError(ctx, err)
 		return
 	}
 
-	// Get session from context (created by auth command service)
-	sessionID := ctx.GetString("session_id")
-	response := NewAuthResponse(sessionID)
-	HandleSuccess(ctx, response)
+	c.loginResponder.RespondLogin(ctx, result)
 }
 
 func (c *AuthController) Register(ctx *gin.Context) {
@@ -62,6 +138,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		HandleValidationError(ctx, "invalid request body")
 		return
 	}
+	cmd.Session = sessionMetadata(ctx)
 
 	err := c.authCommandService.Register(ctx, cmd)
 	if err != nil {
@@ -71,12 +148,15 @@ func (c *AuthController) Register(ctx *gin.Context) {
 
 	// Get session from context (created by auth command service)
 	sessionID := ctx.GetString("session_id")
-	response := NewAuthResponse(sessionID)
-	HandleSuccessWithStatus(ctx, http.StatusCreated, response)
+	c.loginResponder.Respond(ctx, http.StatusCreated, sessionID)
 }
 
+// Logout ends the session of the X-Session-ID header or the session cookie
 func (c *AuthController) Logout(ctx *gin.Context) {
 	sessionID := ctx.GetHeader("X-Session-ID")
+	if sessionID == "" {
+		sessionID, _ = c.loginResponder.sessionCookie.Get(ctx)
+	}
 	if sessionID == "" {
 		HandleValidationError(ctx, "session_id required")
 		return
@@ -88,6 +168,41 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 		HandleError(ctx, err)
 		return
 	}
+	if c.loginResponder.sessionCookie != nil {
+		c.loginResponder.sessionCookie.Clear(ctx)
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *AuthController) RequestPasswordReset(ctx *gin.Context) {
+	var cmd domainauth.RequestPasswordResetCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	err := c.authCommandService.RequestPasswordReset(ctx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *AuthController) ResetPassword(ctx *gin.Context) {
+	var cmd domainauth.ResetPasswordCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	err := c.authCommandService.ResetPassword(ctx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
 
 	HandleSuccess(ctx, nil)
 }
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"jcourse_go/internal/application/event/command"
+	"jcourse_go/internal/application/event/query"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/event"
+)
+
+type EventAdminController struct {
+	deadLetterCommandService command.DeadLetterCommandService
+	deadLetterQueryService   query.DeadLetterQueryService
+	eventStoreQueryService   query.EventStoreQueryService
+}
+
+func NewEventAdminController(
+	deadLetterCommandService command.DeadLetterCommandService,
+	deadLetterQueryService query.DeadLetterQueryService,
+	eventStoreQueryService query.EventStoreQueryService,
+) *EventAdminController {
+	return &EventAdminController{
+		deadLetterCommandService: deadLetterCommandService,
+		deadLetterQueryService:   deadLetterQueryService,
+		eventStoreQueryService:   eventStoreQueryService,
+	}
+}
+
+// ListEvents searches the event store; from and to are unix timestamps in seconds
+func (c *EventAdminController) ListEvents(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	size, _ := strconv.Atoi(ctx.Query("size"))
+
+	filter := event.EventStoreFilter{
+		Pagination: common.NewPagination(page, size),
+	}
+	if aggregateType := ctx.Query("aggregate_type"); aggregateType != "" {
+		aggregateID, err := strconv.Atoi(ctx.Query("aggregate_id"))
+		if err != nil {
+			HandleValidationError(ctx, "invalid aggregate id")
+			return
+		}
+		filter.Aggregate = &event.AggregateRef{
+			Type: event.AggregateType(aggregateType),
+			ID:   aggregateID,
+		}
+	}
+	if eventTypeStr := ctx.Query("event_type"); eventTypeStr != "" {
+		eventType := event.Type(eventTypeStr)
+		filter.EventType = &eventType
+	}
+	from, err := unixQuery(ctx, "from")
+	if err != nil {
+		HandleValidationError(ctx, "invalid from timestamp")
+		return
+	}
+	to, err := unixQuery(ctx, "to")
+	if err != nil {
+		HandleValidationError(ctx, "invalid to timestamp")
+		return
+	}
+	filter.From, filter.To = from, to
+
+	commonCtx := GetCommonContext(ctx)
+
+	storedEvents, err := c.eventStoreQueryService.ListEvents(commonCtx, filter)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, storedEvents)
+}
+
+// unixQuery reads an optional query parameter holding a unix timestamp in seconds
+func unixQuery(ctx *gin.Context, name string) (*time.Time, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	t := time.Unix(seconds, 0)
+	return &t, nil
+}
+
+func (c *EventAdminController) GetTimeline(ctx *gin.Context) {
+	aggregateID, err := strconv.Atoi(ctx.Param("aggregate_id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid aggregate id")
+		return
+	}
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	size, _ := strconv.Atoi(ctx.Query("size"))
+
+	aggregate := event.AggregateRef{
+		Type: event.AggregateType(ctx.Param("aggregate_type")),
+		ID:   aggregateID,
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	timeline, err := c.eventStoreQueryService.GetTimeline(commonCtx, aggregate, common.NewPagination(page, size))
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, timeline)
+}
+
+func (c *EventAdminController) ListDeadLetters(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	size, _ := strconv.Atoi(ctx.Query("size"))
+
+	filter := event.DeadLetterFilter{
+		Status:     event.DeadLetterStatus(ctx.DefaultQuery("status", string(event.DeadLetterStatusFailed))),
+		Handler:    ctx.Query("handler"),
+		Pagination: common.NewPagination(page, size),
+	}
+	if eventTypeStr := ctx.Query("event_type"); eventTypeStr != "" {
+		eventType := event.Type(eventTypeStr)
+		filter.EventType = &eventType
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	deadLetters, err := c.deadLetterQueryService.ListDeadLetters(commonCtx, filter)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, deadLetters)
+}
+
+func (c *EventAdminController) GetDeadLetter(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		HandleValidationError(ctx, "invalid dead letter id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	deadLetter, err := c.deadLetterQueryService.GetDeadLetter(commonCtx, id)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, deadLetter)
+}
+
+func (c *EventAdminController) ReplayDeadLetter(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		HandleValidationError(ctx, "invalid dead letter id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.deadLetterCommandService.Replay(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *EventAdminController) DiscardDeadLetter(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		HandleValidationError(ctx, "invalid dead letter id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.deadLetterCommandService.Discard(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
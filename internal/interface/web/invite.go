@@ -0,0 +1,78 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	authquery "jcourse_go/internal/application/auth/query"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/internal/domain/common"
+)
+
+type InviteController struct {
+	inviteCommandService authcommand.InviteCommandService
+	inviteQueryService   authquery.InviteQueryService
+}
+
+func NewInviteController(
+	inviteCommandService authcommand.InviteCommandService,
+	inviteQueryService authquery.InviteQueryService,
+) *InviteController {
+	return &InviteController{
+		inviteCommandService: inviteCommandService,
+		inviteQueryService:   inviteQueryService,
+	}
+}
+
+func (c *InviteController) ListInvites(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	size, _ := strconv.Atoi(ctx.Query("size"))
+
+	commonCtx := GetCommonContext(ctx)
+
+	invites, err := c.inviteQueryService.ListInvites(commonCtx, common.NewPagination(page, size))
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, invites)
+}
+
+func (c *InviteController) CreateInvite(ctx *gin.Context) {
+	var cmd domainauth.CreateInviteCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	invite, err := c.inviteCommandService.CreateInvite(commonCtx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccessWithStatus(ctx, http.StatusCreated, invite)
+}
+
+func (c *InviteController) RevokeInvite(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid invite id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.inviteCommandService.RevokeInvite(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
@@ -1,25 +1,64 @@
 package web
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 
-	authquery "jcourse_go/internal/application/auth/query"
+	authcommand "jcourse_go/internal/application/auth/command"
+	domainauth "jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/domain/common"
 	"jcourse_go/pkg/apperror"
 )
 
-// AuthMiddleware extracts session ID from header and sets user context
-func AuthMiddleware(authQueryService authquery.AuthQueryService) gin.HandlerFunc {
+// AuthMiddleware authenticates the request with a personal API token or, in token mode, an access token
+// from the Authorization header, or a session ID from the X-Session-ID header or the session cookie,
+// and sets the user context. Authenticating with a session also extends it and records the user as last seen.
+// tokenCommandService is nil in session mode, sessionCookie when cookies are disabled.
+func AuthMiddleware(
+	sessionCommandService authcommand.SessionCommandService,
+	apiTokenCommandService authcommand.APITokenCommandService,
+	tokenCommandService authcommand.TokenCommandService,
+	sessionCookie *SessionCookie,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionID := c.GetHeader("X-Session-ID")
-		if sessionID == "" {
-			// No session ID provided, continue as anonymous user
+		var user *common.User
+		var err error
+		if token, ok := bearerToken(c); ok {
+			switch {
+			case domainauth.IsAPIToken(token):
+				user, err = apiTokenCommandService.Authenticate(c, token)
+			case tokenCommandService != nil:
+				user, err = tokenCommandService.Authenticate(c, token)
+			default:
+				HandleError(c, apperror.ErrSession.WithMessage("unsupported bearer token"))
+				c.Abort()
+				return
+			}
+		} else if sessionID := c.GetHeader("X-Session-ID"); sessionID != "" {
+			user, err = sessionCommandService.Authenticate(c, sessionID)
+		} else if sessionID, ok := sessionCookie.Get(c); ok {
+			// Browsers attach the cookie to cross-site requests as well, so those changing state must prove
+			// they come from our frontend; headers, unlike cookies, are never added by the browser
+			if !sessionCookie.CheckCSRF(c, sessionID) {
+				HandleError(c, apperror.ErrPermission.WithMessage("missing or invalid CSRF token"))
+				c.Abort()
+				return
+			}
+			user, err = sessionCommandService.Authenticate(c, sessionID)
+			if errors.Is(err, apperror.ErrSession) {
+				// The cookie outlives idle sessions; drop it and continue as an anonymous user,
+				// so public pages and logging in again still work
+				sessionCookie.Clear(c)
+				user, err = &common.User{UserID: 0, Role: common.RoleUser}, nil
+			}
+		} else {
+			// No credentials provided, continue as anonymous user
 			c.Set("user", &common.User{UserID: 0, Role: common.RoleUser})
 			c.Next()
 			return
 		}
-
-		user, err := authQueryService.GetUserFromSession(c, sessionID)
 		if err != nil {
 			HandleError(c, err)
 			c.Abort()
@@ -31,6 +70,15 @@ func AuthMiddleware(authQueryService authquery.AuthQueryService) gin.HandlerFunc
 	}
 }
 
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if len(header) < len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(header[len("Bearer "):]), true
+}
+
 // GetCommonContext creates a CommonContext from the gin context
 func GetCommonContext(c *gin.Context) *common.CommonContext {
 	user, exists := c.Get("user")
@@ -84,6 +132,12 @@ func RequireAdmin() gin.HandlerFunc {
 			return
 		}
 
+		if userObj.TwoFactorPending {
+			HandleError(c, apperror.ErrPermission.WithMessage("two-factor authentication must be enabled for admin access"))
+			c.Abort()
+			return
+		}
+
 		if userObj.Role != common.RoleAdmin {
 			HandleError(c, apperror.ErrPermission.WithMessage("admin access required"))
 			c.Abort()
@@ -93,3 +147,30 @@ func RequireAdmin() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireScope middleware ensures a request made with an API token was granted the scope;
+// requests with a session are not restricted
+func RequireScope(scope common.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !GetCommonContext(c).User.HasScope(scope) {
+			HandleError(c, apperror.ErrPermission.WithMessage("token scope "+string(scope)+" required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession middleware rejects API tokens on account management routes, which need an interactive login
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetCommonContext(c).User.APITokenID != 0 {
+			HandleError(c, apperror.ErrPermission.WithMessage("this action requires a login session"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
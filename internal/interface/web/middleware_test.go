@@ -0,0 +1,129 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"jcourse_go/internal/config"
+	domainauth "jcourse_go/internal/domain/auth"
+)
+
+func newCookieTestRouter(sessions *MockSessionCommandService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(sessions, nil, nil, NewSessionCookie(config.SessionCookieConfig{Enabled: true})))
+	handler := func(ctx *gin.Context) {
+		HandleSuccess(ctx, GetCommonContext(ctx).User.UserID)
+	}
+	router.GET("/me", handler)
+	router.POST("/me", handler)
+	return router
+}
+
+func cookieRequest(method, sessionID, csrfToken string) *http.Request {
+	req := httptest.NewRequest(method, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: DefaultSessionCookieName, Value: sessionID})
+	if csrfToken != "" {
+		req.Header.Set(CSRFHeader, csrfToken)
+	}
+	return req
+}
+
+func TestAuthMiddleware_SessionCookieRequiresCSRFToken(t *testing.T) {
+	sessions := &MockSessionCommandService{Sessions: map[string]int{"session": 1}}
+	router := newCookieTestRouter(sessions)
+
+	tests := []struct {
+		name     string
+		request  *http.Request
+		expected int
+	}{
+		{"safe method", cookieRequest(http.MethodGet, "session", ""), http.StatusOK},
+		{"missing token", cookieRequest(http.MethodPost, "session", ""), http.StatusForbidden},
+		{"token of another session", cookieRequest(http.MethodPost, "session", domainauth.CSRFToken("other")), http.StatusForbidden},
+		{"matching token", cookieRequest(http.MethodPost, "session", domainauth.CSRFToken("session")), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, tt.request)
+		assert.Equal(t, tt.expected, recorder.Code, tt.name)
+	}
+	// Requests failing the CSRF check are refused before the session is looked up
+	assert.Equal(t, 2, sessions.Authenticated)
+}
+
+func TestAuthMiddleware_StaleSessionCookieIsCleared(t *testing.T) {
+	router := newCookieTestRouter(&MockSessionCommandService{})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, cookieRequest(http.MethodGet, "expired", ""))
+
+	// The request goes on as anonymous
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"data":0`)
+	cookies := recorder.Result().Cookies()
+	assert.NotEmpty(t, cookies)
+	for _, cookie := range cookies {
+		assert.Empty(t, cookie.Value)
+		assert.True(t, cookie.MaxAge < 0)
+	}
+}
+
+func TestNewSessionCookie(t *testing.T) {
+	assert.Nil(t, NewSessionCookie(config.SessionCookieConfig{}))
+
+	cookie := NewSessionCookie(config.SessionCookieConfig{Enabled: true, SameSite: "none", Insecure: true})
+	assert.Equal(t, DefaultSessionCookieName, cookie.name)
+	assert.Equal(t, "/", cookie.path)
+	assert.Equal(t, http.SameSiteNoneMode, cookie.sameSite)
+	assert.True(t, cookie.secure)
+}
+
+func TestLoginResponder_CookieKeepsSessionIDOutOfBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+
+	NewLoginResponder(nil, NewSessionCookie(config.SessionCookieConfig{Enabled: true})).Respond(ctx, http.StatusOK, "session")
+
+	assert.NotContains(t, recorder.Body.String(), "session_id")
+	assert.Contains(t, recorder.Body.String(), domainauth.CSRFToken("session"))
+	assert.NotEmpty(t, recorder.Result().Cookies())
+}
+
+func TestCORSMiddleware_CredentialsOnlyForAllowedOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORSMiddleware([]string{"https://course.sjtu.plus/app"}))
+	router.GET("/me", func(ctx *gin.Context) {
+		HandleSuccess(ctx, nil)
+	})
+
+	tests := []struct {
+		origin      string
+		allowOrigin string
+		credentials string
+	}{
+		{"https://course.sjtu.plus", "https://course.sjtu.plus", "true"},
+		{"https://evil.example.com", "*", ""},
+		{"", "*", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, tt.allowOrigin, recorder.Header().Get("Access-Control-Allow-Origin"), tt.origin)
+		assert.Equal(t, tt.credentials, recorder.Header().Get("Access-Control-Allow-Credentials"), tt.origin)
+		assert.Equal(t, "Origin", recorder.Header().Get("Vary"), tt.origin)
+	}
+}
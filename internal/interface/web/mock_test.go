@@ -0,0 +1,31 @@
+package web
+
+import (
+	"context"
+
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/pkg/apperror"
+)
+
+// MockSessionCommandService authenticates the sessions it was given and counts the attempts
+type MockSessionCommandService struct {
+	Sessions      map[string]int
+	Authenticated int
+}
+
+func (m *MockSessionCommandService) Authenticate(ctx context.Context, token string) (*common.User, error) {
+	m.Authenticated++
+	userID, ok := m.Sessions[token]
+	if !ok {
+		return nil, apperror.ErrSession.WithMessage("session not found or expired")
+	}
+	return &common.User{UserID: userID, Role: common.RoleUser}, nil
+}
+
+func (m *MockSessionCommandService) RevokeSession(commonCtx *common.CommonContext, sessionID int) error {
+	return nil
+}
+
+func (m *MockSessionCommandService) RevokeOtherSessions(commonCtx *common.CommonContext) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,62 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	domainauth "jcourse_go/internal/domain/auth"
+	"jcourse_go/pkg/apperror"
+)
+
+type OAuthController struct {
+	oauthCommandService authcommand.OAuthCommandService
+	loginResponder      *LoginResponder
+}
+
+func NewOAuthController(oauthCommandService authcommand.OAuthCommandService, loginResponder *LoginResponder) *OAuthController {
+	return &OAuthController{
+		oauthCommandService: oauthCommandService,
+		loginResponder:      loginResponder,
+	}
+}
+
+// Login redirects the browser to the identity provider
+func (c *OAuthController) Login(ctx *gin.Context) {
+	authURL, err := c.oauthCommandService.StartLogin(ctx, ctx.Param("provider"))
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// Callback completes the login the identity provider redirected back from
+func (c *OAuthController) Callback(ctx *gin.Context) {
+	if providerError := ctx.Query("error"); providerError != "" {
+		HandleError(ctx, apperror.ErrWrongAuth.WithUserMessage("Login was cancelled or denied").
+			WithMetadata("provider_error", providerError))
+		return
+	}
+
+	cmd := domainauth.OAuthCallbackCommand{
+		Provider: ctx.Param("provider"),
+		Code:     ctx.Query("code"),
+		State:    ctx.Query("state"),
+		Session:  sessionMetadata(ctx),
+	}
+	if cmd.Code == "" || cmd.State == "" {
+		HandleValidationError(ctx, "code and state are required")
+		return
+	}
+
+	result, err := c.oauthCommandService.CompleteLogin(ctx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	c.loginResponder.RespondLogin(ctx, result)
+}
@@ -2,7 +2,10 @@ package web
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -33,6 +36,10 @@ func HandleError(ctx *gin.Context, err error) {
 	// Determine HTTP status from error category
 	var appErr *apperror.AppError
 	if errors.As(err, &appErr) {
+		if appErr.RetryAfter > 0 {
+			// Retry-After is in whole seconds; round up so clients never retry too early
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
 		ctx.JSON(appErr.HTTPStatus(), response)
 	} else {
 		ctx.JSON(http.StatusInternalServerError, response)
@@ -66,11 +73,25 @@ func HandleValidationError(ctx *gin.Context, message string) {
 	ctx.JSON(apperror.ErrWrongInput.HTTPStatus(), response)
 }
 
-// CORSMiddleware handles Cross-Origin Resource Sharing
-func CORSMiddleware() gin.HandlerFunc {
+// CORSMiddleware handles Cross-Origin Resource Sharing. Only the allowed origins, given as URLs
+// whose scheme and host are compared, may send credentials; other origins are answered with a wildcard.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if u, err := url.Parse(origin); err == nil && u.Scheme != "" && u.Host != "" {
+			allowed[u.Scheme+"://"+u.Host] = true
+		}
+	}
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		// The answer depends on the origin, so caches must not share it between origins
+		c.Writer.Header().Add("Vary", "Origin")
+		if origin := c.GetHeader("Origin"); allowed[origin] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		} else {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		}
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
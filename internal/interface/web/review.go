@@ -8,6 +8,8 @@ import (
 
 	"jcourse_go/internal/application/review/command"
 	"jcourse_go/internal/application/review/query"
+	"jcourse_go/internal/application/review/stream"
+	"jcourse_go/internal/domain/common"
 	"jcourse_go/internal/domain/review"
 	"jcourse_go/internal/interface/dto"
 )
@@ -15,12 +17,18 @@ import (
 type ReviewController struct {
 	reviewCommandService command.ReviewCommandService
 	reviewQueryService   query.ReviewQueryService
+	reviewStreamService  stream.ReviewStreamService
 }
 
-func NewReviewController(reviewCommandService command.ReviewCommandService, reviewQueryService query.ReviewQueryService) *ReviewController {
+func NewReviewController(
+	reviewCommandService command.ReviewCommandService,
+	reviewQueryService query.ReviewQueryService,
+	reviewStreamService stream.ReviewStreamService,
+) *ReviewController {
 	return &ReviewController{
 		reviewCommandService: reviewCommandService,
 		reviewQueryService:   reviewQueryService,
+		reviewStreamService:  reviewStreamService,
 	}
 }
 
@@ -88,10 +96,26 @@ func (c *ReviewController) DeleteReview(ctx *gin.Context) {
 	HandleSuccess(ctx, nil)
 }
 
+// reviewListOptions reads the sort order and page of a review list: a cursor with a limit,
+// or a page number with a size as in the other lists
+func reviewListOptions(ctx *gin.Context) review.ReviewListOptions {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		limit, _ = strconv.Atoi(ctx.Query("size"))
+	}
+	page, _ := strconv.Atoi(ctx.Query("page"))
+
+	return review.ReviewListOptions{
+		Sort:       review.ReviewSort(ctx.Query("sort")),
+		Cursor:     ctx.Query("cursor"),
+		Pagination: common.NewPagination(page, limit),
+	}
+}
+
 func (c *ReviewController) GetLatestReviews(ctx *gin.Context) {
 	commonCtx := GetCommonContext(ctx)
 
-	reviews, err := c.reviewQueryService.LatestReviews(commonCtx)
+	reviews, err := c.reviewQueryService.LatestReviews(commonCtx, reviewListOptions(ctx))
 	if err != nil {
 		HandleError(ctx, err)
 		return
@@ -101,7 +125,7 @@ func (c *ReviewController) GetLatestReviews(ctx *gin.Context) {
 }
 
 func (c *ReviewController) GetCourseReviews(ctx *gin.Context) {
-	courseIDStr := ctx.Param("courseId")
+	courseIDStr := ctx.Param("id")
 	courseID, err := strconv.Atoi(courseIDStr)
 	if err != nil {
 		HandleValidationError(ctx, "invalid course id")
@@ -110,7 +134,7 @@ func (c *ReviewController) GetCourseReviews(ctx *gin.Context) {
 
 	commonCtx := GetCommonContext(ctx)
 
-	reviews, err := c.reviewQueryService.CourseReviews(commonCtx, courseID)
+	reviews, err := c.reviewQueryService.CourseReviews(commonCtx, courseID, reviewListOptions(ctx))
 	if err != nil {
 		HandleError(ctx, err)
 		return
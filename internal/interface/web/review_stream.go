@@ -0,0 +1,77 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"jcourse_go/internal/application/review/stream"
+)
+
+const (
+	ReviewStreamHeartbeat = 15 * time.Second
+	reviewStreamEvent     = "review"
+	heartbeatEvent        = "ping"
+)
+
+// StreamReviews pushes new reviews as Server-Sent Events, resuming after the Last-Event-ID review
+func (c *ReviewController) StreamReviews(ctx *gin.Context) {
+	var filter stream.Filter
+	if courseIDStr := ctx.Query("course_id"); courseIDStr != "" {
+		courseID, err := strconv.Atoi(courseIDStr)
+		if err != nil {
+			HandleValidationError(ctx, "invalid course id")
+			return
+		}
+		filter.CourseID = &courseID
+	}
+	if teacherIDStr := ctx.Query("teacher_id"); teacherIDStr != "" {
+		teacherID, err := strconv.Atoi(teacherIDStr)
+		if err != nil {
+			HandleValidationError(ctx, "invalid teacher id")
+			return
+		}
+		filter.TeacherID = &teacherID
+	}
+
+	lastEventIDStr := ctx.GetHeader("Last-Event-ID")
+	if lastEventIDStr == "" {
+		lastEventIDStr = ctx.Query("last_event_id")
+	}
+	lastEventID, _ := strconv.Atoi(lastEventIDStr)
+
+	backlog, updates, unsubscribe := c.reviewStreamService.Subscribe(filter, lastEventID)
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	for _, review := range backlog {
+		ctx.Render(-1, sse.Event{Event: reviewStreamEvent, Id: strconv.Itoa(review.ID), Data: review})
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(ReviewStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case review, ok := <-updates:
+			if !ok {
+				return
+			}
+			ctx.Render(-1, sse.Event{Event: reviewStreamEvent, Id: strconv.Itoa(review.ID), Data: review})
+		case t := <-heartbeat.C:
+			ctx.Render(-1, sse.Event{Event: heartbeatEvent, Data: t.Unix()})
+		}
+		ctx.Writer.Flush()
+	}
+}
@@ -5,19 +5,31 @@ import (
 
 	"jcourse_go/internal/app"
 	"jcourse_go/internal/application/auth"
+	"jcourse_go/internal/domain/common"
 )
 
-func RegisterRouter(g *gin.Engine, s *app.ServiceContainer) {
-	authController := NewAuthController(s.AuthCommandService, s.AuthQueryService, s.CodeService.(auth.VerificationCodeService))
+// RegisterRouter registers the API; sessionCookie is nil when session cookies are disabled
+func RegisterRouter(g *gin.Engine, s *app.ServiceContainer, sessionCookie *SessionCookie) {
+	loginResponder := NewLoginResponder(s.TokenCommandService, sessionCookie)
+	authController := NewAuthController(s.AuthCommandService, s.AuthQueryService, s.CodeService.(auth.VerificationCodeService), loginResponder)
 	courseController := NewCourseController(s.CourseCommandService, s.CourseQueryService)
-	reviewController := NewReviewController(s.ReviewCommandService, s.ReviewQueryService)
+	reviewController := NewReviewController(s.ReviewCommandService, s.ReviewQueryService, s.ReviewStreamService)
 	pointController := NewUserPointController(s.PointCommandService, s.PointQueryService)
 	userController := NewUserController(s.UserCommandService, s.UserQueryService, s.ReviewQueryService)
+	sessionController := NewSessionController(s.SessionCommandService, s.SessionQueryService)
+	oauthController := NewOAuthController(s.OAuthCommandService, loginResponder)
+	twoFactorController := NewTwoFactorController(s.TwoFactorCommandService, loginResponder)
+	apiTokenController := NewAPITokenController(s.APITokenCommandService, s.APITokenQueryService)
+	inviteController := NewInviteController(s.InviteCommandService, s.InviteQueryService)
+	adminUserController := NewAdminUserController(s.AdminUserCommandService, s.AdminUserQueryService)
+	accountController := NewAccountController(s.AccountCommandService, s.AccountExportQueryService)
 	announcementController := NewAnnouncementController(s.AnnouncementQueryService)
 	statisticsController := NewStatisticsController(s.StatisticsQueryService, s.DailyStatisticsService)
+	eventAdminController := NewEventAdminController(s.DeadLetterCommandService, s.DeadLetterQueryService, s.EventStoreQueryService)
+	webhookController := NewWebhookController(s.WebhookCommandService, s.WebhookQueryService)
 
 	// Apply authentication middleware to all routes
-	g.Use(AuthMiddleware(s.AuthQueryService))
+	g.Use(AuthMiddleware(s.SessionCommandService, s.APITokenCommandService, s.TokenCommandService, sessionCookie))
 
 	// API version 1 group
 	v1 := g.Group("/api/v1")
@@ -26,50 +38,119 @@ func RegisterRouter(g *gin.Engine, s *app.ServiceContainer) {
 	auth := v1.Group("/auth")
 	{
 		auth.POST("/login", authController.Login)
+		auth.POST("/login/2fa", twoFactorController.VerifyLogin)
 		auth.POST("/register", authController.Register)
 		auth.POST("/logout", authController.Logout)
 		auth.POST("/send-code", authController.SendVerificationCode)
+		auth.POST("/reset-password/request", authController.RequestPasswordReset)
+		auth.POST("/reset-password/confirm", authController.ResetPassword)
+		auth.POST("/email/revert", userController.RevertEmailChange)
+		auth.GET("/oauth/:provider/login", oauthController.Login)
+		auth.GET("/oauth/:provider/callback", oauthController.Callback)
 	}
 
+	// Token mode routes, see config.AuthConfig
+	if s.TokenCommandService != nil {
+		tokenController := NewTokenController(s.TokenCommandService)
+		auth.POST("/token/refresh", tokenController.Refresh)
+		auth.POST("/token/revoke", tokenController.Revoke)
+	}
+
+	// Routes are limited to the scopes of the API token a request is made with, see RequireScope
+	courseRead, courseWrite := RequireScope(common.ScopeCourseRead), RequireScope(common.ScopeCourseWrite)
+	reviewRead, reviewWrite := RequireScope(common.ScopeReviewRead), RequireScope(common.ScopeReviewWrite)
+	userRead, userWrite := RequireScope(common.ScopeUserRead), RequireScope(common.ScopeUserWrite)
+
 	// Course routes
 	courses := v1.Group("/course")
 	{
-		courses.GET("/filter", courseController.GetCourseFilter)
-		courses.GET("/enroll", RequireAuth(), courseController.GetUserEnrolledCourses)
-		courses.POST("/enroll", RequireAuth(), courseController.AddUserEnrolledCourse)
-		courses.GET("/search", courseController.SearchCourses)
-		courses.GET("/:id", courseController.GetCourseDetail)
-		courses.GET("/:id/review", reviewController.GetCourseReviews)
-		courses.POST("/:id/watch", RequireAuth(), courseController.WatchCourse)
+		courses.GET("/filter", courseRead, courseController.GetCourseFilter)
+		courses.GET("/enroll", RequireAuth(), courseRead, courseController.GetUserEnrolledCourses)
+		courses.POST("/enroll", RequireAuth(), courseWrite, courseController.AddUserEnrolledCourse)
+		courses.GET("/search", courseRead, courseController.SearchCourses)
+		courses.GET("/:id", courseRead, courseController.GetCourseDetail)
+		courses.GET("/:id/review", reviewRead, reviewController.GetCourseReviews)
+		courses.POST("/:id/watch", RequireAuth(), courseWrite, courseController.WatchCourse)
 	}
 
 	// Review routes
 	reviews := v1.Group("/review")
 	{
-		reviews.GET("", reviewController.GetLatestReviews)
-		reviews.POST("", RequireAuth(), reviewController.WriteReview)
-		reviews.PUT("/:id", RequireAuth(), reviewController.UpdateReview)
-		reviews.DELETE("/:id", RequireAuth(), reviewController.DeleteReview)
-		reviews.POST("/:id/action", RequireAuth(), reviewController.PostReviewAction)
-		reviews.DELETE("/:id/action/:actionID", RequireAuth(), reviewController.DeleteReviewAction)
-		reviews.GET("/:id/revision", reviewController.GetReviewRevisions)
+		reviews.GET("", reviewRead, reviewController.GetLatestReviews)
+		reviews.GET("/stream", reviewRead, reviewController.StreamReviews)
+		reviews.POST("", RequireAuth(), reviewWrite, reviewController.WriteReview)
+		reviews.PUT("/:id", RequireAuth(), reviewWrite, reviewController.UpdateReview)
+		reviews.DELETE("/:id", RequireAuth(), reviewWrite, reviewController.DeleteReview)
+		reviews.POST("/:id/action", RequireAuth(), reviewWrite, reviewController.PostReviewAction)
+		reviews.DELETE("/:id/action/:actionID", RequireAuth(), reviewWrite, reviewController.DeleteReviewAction)
+		reviews.GET("/:id/revision", reviewRead, reviewController.GetReviewRevisions)
 	}
 
 	// User routes
 	users := v1.Group("/user")
 	{
-		users.GET("/info", RequireAuth(), userController.GetUserInfo)
-		users.POST("/info", RequireAuth(), userController.UpdateUserInfo)
-		users.GET("/point", RequireAuth(), pointController.GetUserPoint)
-		users.GET("/review", RequireAuth(), userController.GetUserReviews)
+		users.GET("/info", RequireAuth(), userRead, userController.GetUserInfo)
+		users.POST("/info", RequireAuth(), userWrite, userController.UpdateUserInfo)
+		users.GET("/point", RequireAuth(), userRead, pointController.GetUserPoint)
+		users.GET("/review", RequireAuth(), reviewRead, userController.GetUserReviews)
+	}
+
+	// Account security routes, not available to API tokens
+	account := v1.Group("/user")
+	account.Use(RequireAuth(), RequireSession())
+	{
+		account.GET("/export", accountController.Export)
+		account.DELETE("", accountController.RequestDeletion)
+		account.POST("/restore", accountController.CancelDeletion)
+		account.POST("/email", userController.RequestEmailChange)
+		account.POST("/email/confirm", userController.ConfirmEmailChange)
+		account.GET("/sessions", sessionController.ListSessions)
+		account.DELETE("/sessions", sessionController.RevokeOtherSessions)
+		account.DELETE("/sessions/:id", sessionController.RevokeSession)
+		account.POST("/2fa/enroll", twoFactorController.Enroll)
+		account.POST("/2fa/confirm", twoFactorController.Confirm)
+		account.POST("/2fa/disable", twoFactorController.Disable)
+		account.GET("/tokens", apiTokenController.ListTokens)
+		account.POST("/tokens", apiTokenController.CreateToken)
+		account.DELETE("/tokens/:id", apiTokenController.RevokeToken)
 	}
 
 	// Admin routes
 	admin := v1.Group("/admin")
 	admin.Use(RequireAdmin())
 	{
-		admin.POST("/point", pointController.CreatePoint)
-		admin.POST("/point/transaction", pointController.Transaction)
+		adminPoints := RequireScope(common.ScopeAdminPoints)
+		admin.POST("/point", adminPoints, pointController.CreatePoint)
+		admin.POST("/point/transaction", adminPoints, pointController.Transaction)
+
+		adminEvents := RequireScope(common.ScopeAdminEvents)
+		admin.GET("/events", adminEvents, eventAdminController.ListEvents)
+		admin.GET("/events/timeline/:aggregate_type/:aggregate_id", adminEvents, eventAdminController.GetTimeline)
+		admin.GET("/events/dead-letters", adminEvents, eventAdminController.ListDeadLetters)
+		admin.GET("/events/dead-letters/:id", adminEvents, eventAdminController.GetDeadLetter)
+		admin.POST("/events/dead-letters/:id/replay", adminEvents, eventAdminController.ReplayDeadLetter)
+		admin.DELETE("/events/dead-letters/:id", adminEvents, eventAdminController.DiscardDeadLetter)
+
+		adminWebhooks := RequireScope(common.ScopeAdminWebhooks)
+		admin.GET("/webhooks", adminWebhooks, webhookController.ListSubscriptions)
+		admin.POST("/webhooks", adminWebhooks, webhookController.CreateSubscription)
+		admin.PUT("/webhooks/:id", adminWebhooks, webhookController.UpdateSubscription)
+		admin.DELETE("/webhooks/:id", adminWebhooks, webhookController.DeleteSubscription)
+		admin.GET("/webhooks/:id/deliveries", adminWebhooks, webhookController.ListDeliveries)
+
+		adminInvites := RequireScope(common.ScopeAdminInvites)
+		admin.GET("/invites", adminInvites, inviteController.ListInvites)
+		admin.POST("/invites", adminInvites, inviteController.CreateInvite)
+		admin.DELETE("/invites/:id", adminInvites, inviteController.RevokeInvite)
+
+		adminUsers := RequireScope(common.ScopeAdminUsers)
+		admin.GET("/users", adminUsers, adminUserController.SearchUsers)
+		admin.GET("/users/:id", adminUsers, adminUserController.GetUser)
+		admin.GET("/users/:id/reviews", adminUsers, adminUserController.GetUserReviews)
+		admin.GET("/users/:id/points", adminUsers, adminUserController.GetUserPoints)
+		admin.POST("/users/:id/suspend", adminUsers, adminUserController.SuspendUser)
+		admin.POST("/users/:id/unsuspend", adminUsers, adminUserController.UnsuspendUser)
+		admin.PUT("/users/:id/role", adminUsers, adminUserController.ChangeRole)
 	}
 
 	announcements := v1.Group("/announcement")
@@ -83,6 +164,6 @@ func RegisterRouter(g *gin.Engine, s *app.ServiceContainer) {
 		statistics.GET("/daily/:date", statisticsController.GetDailyStatistics)
 		statistics.GET("/daily/range", statisticsController.GetDailyStatisticsRange)
 		statistics.GET("/daily/latest", statisticsController.GetLatestDailyStatistics)
-		statistics.POST("/daily/calculate", RequireAdmin(), statisticsController.TriggerDailyStatistics)
+		statistics.POST("/daily/calculate", RequireAdmin(), RequireScope(common.ScopeAdminStatistics), statisticsController.TriggerDailyStatistics)
 	}
 }
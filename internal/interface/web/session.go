@@ -0,0 +1,67 @@
+package web
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	authquery "jcourse_go/internal/application/auth/query"
+)
+
+type SessionController struct {
+	sessionCommandService authcommand.SessionCommandService
+	sessionQueryService   authquery.SessionQueryService
+}
+
+func NewSessionController(
+	sessionCommandService authcommand.SessionCommandService,
+	sessionQueryService authquery.SessionQueryService,
+) *SessionController {
+	return &SessionController{
+		sessionCommandService: sessionCommandService,
+		sessionQueryService:   sessionQueryService,
+	}
+}
+
+func (c *SessionController) ListSessions(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	sessions, err := c.sessionQueryService.ListSessions(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, sessions)
+}
+
+func (c *SessionController) RevokeSession(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		HandleValidationError(ctx, "invalid session id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.sessionCommandService.RevokeSession(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+// RevokeOtherSessions signs out every device but the one making the request
+func (c *SessionController) RevokeOtherSessions(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	revoked, err := c.sessionCommandService.RevokeOtherSessions(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, gin.H{"revoked": revoked})
+}
@@ -0,0 +1,110 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"jcourse_go/internal/config"
+	domainauth "jcourse_go/internal/domain/auth"
+)
+
+const (
+	DefaultSessionCookieName = "jcourse_session"
+	CSRFHeader               = "X-CSRF-Token"
+)
+
+// SessionCookie keeps the session of browser clients in an HttpOnly cookie, out of reach of scripts.
+// A second cookie, readable by the frontend, carries the CSRF token that requests changing state
+// must send back in the X-CSRF-Token header.
+type SessionCookie struct {
+	name     string
+	domain   string
+	path     string
+	sameSite http.SameSite
+	secure   bool
+}
+
+// NewSessionCookie returns nil when session cookies are disabled
+func NewSessionCookie(conf config.SessionCookieConfig) *SessionCookie {
+	if !conf.Enabled {
+		return nil
+	}
+
+	cookie := &SessionCookie{
+		name:     conf.Name,
+		domain:   conf.Domain,
+		path:     conf.Path,
+		sameSite: http.SameSiteLaxMode,
+		secure:   !conf.Insecure,
+	}
+	if cookie.name == "" {
+		cookie.name = DefaultSessionCookieName
+	}
+	if cookie.path == "" {
+		cookie.path = "/"
+	}
+	switch strings.ToLower(conf.SameSite) {
+	case "strict":
+		cookie.sameSite = http.SameSiteStrictMode
+	case "none":
+		// Browsers drop SameSite=None cookies without Secure
+		cookie.sameSite = http.SameSiteNoneMode
+		cookie.secure = true
+	}
+	return cookie
+}
+
+func (s *SessionCookie) csrfName() string {
+	return s.name + "_csrf"
+}
+
+// Set stores the session in the cookies; the server still expires the session on its own schedule
+func (s *SessionCookie) Set(ctx *gin.Context, sessionID string) {
+	maxAge := int(domainauth.SessionMaxLifetime.Seconds())
+	http.SetCookie(ctx.Writer, s.cookie(s.name, sessionID, maxAge, true))
+	http.SetCookie(ctx.Writer, s.cookie(s.csrfName(), domainauth.CSRFToken(sessionID), maxAge, false))
+}
+
+func (s *SessionCookie) Clear(ctx *gin.Context) {
+	http.SetCookie(ctx.Writer, s.cookie(s.name, "", -1, true))
+	http.SetCookie(ctx.Writer, s.cookie(s.csrfName(), "", -1, false))
+}
+
+// Get returns the session of the request's cookie; it reports false when cookies are disabled
+func (s *SessionCookie) Get(ctx *gin.Context) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	sessionID, err := ctx.Cookie(s.name)
+	if err != nil || sessionID == "" {
+		return "", false
+	}
+	return sessionID, true
+}
+
+// CheckCSRF reports whether a request authenticated with the session cookie may proceed: safe methods
+// always may, the others must carry the CSRF token of the session
+func (s *SessionCookie) CheckCSRF(ctx *gin.Context, sessionID string) bool {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	token := ctx.GetHeader(CSRFHeader)
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(domainauth.CSRFToken(sessionID))) == 1
+}
+
+func (s *SessionCookie) cookie(name, value string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   s.domain,
+		Path:     s.path,
+		MaxAge:   maxAge,
+		Secure:   s.secure,
+		HttpOnly: httpOnly,
+		SameSite: s.sameSite,
+	}
+}
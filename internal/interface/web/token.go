@@ -0,0 +1,51 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	domainauth "jcourse_go/internal/domain/auth"
+)
+
+// TokenController renews and revokes the tokens handed out in token mode
+type TokenController struct {
+	tokenCommandService authcommand.TokenCommandService
+}
+
+func NewTokenController(tokenCommandService authcommand.TokenCommandService) *TokenController {
+	return &TokenController{
+		tokenCommandService: tokenCommandService,
+	}
+}
+
+func (c *TokenController) Refresh(ctx *gin.Context) {
+	var cmd domainauth.RefreshTokenCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil || cmd.RefreshToken == "" {
+		HandleValidationError(ctx, "refresh_token required")
+		return
+	}
+
+	pair, err := c.tokenCommandService.Refresh(ctx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, NewTokenResponse(pair))
+}
+
+// Revoke logs out in token mode
+func (c *TokenController) Revoke(ctx *gin.Context) {
+	var cmd domainauth.RefreshTokenCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil || cmd.RefreshToken == "" {
+		HandleValidationError(ctx, "refresh_token required")
+		return
+	}
+
+	if err := c.tokenCommandService.Revoke(ctx, cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
@@ -0,0 +1,87 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authcommand "jcourse_go/internal/application/auth/command"
+	domainauth "jcourse_go/internal/domain/auth"
+)
+
+type TwoFactorController struct {
+	twoFactorCommandService authcommand.TwoFactorCommandService
+	loginResponder          *LoginResponder
+}
+
+func NewTwoFactorController(twoFactorCommandService authcommand.TwoFactorCommandService, loginResponder *LoginResponder) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorCommandService: twoFactorCommandService,
+		loginResponder:          loginResponder,
+	}
+}
+
+// VerifyLogin completes a login that asked for the second factor
+func (c *TwoFactorController) VerifyLogin(ctx *gin.Context) {
+	var cmd domainauth.VerifyTwoFactorCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	sessionID, err := c.twoFactorCommandService.VerifyLogin(ctx, cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	c.loginResponder.Respond(ctx, http.StatusOK, sessionID)
+}
+
+func (c *TwoFactorController) Enroll(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	enrollment, err := c.twoFactorCommandService.Enroll(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, enrollment)
+}
+
+// Confirm enables two-factor authentication and returns the recovery codes
+func (c *TwoFactorController) Confirm(ctx *gin.Context) {
+	var cmd domainauth.TwoFactorCodeCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	recoveryCodes, err := c.twoFactorCommandService.Confirm(commonCtx, cmd.Code)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, gin.H{"recovery_codes": recoveryCodes})
+}
+
+func (c *TwoFactorController) Disable(ctx *gin.Context) {
+	var cmd domainauth.TwoFactorCodeCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.twoFactorCommandService.Disable(commonCtx, cmd.Code); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
@@ -6,6 +6,7 @@ import (
 	authcommand "jcourse_go/internal/application/auth/command"
 	authquery "jcourse_go/internal/application/auth/query"
 	reviewquery "jcourse_go/internal/application/review/query"
+	domainauth "jcourse_go/internal/domain/auth"
 	"jcourse_go/internal/interface/dto"
 )
 
@@ -61,7 +62,7 @@ func (c *UserController) UpdateUserInfo(ctx *gin.Context) {
 func (c *UserController) GetUserReviews(ctx *gin.Context) {
 	commonCtx := GetCommonContext(ctx)
 
-	reviews, err := c.reviewQueryService.GetUserReviews(commonCtx)
+	reviews, err := c.reviewQueryService.GetUserReviews(commonCtx, reviewListOptions(ctx))
 	if err != nil {
 		HandleError(ctx, err)
 		return
@@ -69,3 +70,52 @@ func (c *UserController) GetUserReviews(ctx *gin.Context) {
 
 	HandleSuccess(ctx, reviews)
 }
+
+func (c *UserController) RequestEmailChange(ctx *gin.Context) {
+	var cmd domainauth.RequestEmailChangeCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.userCommandService.RequestEmailChange(commonCtx, cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *UserController) ConfirmEmailChange(ctx *gin.Context) {
+	var cmd domainauth.ConfirmEmailChangeCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.userCommandService.ConfirmEmailChange(commonCtx, cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *UserController) RevertEmailChange(ctx *gin.Context) {
+	var cmd domainauth.RevertEmailChangeCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	if err := c.userCommandService.RevertEmailChange(ctx, cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
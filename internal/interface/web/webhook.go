@@ -0,0 +1,116 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"jcourse_go/internal/application/webhook/command"
+	"jcourse_go/internal/application/webhook/query"
+	"jcourse_go/internal/domain/common"
+	"jcourse_go/internal/domain/webhook"
+)
+
+type WebhookController struct {
+	webhookCommandService command.WebhookCommandService
+	webhookQueryService   query.WebhookQueryService
+}
+
+func NewWebhookController(webhookCommandService command.WebhookCommandService, webhookQueryService query.WebhookQueryService) *WebhookController {
+	return &WebhookController{
+		webhookCommandService: webhookCommandService,
+		webhookQueryService:   webhookQueryService,
+	}
+}
+
+func (c *WebhookController) ListSubscriptions(ctx *gin.Context) {
+	commonCtx := GetCommonContext(ctx)
+
+	subscriptions, err := c.webhookQueryService.ListSubscriptions(commonCtx)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, subscriptions)
+}
+
+func (c *WebhookController) CreateSubscription(ctx *gin.Context) {
+	var cmd webhook.CreateSubscriptionCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	id, err := c.webhookCommandService.CreateSubscription(commonCtx, &cmd)
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccessWithStatus(ctx, http.StatusCreated, gin.H{"id": id})
+}
+
+func (c *WebhookController) UpdateSubscription(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		HandleValidationError(ctx, "invalid subscription id")
+		return
+	}
+
+	var cmd webhook.UpdateSubscriptionCommand
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		HandleValidationError(ctx, "invalid request body")
+		return
+	}
+	cmd.SubscriptionID = id
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.webhookCommandService.UpdateSubscription(commonCtx, &cmd); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *WebhookController) DeleteSubscription(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		HandleValidationError(ctx, "invalid subscription id")
+		return
+	}
+
+	commonCtx := GetCommonContext(ctx)
+
+	if err := c.webhookCommandService.DeleteSubscription(commonCtx, id); err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, nil)
+}
+
+func (c *WebhookController) ListDeliveries(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		HandleValidationError(ctx, "invalid subscription id")
+		return
+	}
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	size, _ := strconv.Atoi(ctx.Query("size"))
+
+	commonCtx := GetCommonContext(ctx)
+
+	deliveries, err := c.webhookQueryService.ListDeliveries(commonCtx, id, common.NewPagination(page, size))
+	if err != nil {
+		HandleError(ctx, err)
+		return
+	}
+
+	HandleSuccess(ctx, deliveries)
+}
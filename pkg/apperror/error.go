@@ -3,6 +3,7 @@ package apperror
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // Error categories
@@ -23,6 +24,7 @@ type AppError struct {
 	Metadata    map[string]any // Additional error context
 	httpStatus  int            // HTTP status code (internal use)
 	UserMessage string         // User-friendly message
+	RetryAfter  time.Duration  // How long the client should wait before retrying, 0 when unknown
 }
 
 func (e *AppError) Error() string {
@@ -55,6 +57,7 @@ func (e *AppError) Wrap(err error) *AppError {
 		Metadata:    e.Metadata,
 		httpStatus:  e.httpStatus,
 		UserMessage: e.UserMessage,
+		RetryAfter:  e.RetryAfter,
 	}
 }
 
@@ -68,6 +71,7 @@ func (e *AppError) WithMessage(message string) *AppError {
 		Metadata:    e.Metadata,
 		httpStatus:  e.httpStatus,
 		UserMessage: e.UserMessage,
+		RetryAfter:  e.RetryAfter,
 	}
 }
 
@@ -88,6 +92,13 @@ func (e *AppError) WithUserMessage(message string) *AppError {
 	return newErr
 }
 
+// WithRetryAfter tells the client how long to wait before retrying
+func (e *AppError) WithRetryAfter(retryAfter time.Duration) *AppError {
+	newErr := e.WithMessage(e.Message)
+	newErr.RetryAfter = retryAfter
+	return newErr
+}
+
 // HTTPStatus returns the appropriate HTTP status code
 func (e *AppError) HTTPStatus() int {
 	if e.httpStatus != 0 {
@@ -107,6 +107,22 @@ func TestUserMessage(t *testing.T) {
 	}
 }
 
+func TestRetryAfter(t *testing.T) {
+	err := ErrRateLimit.WithRetryAfter(90 * time.Second)
+	if err.RetryAfter != 90*time.Second {
+		t.Error("Retry-after should be set")
+	}
+	if ErrRateLimit.RetryAfter != 0 {
+		t.Error("Original error should not be modified")
+	}
+
+	// Retry-after should survive further decoration
+	decorated := err.WithMetadata("email", "user@example.com").WithUserMessage("Too many attempts")
+	if decorated.RetryAfter != 90*time.Second {
+		t.Error("Retry-after should be preserved")
+	}
+}
+
 func TestHTTPStatus(t *testing.T) {
 	tests := []struct {
 		err      *AppError
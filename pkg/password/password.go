@@ -2,31 +2,148 @@ package password
 
 import (
 	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+
+	"jcourse_go/pkg/random"
+)
+
+var (
+	ErrInvalidPassword = errors.New("invalid password")
+	ErrUnknownFormat   = errors.New("unknown password hash format")
 )
 
 type Hasher interface {
 	Hash(password string) string
 	Validate(password, hash string) error
+	// NeedsRehash reports whether the hash is in a legacy format or uses other parameters than the hasher
+	NeedsRehash(hash string) bool
 }
 
-type bcryptHasher struct{}
+// Params are the argon2id cost parameters
+type Params struct {
+	// Memory is the amount of memory used in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
 
-func NewHasher() Hasher {
-	return &bcryptHasher{}
+// DefaultParams follow the OWASP recommendation for argon2id
+var DefaultParams = Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
 }
 
-func (h *bcryptHasher) Hash(password string) string {
-	hasher := sha256.New()
-	hasher.Write([]byte(password))
-	return hex.EncodeToString(hasher.Sum(nil))
+const (
+	saltLength = 16
+	keyLength  = 32
+
+	argon2idPrefix = "$argon2id$"
+)
+
+type argon2idHasher struct {
+	params Params
 }
 
-func (h *bcryptHasher) Validate(password, hash string) error {
-	hashedPassword := h.Hash(password)
-	if hashedPassword != hash {
-		return errors.New("invalid password")
+// NewHasher returns an argon2id hasher; zero parameters fall back to DefaultParams.
+// Hashes are encoded in the PHC string format, e.g. $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func NewHasher(params Params) Hasher {
+	if params.Memory == 0 {
+		params.Memory = DefaultParams.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultParams.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultParams.Parallelism
+	}
+	return &argon2idHasher{params: params}
+}
+
+func (h *argon2idHasher) Hash(password string) string {
+	salt := random.Bytes(saltLength)
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, keyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func (h *argon2idHasher) Validate(password, hash string) error {
+	if isLegacySHA256(hash) {
+		sum := sha256.Sum256([]byte(password))
+		if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(strings.ToLower(hash))) != 1 {
+			return ErrInvalidPassword
+		}
+		return nil
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return ErrInvalidPassword
 	}
 	return nil
 }
+
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params != h.params || len(salt) != saltLength || len(key) != keyLength
+}
+
+// isLegacySHA256 recognizes the unsalted SHA-256 hex digests stored by earlier versions
+func isLegacySHA256(hash string) bool {
+	if len(hash) != hex.EncodedLen(sha256.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+func decodeArgon2id(hash string) (Params, []byte, []byte, error) {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return Params{}, nil, nil, ErrUnknownFormat
+	}
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return Params{}, nil, nil, ErrUnknownFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Params{}, nil, nil, fmt.Errorf("%w: unsupported argon2 version", ErrUnknownFormat)
+	}
+
+	var params Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Params{}, nil, nil, fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Params{}, nil, nil, fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, fmt.Errorf("%w: invalid key", ErrUnknownFormat)
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,59 @@
+package password
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testParams keep the tests fast
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestHasher_HashAndValidate(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	hash := hasher.Hash("correct horse")
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	// Every hash has its own salt
+	assert.NotEqual(t, hash, hasher.Hash("correct horse"))
+
+	assert.NoError(t, hasher.Validate("correct horse", hash))
+	assert.ErrorIs(t, hasher.Validate("wrong horse", hash), ErrInvalidPassword)
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestHasher_LegacySHA256(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	sum := sha256.Sum256([]byte("correct horse"))
+	legacy := hex.EncodeToString(sum[:])
+
+	assert.NoError(t, hasher.Validate("correct horse", legacy))
+	assert.ErrorIs(t, hasher.Validate("wrong horse", legacy), ErrInvalidPassword)
+	assert.True(t, hasher.NeedsRehash(legacy))
+}
+
+func TestHasher_NeedsRehashOnCostChange(t *testing.T) {
+	hash := NewHasher(testParams).Hash("correct horse")
+
+	stronger := NewHasher(Params{Memory: 2048, Iterations: 2, Parallelism: 1})
+	assert.True(t, stronger.NeedsRehash(hash))
+	// Hashes made with other parameters still validate
+	assert.NoError(t, stronger.Validate("correct horse", hash))
+}
+
+func TestHasher_InvalidHash(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	assert.ErrorIs(t, hasher.Validate("correct horse", "not a hash"), ErrUnknownFormat)
+	assert.ErrorIs(t, hasher.Validate("correct horse", "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA"), ErrUnknownFormat)
+	assert.True(t, hasher.NeedsRehash(""))
+}
+
+func TestNewHasher_Defaults(t *testing.T) {
+	hash := NewHasher(Params{}).Hash("correct horse")
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$"))
+}
@@ -0,0 +1,26 @@
+// Package random generates cryptographically secure random values.
+package random
+
+import "crypto/rand"
+
+// Bytes returns n bytes from crypto/rand, which never returns an error since Go 1.24
+func Bytes(n int) []byte {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// String returns n characters drawn uniformly from alphabet, which holds at most 256 single-byte characters
+func String(n int, alphabet string) string {
+	// Bytes at or above limit would favour the first characters of the alphabet, so they are drawn again
+	limit := 256 - 256%len(alphabet)
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		for _, b := range Bytes(n - len(out)) {
+			if int(b) < limit {
+				out = append(out, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,31 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	first := Bytes(32)
+	second := Bytes(32)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+	assert.Empty(t, Bytes(0))
+}
+
+func TestString(t *testing.T) {
+	s := String(3000, "abc")
+
+	assert.Len(t, s, 3000)
+	counts := map[rune]int{}
+	for _, c := range s {
+		counts[c]++
+	}
+	assert.Len(t, counts, 3)
+	for _, count := range counts {
+		// Each symbol is expected 1000 times
+		assert.InDelta(t, 1000, count, 150)
+	}
+}
@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, code(rfcSecret, Step(time.Unix(tt.unix, 0)), 8), tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString(rfcSecret)
+	now := time.Unix(59, 0)
+
+	step, ok := Validate(secret, "287082", now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// Codes of the neighbouring steps are accepted, older ones are not
+	_, ok = Validate(secret, "287082", now.Add(Period))
+	assert.True(t, ok)
+	_, ok = Validate(secret, "287082", now.Add(2*Period))
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "000000", now)
+	assert.False(t, ok)
+	_, ok = Validate(secret, "28708", now)
+	assert.False(t, ok)
+	_, ok = Validate("not base32!", "287082", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret := GenerateSecret()
+	now := time.Now()
+
+	code, err := Code(secret, Step(now))
+	assert.NoError(t, err)
+	_, ok := Validate(secret, code, now)
+	assert.True(t, ok)
+	assert.NotEqual(t, secret, GenerateSecret())
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("jCourse", "admin@sjtu.edu.cn", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/jCourse:admin@sjtu.edu.cn?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=jCourse")
+	assert.Contains(t, uri, "digits=6")
+}